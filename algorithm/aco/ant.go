@@ -2,8 +2,7 @@ package aco
 
 import (
 	"math"
-	"math/rand"
-	"time"
+	"sort"
 
 	"github.com/mukhinaks/fops/generic"
 )
@@ -15,12 +14,30 @@ type Ant struct {
 
 	locations *map[int]generic.Point
 	colony    ACO
+	random    splitMix
 }
 
-func (ant *Ant) Init(locations *map[int]generic.Point, colony ACO) {
-	rand.Seed(time.Now().UnixNano())
+func (ant *Ant) Init(locations *map[int]generic.Point, colony ACO, seed uint64) {
 	ant.locations = locations
 	ant.colony = colony
+	ant.random = splitMix(seed)
+}
+
+// splitMix is a random generator of an ant. It is cheap to seed, so every ant of every iteration
+// gets its own generator seeded by the source of the solve.
+type splitMix uint64
+
+func (s *splitMix) next() uint64 {
+	*s += 0x9e3779b97f4a7c15
+	z := uint64(*s)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Float64 returns a number in [0, 1).
+func (s *splitMix) Float64() float64 {
+	return float64(s.next()>>11) / (1 << 53)
 }
 
 func (ant *Ant) NextLocation() (bool, int) {
@@ -51,7 +68,6 @@ func (ant *Ant) NextLocation() (bool, int) {
 		probability := math.Pow(pheromone, ant.colony.pheromoneControl) * math.Pow(locationScore, ant.colony.attractivenessControl)
 		//if len(probabilities) < 30 {
 		probabilities[key] = probability
-		probabilitiesSum += probability
 		/*
 			} else {
 
@@ -73,7 +89,19 @@ func (ant *Ant) NextLocation() (bool, int) {
 		return false, -1
 	}
 
-	randomNumber := rand.Float64() * probabilitiesSum
+	keys := make([]int, 0, len(probabilities))
+	for key := range probabilities {
+		keys = append(keys, key)
+	}
+	if ant.colony.seed != 0 {
+		// Map iteration order is random, so keys are sorted to make seeded runs reproducible.
+		sort.Ints(keys)
+	}
+	for _, key := range keys {
+		probabilitiesSum += probabilities[key]
+	}
+
+	randomNumber := ant.random.Float64() * probabilitiesSum
 	/*
 		candidate := make(map[int]generic.Point)
 		for key, value := range ant.route {
@@ -82,8 +110,8 @@ func (ant *Ant) NextLocation() (bool, int) {
 	*/
 	cumProbabiltySum := 0.0
 	index := -1
-	for key, prob := range probabilities {
-		cumProbabiltySum += prob
+	for _, key := range keys {
+		cumProbabiltySum += probabilities[key]
 		if cumProbabiltySum >= randomNumber {
 			index = key
 			break
@@ -2,8 +2,9 @@ package aco
 
 import (
 	"math"
-	"runtime"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/mukhinaks/fops/generic"
 )
@@ -18,6 +19,9 @@ type ACO struct {
 	currentIterations     int
 	solver                *generic.Solver
 	numberOfChannels      int //
+	seed                  int64
+	// random is the source of the solve, it seeds ants of every iteration.
+	random *rand.Rand
 }
 
 type Deltas struct {
@@ -28,15 +32,21 @@ type Deltas struct {
 
 func (colony ACO) Init(solver *generic.Solver) generic.PathAlgorithm {
 	colony.solver = solver
-	colony.fadeness = solver.Configuration["Fadeness"].(float64)
-	colony.attractivenessControl = solver.Configuration["AttractivenessControl"].(float64)
-	colony.pheromoneControl = solver.Configuration["PheromoneControl"].(float64)
+	config := solver.Configuration.ACOConfig
+	colony.fadeness = config.Fadeness
+	colony.attractivenessControl = config.AttractivenessControl
+	colony.pheromoneControl = config.PheromoneControl
 
-	colony.iterations = int(solver.Configuration["Iterations"].(float64))
+	colony.iterations = config.Iterations
 	colony.pheromones = make(map[int](map[int]float64))
-	colony.antsNumber = solver.Configuration["AntsNumber"].(float64)
-	colony.numberOfChannels = int(solver.Configuration["NumberOfChannels"].(float64))
-	runtime.GOMAXPROCS(12)
+	colony.antsNumber = config.AntsNumber
+	colony.numberOfChannels = config.NumberOfChannels
+	colony.seed = solver.Configuration.Seed
+	seed := colony.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	colony.random = rand.New(rand.NewSource(seed))
 	return colony
 }
 
@@ -53,37 +63,34 @@ func (colony ACO) CreateRoute() (map[int]generic.Point, []int, float64) {
 
 		iterationPheromones := make([]Deltas, 0)
 
-		sem := make(chan bool, antsNumber)
-		mux := sync.Mutex{}
+		// Ants are processed in fixed order after all of them finish, so seeded runs are reproducible.
+		ants := make([]Ant, antsNumber)
+		wg := sync.WaitGroup{}
 		for k := 0; k < antsNumber; k++ {
-			go func(i int) {
-				ant := Ant{}
-				ant.Init(&candidatesLocations, colony)
-				ant.GetRoute()
-
-				mux.Lock()
-				if ant.score >= bestScore {
-					bestRoute = ant.route
-					bestOrder = ant.keys
-					bestScore = ant.score
-				}
-
-				for key := 0; key < len(ant.keys)-1; key++ {
-					startKey := ant.keys[key]
-					endKey := ant.keys[key+1]
-
-					iterationPheromones = append(iterationPheromones, Deltas{startKey, endKey, ant.score / float64(antsNumber)})
-				}
-				mux.Unlock()
-				sem <- true
+			wg.Add(1)
+			seed := colony.random.Uint64()
+			go func(k int) {
+				defer wg.Done()
+				ants[k].Init(&candidatesLocations, colony, seed)
+				ants[k].GetRoute()
 			}(k)
 		}
+		wg.Wait()
 
-		//t := time.Now()
-		for i := 0; i < antsNumber; i++ {
-			<-sem
+		for _, ant := range ants {
+			if ant.score >= bestScore {
+				bestRoute = ant.route
+				bestOrder = ant.keys
+				bestScore = ant.score
+			}
+
+			for key := 0; key < len(ant.keys)-1; key++ {
+				startKey := ant.keys[key]
+				endKey := ant.keys[key+1]
+
+				iterationPheromones = append(iterationPheromones, Deltas{startKey, endKey, ant.score / float64(antsNumber)})
+			}
 		}
-		//		fmt.Println(time.Since(t))
 
 		colony.UpdatePheromones(iterationPheromones)
 		colony.currentIterations++
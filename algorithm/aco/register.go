@@ -0,0 +1,11 @@
+package aco
+
+import (
+	"github.com/mukhinaks/fops/generic"
+)
+
+func init() {
+	generic.RegisterAlgorithm("aco", func(task generic.Task) (generic.PathAlgorithm, error) {
+		return ACO{}, nil
+	})
+}
@@ -0,0 +1,11 @@
+package rga
+
+import (
+	"github.com/mukhinaks/fops/generic"
+)
+
+func init() {
+	generic.RegisterAlgorithm("rga", func(task generic.Task) (generic.PathAlgorithm, error) {
+		return RGA{}, nil
+	})
+}
@@ -0,0 +1,191 @@
+package fops
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+const maxBatchLineSize = 16 * 1024 * 1024
+
+// BatchRequest is a single line of JSONL batch file: problem specification with optional identifier.
+type BatchRequest struct {
+	ID string `json:"id,omitempty"`
+	Problem
+}
+
+// BatchResult is a single line of batch output.
+type BatchResult struct {
+	ID string `json:"id"`
+	// Line is a line number of the request in batch file.
+	Line      int                  `json:"line"`
+	Route     []generic.LocationID `json:"route"`
+	Score     float64              `json:"score"`
+	RouteTime int                  `json:"route_time"`
+	// Runtime is solving time in seconds.
+	Runtime float64 `json:"runtime"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// BatchOptions control batch solving.
+type BatchOptions struct {
+	// Parallelism is a number of problems solved at the same time, number of CPUs is used if it is not positive.
+	Parallelism int
+	// Defaults provide configuration path, dataset, algorithm, seed and settings for requests where they are not set.
+	Defaults Problem
+}
+
+// BatchSummary counts processed requests.
+type BatchSummary struct {
+	Total  int
+	Failed int
+}
+
+type batchJob struct {
+	line    int
+	request BatchRequest
+	err     error
+}
+
+// SolveBatch reads problems from JSONL input, solves them concurrently and writes one result line per request.
+// Results are written in order of completion.
+// Errors of single requests are reported in results and counted in summary,
+// returned error is related to input or output only.
+func SolveBatch(ctx context.Context, input io.Reader, output io.Writer, options BatchOptions) (BatchSummary, error) {
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	jobs := make(chan batchJob)
+	results := make(chan BatchResult)
+
+	workers := sync.WaitGroup{}
+	for i := 0; i < parallelism; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				results <- solveBatchJob(ctx, job)
+			}
+		}()
+	}
+
+	summary := BatchSummary{}
+	writeErr := make(chan error, 1)
+	go func() {
+		encoder := json.NewEncoder(output)
+		var err error
+		for result := range results {
+			summary.Total++
+			if result.Error != "" {
+				summary.Failed++
+			}
+			if err == nil {
+				err = encoder.Encode(result)
+			}
+		}
+		writeErr <- err
+	}()
+
+	readErr := readBatch(ctx, input, options.Defaults, jobs)
+	close(jobs)
+	workers.Wait()
+	close(results)
+
+	if err := <-writeErr; err != nil {
+		return summary, err
+	}
+	return summary, readErr
+}
+
+func readBatch(ctx context.Context, input io.Reader, defaults Problem, jobs chan<- batchJob) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		job := batchJob{line: line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		job.err = decoder.Decode(&job.request)
+		if job.request.ID == "" {
+			job.request.ID = strconv.Itoa(line)
+		}
+		job.request.Problem = withDefaults(job.request.Problem, defaults)
+
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+func solveBatchJob(ctx context.Context, job batchJob) BatchResult {
+	result := BatchResult{
+		ID:   job.request.ID,
+		Line: job.line,
+	}
+	if job.err != nil {
+		result.Error = job.err.Error()
+		return result
+	}
+
+	t := time.Now()
+	itinerary, err := Solve(ctx, job.request.Problem)
+	result.Runtime = time.Since(t).Seconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Route = itinerary.IDs
+	result.Score = itinerary.Score
+	result.RouteTime = itinerary.RouteTime
+	return result
+}
+
+func withDefaults(problem Problem, defaults Problem) Problem {
+	if problem.ConfigPath == "" {
+		problem.ConfigPath = defaults.ConfigPath
+	}
+	if problem.DataPath == "" {
+		problem.DataPath = defaults.DataPath
+	}
+	if problem.Algorithm == "" {
+		problem.Algorithm = defaults.Algorithm
+	}
+	if problem.Seed == 0 {
+		problem.Seed = defaults.Seed
+	}
+	if problem.TravelMode == "" {
+		problem.TravelMode = defaults.TravelMode
+	}
+	if len(defaults.Settings) > 0 {
+		settings := make(map[string]string)
+		for key, value := range defaults.Settings {
+			settings[key] = value
+		}
+		for key, value := range problem.Settings {
+			settings[key] = value
+		}
+		problem.Settings = settings
+	}
+	return problem
+}
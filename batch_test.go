@@ -0,0 +1,138 @@
+package fops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testCity writes configuration and dataset of 6 locations in a square of 1 km with ids 10..15 to a
+// temporary directory, it returns path of configuration and a function which removes the directory.
+func testCity(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "fops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hours := `{"0": [0, 2400], "1": [0, 2400], "2": [0, 2400], "3": [0, 2400], "4": [0, 2400], "5": [0, 2400], "6": [0, 2400]}`
+	locations := make([]string, 0)
+	for i := 0; i < 6; i++ {
+		locations = append(locations, fmt.Sprintf(`{"id": %d, "title": "Location %d", "lat": %f, "lng": %f, `+
+			`"duration": 20, "category": ["Sights & Landmarks"], "instagram_visitorsNumber": %d, "open_hours": %s}`,
+			10+i, i, 55.75+0.003*float64(i%3), 37.62+0.005*float64(i/3), 100*(i+1), hours))
+	}
+	files := map[string]string{
+		"city.json":   "[" + strings.Join(locations, ",\n") + "]",
+		"config.json": `{"DataPath": "` + filepath.Join(dir, "city.json") + `", "Iterations": 3, "Seed": 1}`,
+	}
+	for name, text := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "config.json"), func() { os.RemoveAll(dir) }
+}
+
+func TestSolveBatch(t *testing.T) {
+	config, remove := testCity(t)
+	defer remove()
+	input := strings.Join([]string{
+		`{"id": "first", "kind": "op", "start_id": 10, "end_id": 15, "time_limit": 120}`,
+		`{"kind": "op", "start_id": 10, "end_id": 15, "time_limit": 120`,
+		`{"id": "unknown", "kind": "op", "start_id": 99, "end_id": 15, "time_limit": 120}`,
+		``,
+		`{"id": "field", "kind": "op", "start": 10}`,
+		`{"id": "last", "kind": "op", "start_id": 11, "end_id": 11, "time_limit": 60}`,
+	}, "\n")
+	type line struct {
+		id    string
+		line  int
+		error string
+	}
+	want := []line{
+		{"first", 1, ""},
+		{"2", 2, "unexpected EOF"},
+		{"unknown", 3, `start location "99" is not in dataset`},
+		{"field", 5, `unknown field "start"`},
+		{"last", 6, ""},
+	}
+
+	for _, parallelism := range []int{1, 4} {
+		var output bytes.Buffer
+		summary, err := SolveBatch(context.Background(), strings.NewReader(input), &output,
+			BatchOptions{Parallelism: parallelism, Defaults: Problem{ConfigPath: config}})
+		if err != nil {
+			t.Fatalf("parallelism %d: unexpected error %v", parallelism, err)
+		}
+		if summary != (BatchSummary{Total: 5, Failed: 3}) {
+			t.Errorf("parallelism %d: summary %+v, want 5 requests and 3 failures", parallelism, summary)
+		}
+
+		results := make([]BatchResult, 0)
+		decoder := json.NewDecoder(&output)
+		for decoder.More() {
+			var result BatchResult
+			if err := decoder.Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, result)
+		}
+		// results are written in order of completion, a single worker completes them in order of lines
+		if parallelism > 1 {
+			sort.Slice(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+		}
+		if len(results) != len(want) {
+			t.Fatalf("parallelism %d: got %d results, want %d", parallelism, len(results), len(want))
+		}
+		for i, result := range results {
+			if result.ID != want[i].id || result.Line != want[i].line || !strings.Contains(result.Error, want[i].error) ||
+				(want[i].error == "" && result.Error != "") {
+				t.Errorf("parallelism %d: result %d is %s line %d error %q, want %+v",
+					parallelism, i, result.ID, result.Line, result.Error, want[i])
+			}
+			if result.Error == "" && (len(result.Route) < 2 || result.Score <= 0) {
+				t.Errorf("parallelism %d: result %s has route %v and score %v", parallelism, result.ID, result.Route, result.Score)
+			}
+		}
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	defaults := Problem{ConfigPath: "config.json", DataPath: "city.json", Algorithm: RGA, Seed: 7,
+		TravelMode: "cycling", Settings: map[string]string{"Iterations": "10", "Fadeness": "0.2"}}
+	tests := []struct {
+		name    string
+		problem Problem
+		want    Problem
+	}{
+		{"empty", Problem{Kind: OP}, Problem{Kind: OP, ConfigPath: "config.json", DataPath: "city.json",
+			Algorithm: RGA, Seed: 7, TravelMode: "cycling", Settings: map[string]string{"Iterations": "10", "Fadeness": "0.2"}}},
+		{"set", Problem{Kind: OP, ConfigPath: "other.json", DataPath: "other.csv", Algorithm: ACO, Seed: 3,
+			TravelMode: "walking", Settings: map[string]string{"Iterations": "2", "AntsNumber": "2"}},
+			Problem{Kind: OP, ConfigPath: "other.json", DataPath: "other.csv", Algorithm: ACO, Seed: 3,
+				TravelMode: "walking", Settings: map[string]string{"Iterations": "2", "Fadeness": "0.2", "AntsNumber": "2"}}},
+	}
+	for _, test := range tests {
+		if got := withDefaults(test.problem, defaults); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: withDefaults = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+
+	// settings of defaults are copied, so problems do not share them
+	problem := withDefaults(Problem{}, defaults)
+	problem.Settings["Iterations"] = "1"
+	if defaults.Settings["Iterations"] != "10" {
+		t.Error("withDefaults shares settings of defaults")
+	}
+	if got := withDefaults(Problem{Seed: 5}, Problem{}); !reflect.DeepEqual(got, Problem{Seed: 5}) {
+		t.Errorf("withDefaults of empty defaults = %+v", got)
+	}
+}
@@ -0,0 +1,695 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mukhinaks/fops"
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/merge"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/osm"
+	"github.com/mukhinaks/fops/points"
+)
+
+// solverFlags are shared by commands which run the solver.
+type solverFlags struct {
+	config    string
+	data      string
+	algorithm string
+	seed      int64
+	travel    string
+	settings  settingsFlag
+}
+
+// settingsFlag collects repeated Field=Value configuration overrides.
+type settingsFlag map[string]string
+
+func (s settingsFlag) String() string {
+	items := make([]string, 0, len(s))
+	for key, value := range s {
+		items = append(items, key+"="+value)
+	}
+	return strings.Join(items, ",")
+}
+
+func (s settingsFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("expected Field=Value, got %q", value)
+	}
+	s[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	return nil
+}
+
+func (f *solverFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.config, "config", "", "path to solver configuration (default config.json)")
+	flags.StringVar(&f.data, "data", "", "path to dataset, replaces DataPath from configuration")
+	flags.StringVar(&f.algorithm, "algorithm", "", "path algorithm: ACO or RGA (default ACO)")
+	flags.Int64Var(&f.seed, "seed", 0, "random seed for reproducible runs, 0 means random seed")
+	flags.StringVar(&f.travel, "travel", "", "travel model: walking, cycling or driving, replaces TravelMode from configuration")
+	f.settings = make(settingsFlag)
+	flags.Var(f.settings, "set", "configuration override Field=Value, can be repeated")
+}
+
+func (f solverFlags) problem() fops.Problem {
+	return fops.Problem{
+		ConfigPath: f.config,
+		DataPath:   f.data,
+		Algorithm:  strings.ToUpper(f.algorithm),
+		Seed:       f.seed,
+		TravelMode: f.travel,
+		Settings:   f.settings,
+	}
+}
+
+// parseFlags parses command flags, if command should not be run it returns exit code and true.
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	flags.SetOutput(os.Stderr)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, true
+		}
+		return exitUsage, true
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "fops %s: unexpected arguments %v\n", flags.Name(), flags.Args())
+		return exitUsage, true
+	}
+	return exitOK, false
+}
+
+// parseIDs splits comma separated location IDs.
+func parseIDs(value string) []generic.LocationID {
+	ids := make([]generic.LocationID, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ids = append(ids, generic.LocationID(item))
+		}
+	}
+	return ids
+}
+
+func parseInts(value string) ([]int, error) {
+	ids := make([]int, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func runSolve(args []string) int {
+	flags := flag.NewFlagSet("solve", flag.ContinueOnError)
+	common := solverFlags{}
+	common.register(flags)
+	spec := flags.String("spec", "", "problem specification file (JSON or YAML)")
+	kind := flags.String("kind", "", "problem kind: op, opcv, optw, tdop, opfp or citybrand")
+	pointsName := flags.String("points", "", "registered points, replaces default points of the problem kind")
+	scoreName := flags.String("score", "", "registered score, replaces default score of the problem kind")
+	constraintsName := flags.String("constraints", "", "registered constraints, replaces default constraints of the problem kind")
+	startID := flags.String("start", "", "start location ID")
+	endID := flags.String("end", "", "end location ID")
+	compulsory := flags.String("compulsory", "", "comma separated compulsory location IDs")
+	forbidden := flags.String("forbidden", "", "comma separated forbidden location IDs")
+	allowedAreas := flags.String("allowed-areas", "", "GeoJSON polygons, locations outside of them are not visited")
+	forbiddenAreas := flags.String("forbidden-areas", "", "GeoJSON polygons, locations inside of them are not visited")
+	crossingPenalty := flags.Float64("crossing-penalty", 0, "score penalty for every leg crossing forbidden areas")
+	diversityPenalty := flags.Float64("diversity-penalty", 0, "score penalty for every repeated category of the route")
+	include := flags.String("include", "", "filter expression, only matching locations are visited")
+	exclude := flags.String("exclude", "", "filter expression, matching locations are not visited")
+	timeLimit := flags.Int("time-limit", 0, "route time budget in minutes")
+	startTime := flags.Int("start-time", 0, "start time in HHMM format")
+	day := flags.String("day", "", "day of week, 0 is Sunday")
+	days := flags.Int("days", 0, "number of days for OPCV")
+	output := flags.String("output", "", "output file, standard output is used if empty")
+	format := flags.String("format", "", "output format: json, csv, geojson or text (default json for files and text for standard output)")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	problem := fops.Problem{}
+	if *spec != "" {
+		var err error
+		problem, err = fops.LoadProblem(*spec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+	}
+
+	defaults := common.problem()
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "config":
+			problem.ConfigPath = defaults.ConfigPath
+		case "data":
+			problem.DataPath = defaults.DataPath
+		case "algorithm":
+			problem.Algorithm = defaults.Algorithm
+		case "seed":
+			problem.Seed = defaults.Seed
+		case "travel":
+			problem.TravelMode = defaults.TravelMode
+		case "set":
+			if problem.Settings == nil {
+				problem.Settings = make(map[string]string)
+			}
+			for key, value := range defaults.Settings {
+				problem.Settings[key] = value
+			}
+		case "kind":
+			problem.Kind = *kind
+		case "points":
+			problem.Points = *pointsName
+		case "score":
+			problem.Score = *scoreName
+		case "constraints":
+			problem.Constraints = *constraintsName
+		case "start":
+			problem.StartID = generic.LocationID(*startID)
+		case "end":
+			problem.EndID = generic.LocationID(*endID)
+		case "compulsory":
+			problem.CompulsoryLocations = parseIDs(*compulsory)
+		case "forbidden":
+			problem.ForbiddenLocations = parseIDs(*forbidden)
+		case "allowed-areas":
+			problem.AllowedAreas = *allowedAreas
+		case "forbidden-areas":
+			problem.ForbiddenAreas = *forbiddenAreas
+		case "crossing-penalty":
+			problem.CrossingPenalty = *crossingPenalty
+		case "diversity-penalty":
+			problem.DiversityPenalty = *diversityPenalty
+		case "include":
+			problem.Include = *include
+		case "exclude":
+			problem.Exclude = *exclude
+		case "time-limit":
+			problem.TimeLimit = *timeLimit
+		case "start-time":
+			problem.StartTime = *startTime
+		case "day":
+			problem.DayOfWeek = fops.Weekday(*day)
+		case "days":
+			problem.Days = *days
+		}
+	})
+	if problem.Kind == "" && problem.Constraints == "" {
+		fmt.Fprintln(os.Stderr, "fops solve: problem kind is not specified, use -spec, -kind or -constraints")
+		return exitUsage
+	}
+
+	// output of specification is written like -output, so the route is written once
+	if *output == "" {
+		*output = problem.OutputPath
+	}
+	problem.OutputPath = ""
+	if *format == "" {
+		*format = "text"
+		if *output != "" {
+			*format = "json"
+		}
+	}
+	if !isRouteFormat(*format) {
+		fmt.Fprintf(os.Stderr, "fops solve: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	itinerary, err := fops.Solve(context.Background(), problem)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	if err := writeOutput(*output, func(w io.Writer) error {
+		return writeItinerary(w, itinerary, *format)
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func runBatch(args []string) int {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	common := solverFlags{}
+	common.register(flags)
+	input := flags.String("input", "-", "JSONL file with one problem per line, - for standard input")
+	output := flags.String("output", "-", "JSONL file for results, - for standard output")
+	parallel := flags.Int("parallel", 0, "number of problems solved at the same time (default number of CPUs)")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	reader := io.Reader(os.Stdin)
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var summary fops.BatchSummary
+	target := *output
+	if target == "-" {
+		target = ""
+	}
+	err := writeOutput(target, func(w io.Writer) error {
+		var err error
+		summary, err = fops.SolveBatch(context.Background(), reader, w, fops.BatchOptions{
+			Parallelism: *parallel,
+			Defaults:    common.problem(),
+		})
+		return err
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	fmt.Fprintf(os.Stderr, "solved %d of %d requests\n", summary.Total-summary.Failed, summary.Total)
+	if summary.Failed > 0 {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func runBenchmark(args []string) int {
+	flags := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	problems := flags.String("problems", "op,opcv,optw,tdop,opfp", "comma separated problem kinds")
+	algorithm := flags.String("algorithm", fops.RGA, "path algorithm: ACO or RGA")
+	sizes := flags.String("sizes", "10,50,100,500,1000,5000", "comma separated sizes of sample datasets")
+	launches := flags.Int("launches", 50, "number of launches of each problem")
+	output := flags.String("output", "benchmarks", "output folder")
+	seed := flags.Int64("seed", 0, "random seed for reproducible runs, 0 means random seed")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	alg := strings.ToUpper(*algorithm)
+	if alg != fops.ACO && alg != fops.RGA {
+		fmt.Fprintf(os.Stderr, "fops benchmark: unknown algorithm %q\n", *algorithm)
+		return exitUsage
+	}
+	datasetSizes, err := parseInts(*sizes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fops benchmark:", err)
+		return exitUsage
+	}
+
+	failed := ExperimentCompareProblemSolvingTime(strings.Split(*problems, ","), alg, datasetSizes, *output, *launches, *seed)
+	if failed > 0 {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func runBenchmarkTravel(args []string) int {
+	flags := flag.NewFlagSet("benchmark-travel", flag.ContinueOnError)
+	sizes := flags.String("sizes", "10,50,100,500,1000,5000", "comma separated sizes of sample datasets")
+	queries := flags.Int("queries", 1000000, "number of random travel time queries")
+	travelMode := flags.String("travel", "", "travel model, replaces TravelMode from configuration")
+	seed := flags.Int64("seed", 0, "random seed for reproducible runs, 0 means random seed")
+	settings := make(settingsFlag)
+	flags.Var(settings, "set", "configuration override Field=Value, can be repeated")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	datasetSizes, err := parseInts(*sizes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fops benchmark-travel:", err)
+		return exitUsage
+	}
+	if *queries <= 0 {
+		fmt.Fprintln(os.Stderr, "fops benchmark-travel: number of queries should be positive")
+		return exitUsage
+	}
+	if *travelMode != "" {
+		settings["TravelMode"] = *travelMode
+	}
+
+	failed, err := ExperimentTravelMatrix(datasetSizes, settings, *queries, *seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fops benchmark-travel:", err)
+		return exitUsage
+	}
+	if failed > 0 {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func runTune(args []string) int {
+	flags := flag.NewFlagSet("tune", flag.ContinueOnError)
+	problems := flags.String("problems", "op,opcv,optw,tdop,opfp", "comma separated problem kinds")
+	launches := flags.Int("launches", 50, "number of launches of each problem")
+	output := flags.String("output", "benchmarks", "output folder")
+	seed := flags.Int64("seed", 0, "random seed for reproducible runs, 0 means random seed")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	failed := ExperimentOptimizeIterationAndAnts(strings.Split(*problems, ","), *output, *launches, *seed)
+	if failed > 0 {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func runValidateData(args []string) int {
+	flags := flag.NewFlagSet("validate-data", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to solver configuration (default config.json)")
+	data := flags.String("data", "", "path to dataset, replaces DataPath from configuration")
+	kind := flags.String("kind", fops.OP, "problem kind which defines dataset schema")
+	day := flags.String("day", "", "day of week (0 is Sunday) which locations of optw and citybrand should be open")
+	output := flags.String("output", "", "write cleaned dataset as JSON to the file")
+	settings := make(settingsFlag)
+	flags.Var(settings, "set", "configuration override Field=Value, can be repeated")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+
+	if d, err := strconv.Atoi(*day); *day != "" && (err != nil || d < 0 || d > 6) {
+		fmt.Fprintf(os.Stderr, "day should be from 0 (Sunday) to 6, got %q\n", *day)
+		return exitUsage
+	}
+	problemKind := strings.ToLower(*kind)
+	knownKind := false
+	for _, k := range fops.Kinds {
+		knownKind = knownKind || problemKind == k
+	}
+	if !knownKind {
+		fmt.Fprintf(os.Stderr, "fops validate-data: unknown problem kind %q, kinds: %s\n", *kind, strings.Join(fops.Kinds, ", "))
+		return exitUsage
+	}
+
+	overrides := make(map[string]string)
+	for key, value := range settings {
+		overrides[key] = value
+	}
+	if *data != "" {
+		overrides["DataPath"] = *data
+	}
+	config, err := misc.LoadConfig(*configPath, overrides)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	schema := points.Schema{
+		OpenHours:  problemKind == fops.OPTW || problemKind == fops.CityBrand,
+		DayOfWeek:  *day,
+		Categories: problemKind == fops.CityBrand,
+	}
+	report, err := points.ValidateDataset(config.DataConfig, problemKind == fops.CityBrand, schema)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%s: %d locations, %d errors, %d warnings\n", config.DataPath, report.Locations,
+		report.Count(points.Error), report.Count(points.Warning))
+	fields := make([]string, 0, len(report.Imputed))
+	for field := range report.Imputed {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Printf("%s: %d imputed values\n", field, report.Imputed[field])
+	}
+	categories := make([]string, 0, len(report.Unmapped))
+	for category := range report.Unmapped {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		fmt.Printf("%q: %d locations, category is not in taxonomy\n", category, report.Unmapped[category])
+	}
+	if *output != "" {
+		if err := writeOutput(*output, report.WriteCleaned); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		fmt.Printf("%s: %d locations\n", *output, report.Cleaned())
+	}
+	if report.Count(points.Error) > 0 {
+		return exitPartial
+	}
+	return exitOK
+}
+
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	input := flags.String("input", "", "route or dataset JSON file")
+	output := flags.String("output", "", "output file, standard output is used if empty")
+	format := flags.String("format", "geojson", "output format: json, csv or geojson")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "fops export: input file is not specified")
+		return exitUsage
+	}
+	if !isRouteFormat(*format) || *format == "text" {
+		fmt.Fprintf(os.Stderr, "fops export: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	records, err := readRecords(*input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	if err := writeOutput(*output, func(w io.Writer) error {
+		return writeRecords(w, records, *format)
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func runImportOSM(args []string) int {
+	flags := flag.NewFlagSet("import-osm", flag.ContinueOnError)
+	input := flags.String("input", "", "OpenStreetMap extract, .osm XML or .osm.pbf")
+	output := flags.String("output", "", "dataset file, standard output is used if empty")
+	format := flags.String("format", "json", "dataset format: json, csv or geojson")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "fops import-osm: input file is not specified")
+		return exitUsage
+	}
+	if !isRouteFormat(*format) || *format == "text" {
+		fmt.Fprintf(os.Stderr, "fops import-osm: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	imported, err := osm.ImportPOIs(*input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	records := make([]map[string]interface{}, len(imported.POIs))
+	counts := make(map[string]int)
+	for i, poi := range imported.POIs {
+		records[i] = map[string]interface{}{
+			"id":         poi.ID,
+			"title":      poi.Title,
+			"lat":        poi.Lat,
+			"lng":        poi.Lng,
+			"address":    poi.Address,
+			"category":   poi.Categories,
+			"duration":   poi.Duration,
+			"open_hours": poi.OpenHours,
+		}
+		counts[poi.Categories[0]]++
+	}
+
+	if err := writeOutput(*output, func(w io.Writer) error {
+		return writeRecords(w, records, *format)
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	fmt.Fprintf(os.Stderr, "%s: %d locations\n", *input, len(records))
+	for _, category := range categories {
+		fmt.Fprintf(os.Stderr, "  %s: %d\n", category, counts[category])
+	}
+	if len(imported.InvalidHours) > 0 {
+		fmt.Fprintf(os.Stderr, "%d opening_hours are not supported and replaced by open all days, e.g. %q\n",
+			len(imported.InvalidHours), imported.InvalidHours[0])
+	}
+	return exitOK
+}
+
+func runMerge(args []string) int {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	var sourceFlags sourcesFlag
+	flags.Var(&sourceFlags, "source", "dataset JSON of source as name=path, can be repeated")
+	output := flags.String("output", "", "output file, standard output is used if empty")
+	format := flags.String("format", "json", "output format: json, csv or geojson")
+	distance := flags.Float64("distance", 100, "maximum distance between matched records in meters")
+	similarity := flags.Float64("similarity", 0.8, "minimum title similarity of matched records from 0 to 1")
+	precedence := make(settingsFlag)
+	flags.Var(precedence, "precedence", "sources by priority for a field as field=source,source, * is any field, can be repeated")
+	rules := make(settingsFlag)
+	flags.Var(rules, "rule", "combination of a field as field=rule, rules: "+strings.Join(merge.Rules, ", ")+", can be repeated")
+	if code, done := parseFlags(flags, args); done {
+		return code
+	}
+	if len(sourceFlags.names) < 2 {
+		fmt.Fprintln(os.Stderr, "fops merge: at least two sources are required")
+		return exitUsage
+	}
+	if !isRouteFormat(*format) || *format == "text" {
+		fmt.Fprintf(os.Stderr, "fops merge: unknown format %q\n", *format)
+		return exitUsage
+	}
+	sources, err := sourceFlags.read()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	options := merge.Options{
+		MaxDistance:   *distance,
+		MinSimilarity: *similarity,
+		Precedence:    make(map[string][]string),
+		Rules:         rules,
+	}
+	for field, names := range precedence {
+		for _, name := range strings.Split(names, ",") {
+			options.Precedence[field] = append(options.Precedence[field], strings.TrimSpace(name))
+		}
+	}
+	merged, err := merge.Merge(sources, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	records := make([]map[string]interface{}, len(merged))
+	matched := 0
+	for i, record := range merged {
+		records[i] = record.Map()
+		if len(record.Members) > 1 {
+			matched++
+		}
+	}
+	if err := writeOutput(*output, func(w io.Writer) error {
+		return writeRecords(w, records, *format)
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	total := 0
+	for _, source := range sources {
+		total += len(source.Records)
+	}
+	fmt.Fprintf(os.Stderr, "%d records of %d sources merged to %d locations, %d locations have several sources\n",
+		total, len(sources), len(merged), matched)
+	return exitOK
+}
+
+// writeOutput calls write with the file at path or with standard output if path is empty.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// sourcesFlag collects repeated name=path datasets in order of flags, datasets are read by read
+// after flags are parsed.
+type sourcesFlag struct {
+	names []string
+	paths []string
+}
+
+func (s *sourcesFlag) String() string {
+	return strings.Join(s.names, ",")
+}
+
+func (s *sourcesFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return fmt.Errorf("expected name=path, got %q", value)
+	}
+	s.names = append(s.names, strings.TrimSpace(parts[0]))
+	s.paths = append(s.paths, strings.TrimSpace(parts[1]))
+	return nil
+}
+
+// read reads datasets of sources.
+func (s *sourcesFlag) read() ([]merge.Source, error) {
+	sources := make([]merge.Source, len(s.names))
+	for i, name := range s.names {
+		records, err := readRecords(s.paths[i])
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = merge.Source{Name: name, Records: records}
+	}
+	return sources, nil
+}
+
+func writeItinerary(w io.Writer, itinerary fops.Itinerary, format string) error {
+	if format == "text" {
+		fmt.Fprintln(w, "score:", itinerary.Score)
+		fmt.Fprintln(w, "route time:", itinerary.RouteTime)
+		for i, id := range itinerary.IDs {
+			fmt.Fprintf(w, "%3d. [%s] %s\n", i+1, id, itinerary.Locations[i].Name())
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(itinerary.Locations)
+	if err != nil {
+		return err
+	}
+	records := make([]map[string]interface{}, 0)
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+	return writeRecords(w, records, format)
+}
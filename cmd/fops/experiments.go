@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mukhinaks/fops"
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/travel"
+)
+
+// CreateDirIfNotExist creates folders for output
+func CreateDirIfNotExist(dir string) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExperimentCompareProblemSolvingTime conducts experiments on computation time for 5 orienteering problems: OP, OPCV, OPTW, TDOP and OPFP.
+// Sample datasets of given sizes are used. It returns number of failed launches.
+func ExperimentCompareProblemSolvingTime(problems []string, algorithm string, datasetSizes []int, outputFolderName string,
+	numberOfProblemLaunches int, seed int64) int {
+	fmt.Println("--------")
+	fmt.Println(strings.ToUpper("Compare Problem Solving Time"))
+
+	failed := 0
+	for _, problem := range problems {
+		fmt.Println("--------")
+		fmt.Println(strings.ToUpper(problem))
+		for _, datasetSize := range datasetSizes {
+			fmt.Println("Dataset size:", datasetSize)
+			failed += ProblemSolvingTime(problem, algorithm, datasetSize, outputFolderName, numberOfProblemLaunches, seed)
+		}
+		fmt.Println("--------")
+	}
+	fmt.Println("Done")
+	return failed
+}
+
+// ProblemSolvingTime runs specific problem of defined number of times.
+// All resulting routes against with summary of each launch will be written in output folder.
+// It returns number of failed launches.
+func ProblemSolvingTime(problem string, algorithm string, datasetSize int, outputFolderName string, numberOfLaunches int, seed int64) int {
+	CreateDirIfNotExist(outputFolderName)
+	CreateDirIfNotExist(filepath.Join(outputFolderName, algorithm))
+	CreateDirIfNotExist(filepath.Join(outputFolderName, algorithm, problem))
+	fileHandle, _ := os.Create(filepath.Join(outputFolderName, algorithm, "experiment-"+problem+"-"+strconv.Itoa(datasetSize)+".txt"))
+	writer := bufio.NewWriter(fileHandle)
+
+	p, err := experimentProblem(problem)
+	if err != nil {
+		fmt.Println(err)
+		return numberOfLaunches
+	}
+	p.Algorithm = algorithm
+	p.Seed = seed
+	p.ConfigPath = filepath.Join("experiments", "configs", "samples", "config-data-"+strconv.Itoa(datasetSize)+".json")
+
+	failed := 0
+	for i := 0; i < numberOfLaunches; i++ {
+		fileName := "experiment-" + strconv.Itoa(datasetSize) + "-" + strconv.Itoa(i) + ".json"
+		p.OutputPath = filepath.Join(outputFolderName, algorithm, problem, fileName)
+		t := time.Now()
+		itinerary, err := fops.Solve(context.Background(), p)
+		if err != nil {
+			fmt.Println(err)
+			failed++
+			continue
+		}
+		fmt.Fprintln(writer, itinerary.Score, itinerary.RouteTime, time.Since(t))
+	}
+	writer.Flush()
+	return failed
+}
+
+// experimentProblem reads parameters of the problem used in experiments from experiments/specs folder.
+func experimentProblem(problem string) (fops.Problem, error) {
+	return fops.LoadProblem(filepath.Join("experiments", "specs", problem+".json"))
+}
+
+// Old stuff
+func SomeLaunches() {
+
+	/*
+		ExperimentClassicalOPWithReference(solver, "", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, "classic-op-with-ref")
+		ExperimentOPCVMultipleDays(solver, "", []int{152, 3, 106, 105, 51, 63, 9, 127, 157, 158, 11, 13, 5191}, 600, 2, "opcv-multiple-days")
+	*/
+	//ExperimentCityBrand(solver, "", 720, 1000, 0, "Moscow")
+}
+
+// ExperimentOptimizeIterationAndAnts conducts experiments on ACO quality for different number of iterations and ants.
+// It returns number of failed launches.
+func ExperimentOptimizeIterationAndAnts(problems []string, outputFolderName string, numberOfProblemLaunches int, seed int64) int {
+	fmt.Println("--------")
+	fmt.Println(strings.ToUpper("Experiment Optimize Iteration And Ants"))
+
+	iterations := []int{10, 20, 50, 100, 200}
+	ants := []string{"0.1", "0.25", "0.5", "1", "2"}
+
+	failed := 0
+	for _, problem := range problems {
+		fmt.Println("--------")
+		fmt.Println(strings.ToUpper(problem))
+		for _, iteration := range iterations {
+			fmt.Println("Number of iterations:", iteration)
+			for _, ant := range ants {
+				fmt.Println("Number of ants:", ant)
+				failed += ProblemSolvingIterationsAndAntsTest(problem, iteration, ant, outputFolderName, numberOfProblemLaunches, seed)
+			}
+		}
+		fmt.Println("--------")
+	}
+	fmt.Println("Done")
+	return failed
+}
+
+// ProblemSolvingIterationsAndAntsTest runs specific problem of defined number of times with ACO configuration
+// for given number of iterations and ants. It returns number of failed launches.
+func ProblemSolvingIterationsAndAntsTest(problem string, iterations int, ants string, outputFolderName string, numberOfLaunches int, seed int64) int {
+	folder := "iterations"
+	CreateDirIfNotExist(outputFolderName)
+	CreateDirIfNotExist(filepath.Join(outputFolderName, folder))
+	CreateDirIfNotExist(filepath.Join(outputFolderName, folder, problem))
+
+	fileHandle, _ := os.Create(filepath.Join(outputFolderName, folder, "experiment-"+problem+"-iterations-"+strconv.Itoa(iterations)+"-ants-"+ants+".txt"))
+	writer := bufio.NewWriter(fileHandle)
+
+	configPath := filepath.Join("experiments", "configs", "iterations-ants", "config-iterations-"+strconv.Itoa(iterations)+"-ants-"+ants+".json")
+
+	p, err := experimentProblem(problem)
+	if err != nil {
+		fmt.Println(err)
+		return numberOfLaunches
+	}
+	p.Algorithm = fops.ACO
+	p.Seed = seed
+	p.ConfigPath = configPath
+
+	failed := 0
+	for i := 0; i < numberOfLaunches; i++ {
+		fileName := "experiment-" + problem + "-iterations-" + strconv.Itoa(iterations) + "-ants-" + ants + "-" + strconv.Itoa(i) + ".json"
+		p.OutputPath = filepath.Join(outputFolderName, folder, problem, fileName)
+		t := time.Now()
+		itinerary, err := fops.Solve(context.Background(), p)
+		if err != nil {
+			fmt.Println(err)
+			failed++
+			continue
+		}
+		fmt.Fprintln(writer, itinerary.Score, itinerary.RouteTime, time.Since(t))
+	}
+	writer.Flush()
+	return failed
+}
+
+// errNoMatrix tells that travel times of the travel model are not precomputed, e.g. of time-dependent models.
+var errNoMatrix = errors.New("travel model has no travel time matrix, time-dependent models are not precomputed")
+
+// ExperimentTravelMatrix compares speed and memory of travel times computed by travel model and precomputed
+// in travel time matrix on sample datasets. It returns number of failed dataset sizes, it stops with
+// errNoMatrix if travel model has no matrix.
+func ExperimentTravelMatrix(datasetSizes []int, overrides map[string]string, numberOfQueries int, seed int64) (int, error) {
+	fmt.Println("--------")
+	fmt.Println(strings.ToUpper("Compare Travel Time Matrix"))
+	fmt.Println("size\tprepare\tmatrix MB\theap MB\tmodel ns/query\tmatrix ns/query")
+
+	failed := 0
+	for _, datasetSize := range datasetSizes {
+		err := TravelMatrixTest(datasetSize, overrides, numberOfQueries, seed)
+		if err == errNoMatrix {
+			return failed, err
+		}
+		if err != nil {
+			fmt.Println(err)
+			failed++
+		}
+	}
+	fmt.Println("Done")
+	return failed, nil
+}
+
+// TravelMatrixTest prepares travel time matrix of sample dataset and measures time of random queries.
+func TravelMatrixTest(datasetSize int, overrides map[string]string, numberOfQueries int, seed int64) error {
+	configPath := filepath.Join("experiments", "configs", "samples", "config-data-"+strconv.Itoa(datasetSize)+".json")
+	config, err := misc.LoadConfig(configPath, overrides)
+	if err != nil {
+		return err
+	}
+	solver := generic.Solver{Configuration: config}
+	locations, err := points.BaseLocations{}.Init(&solver)
+	if err != nil {
+		return err
+	}
+	allPoints := locations.GetAllPoints()
+	solver.Points = locations
+	solver.Projection = generic.ProjectionOf(locations)
+
+	config.TravelMatrix = false
+	model, err := generic.NewTravelModel(config.TravelMode, config)
+	if err != nil {
+		return err
+	}
+	if prepared, ok := model.(generic.PreparedTravelModel); ok {
+		if err := prepared.Prepare(&solver); err != nil {
+			return err
+		}
+	}
+	config.TravelMatrix = true
+	matrixModel, err := generic.NewTravelModel(config.TravelMode, config)
+	if err != nil {
+		return err
+	}
+	matrix, ok := matrixModel.(*travel.Matrix)
+	if !ok {
+		return errNoMatrix
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	t := time.Now()
+	if err := matrix.Prepare(&solver); err != nil {
+		return err
+	}
+	prepare := time.Since(t)
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	_, matrixBytes := matrix.Size()
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	random := rand.New(rand.NewSource(seed))
+	pairs := make([][2]generic.Point, numberOfQueries)
+	for i := range pairs {
+		pairs[i] = [2]generic.Point{allPoints[random.Intn(len(allPoints))], allPoints[random.Intn(len(allPoints))]}
+	}
+	queryTime := func(m generic.TravelModel) float64 {
+		t := time.Now()
+		for _, pair := range pairs {
+			m.TravelTime(pair[0], pair[1])
+		}
+		return float64(time.Since(t).Nanoseconds()) / float64(len(pairs))
+	}
+
+	const megabyte = 1 << 20
+	fmt.Printf("%d\t%v\t%.1f\t%.1f\t%.0f\t%.0f\n", datasetSize, prepare, float64(matrixBytes)/megabyte,
+		(float64(after.HeapAlloc)-float64(before.HeapAlloc))/megabyte, queryTime(model), queryTime(matrix))
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func isRouteFormat(format string) bool {
+	switch format {
+	case "json", "csv", "geojson", "text":
+		return true
+	}
+	return false
+}
+
+// readRecords reads JSON array of locations as generic records, so any dataset schema can be exported.
+func readRecords(path string) ([]map[string]interface{}, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]map[string]interface{}, 0)
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return records, nil
+}
+
+func writeRecords(w io.Writer, records []map[string]interface{}, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(records)
+	case "csv":
+		return writeCSV(w, records)
+	case "geojson":
+		return writeGeoJSON(w, records)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// writeCSV writes records with columns sorted by name. Lists are joined with ";", objects are written as JSON.
+func writeCSV(w io.Writer, records []map[string]interface{}) error {
+	columnSet := make(map[string]bool)
+	for _, record := range records {
+		for key := range record {
+			columnSet[key] = true
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for key := range columnSet {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			value, err := csvValue(record[column])
+			if err != nil {
+				return err
+			}
+			row[i] = value
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := csvValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ";"), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// writeGeoJSON writes records as FeatureCollection of points, "lat" and "lng" fields are used as coordinates.
+func writeGeoJSON(w io.Writer, records []map[string]interface{}) error {
+	features := make([]geoJSONFeature, 0, len(records))
+	for i, record := range records {
+		lat, okLat := record["lat"].(float64)
+		lng, okLng := record["lng"].(float64)
+		if !okLat || !okLng {
+			return fmt.Errorf("record %d has no coordinates", i)
+		}
+
+		properties := make(map[string]interface{})
+		for key, value := range record {
+			if key != "lat" && key != "lng" {
+				properties[key] = value
+			}
+		}
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: []float64{lng, lat}},
+			Properties: properties,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": features,
+	})
+}
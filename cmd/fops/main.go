@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Exit codes of the command line interface.
+const (
+	exitOK = iota
+	// exitFailure means that command could not be completed.
+	exitFailure
+	// exitUsage means invalid command or flags.
+	exitUsage
+	// exitPartial means that command is completed, but some requests or records are invalid.
+	exitPartial
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+var commands = []command{
+	{"solve", "solve a single problem from specification file or flags", runSolve},
+	{"batch", "solve problems from JSONL file concurrently", runBatch},
+	{"benchmark", "compare solving time of problems on sample datasets", runBenchmark},
+	{"benchmark-travel", "compare travel model and precomputed travel time matrix", runBenchmarkTravel},
+	{"tune", "run experiments on number of iterations and ants of ACO", runTune},
+	{"validate-data", "check dataset against schema of problem and clean it", runValidateData},
+	{"export", "convert route or dataset to another format", runExport},
+	{"import-osm", "build dataset from OpenStreetMap extract", runImportOSM},
+	{"merge", "merge datasets of several sources", runMerge},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return exitUsage
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return exitOK
+	}
+	fmt.Fprintf(os.Stderr, "fops: unknown command %q\n", args[0])
+	usage()
+	return exitUsage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: fops <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	width := 0
+	for _, c := range commands {
+		if len(c.name) > width {
+			width = len(c.name)
+		}
+	}
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-*s %s\n", width, c.name, c.description)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run \"fops <command> -h\" for command flags.")
+}
@@ -0,0 +1,44 @@
+package constraints
+
+import (
+	"github.com/mukhinaks/fops/generic"
+)
+
+// CandidateConstraints is a layer over constraints of any problem which does not visit excluded locations,
+// e.g. locations outside of allowed areas of geofence or rejected by filters of the problem.
+type CandidateConstraints struct {
+	generic.Constraints
+	// Excluded are positions of locations which are not visited.
+	Excluded map[int]bool
+}
+
+func (f CandidateConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	constraints, err := f.Constraints.Init(solver)
+	if err != nil {
+		return nil, err
+	}
+	f.Constraints = constraints
+	return f, nil
+}
+
+func (f CandidateConstraints) SinglePointConstraints(location generic.Point, id int) bool {
+	if f.Excluded[id] {
+		return false
+	}
+	return f.Constraints.SinglePointConstraints(location, id)
+}
+
+func (f CandidateConstraints) UpdateConstraint(route map[int]generic.Point, orderOfPoints []int, locations []generic.Point) generic.Constraints {
+	f.Constraints = f.Constraints.UpdateConstraint(route, orderOfPoints, locations)
+	return f
+}
+
+// FinalRouteTime returns route time of wrapped constraints, it is 0 if they do not compute it.
+func (f CandidateConstraints) FinalRouteTime(route map[int]generic.Point, orderOfLocations []int) int {
+	if timer, ok := f.Constraints.(interface {
+		FinalRouteTime(route map[int]generic.Point, orderOfLocations []int) int
+	}); ok {
+		return timer.FinalRouteTime(route, orderOfLocations)
+	}
+	return 0
+}
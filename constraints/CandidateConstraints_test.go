@@ -0,0 +1,60 @@
+package constraints
+
+import (
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+// oddConstraints allow only locations at odd positions, Init and updates count calls.
+type oddConstraints struct {
+	generic.Constraints
+	inits   int
+	updates int
+}
+
+func (c oddConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	c.inits++
+	return c, nil
+}
+
+func (c oddConstraints) SinglePointConstraints(location generic.Point, id int) bool {
+	return id%2 == 1
+}
+
+func (c oddConstraints) UpdateConstraint(route map[int]generic.Point, orderOfPoints []int,
+	locations []generic.Point) generic.Constraints {
+	c.updates++
+	return c
+}
+
+func TestCandidateConstraints(t *testing.T) {
+	f := CandidateConstraints{Constraints: oddConstraints{}, Excluded: map[int]bool{0: true, 3: true}}
+	c, err := f.Init(&generic.Solver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = c.UpdateConstraint(nil, nil, nil)
+	wrapped := c.(CandidateConstraints).Constraints.(oddConstraints)
+	if wrapped.inits != 1 || wrapped.updates != 1 {
+		t.Errorf("wrapped constraints are initialized %d and updated %d times, want once", wrapped.inits, wrapped.updates)
+	}
+	// excluded locations are rejected even if wrapped constraints allow them
+	want := []bool{false, true, false, false, false, true}
+	for id, allowed := range want {
+		if got := c.SinglePointConstraints(&points.BaseLocation{}, id); got != allowed {
+			t.Errorf("SinglePointConstraints(%d) = %v, want %v", id, got, allowed)
+		}
+	}
+
+	// route time is taken from wrapped constraints if they compute it
+	if got := c.(CandidateConstraints).FinalRouteTime(nil, []int{1}); got != 0 {
+		t.Errorf("FinalRouteTime = %d, want 0 without route time of wrapped constraints", got)
+	}
+	timed := CandidateConstraints{Constraints: &OPConstraints{traveler: traveler{Travel: constantTravel(5)}}}
+	route := map[int]generic.Point{1: &points.BaseLocation{Duration: 10}, 2: &points.BaseLocation{Duration: 20}}
+	if got := timed.FinalRouteTime(route, []int{1, 2}); got != 10+5+20 {
+		t.Errorf("FinalRouteTime = %d, want %d of wrapped constraints", got, 10+5+20)
+	}
+}
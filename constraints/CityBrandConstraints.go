@@ -3,42 +3,57 @@ package constraints
 import (
 	"github.com/mukhinaks/fops/generic"
 	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/query"
 )
 
 // CityBrandConstraints implements Constraint interface for solving orienteering problem with time windows
 type CityBrandConstraints struct {
+	traveler
+
 	TimeLimit          int
 	StartID            int
-	StartLocation      points.CityBrandLocation
+	StartLocation      generic.Point
 	StartTime          int
 	DayOfWeek          int
 	ForbiddenLocations []int
+	// Restaurants are not visited, they are chosen by RestarauntsConstraints.
+	Restaurants *query.Expression
 }
 
-func (f *CityBrandConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.CityBrandLocation)
+func (f *CityBrandConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckPointIndex("start", f.StartID, locs); err != nil {
+		return nil, err
+	}
+	start := locs[f.StartID]
+	restaurants, err := points.RestaurantsOf(solver.Points)
+	if err != nil {
+		return nil, err
+	}
 
 	f.StartLocation = start
+	f.Restaurants = restaurants
 
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f *CityBrandConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route == nil {
-		duration = f.StartLocation.Duration
+		duration = f.StartLocation.VisitDuration()
 	} else {
-		loc := route[orderOfLocations[0]].(points.CityBrandLocation)
-		duration = f.StartLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.CityBrandLocation), route[orderOfLocations[i+1]].(points.CityBrandLocation))
-			duration += route[key].(points.CityBrandLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.CityBrandLocation).Duration
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	}
 
@@ -69,10 +84,10 @@ func (f *CityBrandConstraints) FinalRouteTime(route map[int]generic.Point, order
 	}
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		walkTime := points.WalkingTime(route[key].(points.CityBrandLocation), route[orderOfLocations[i+1]].(points.CityBrandLocation))
-		duration += route[key].(points.CityBrandLocation).Duration + int(walkTime)
+		walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+		duration += route[key].VisitDuration() + int(walkTime)
 	}
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.CityBrandLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	return duration
 }
@@ -83,8 +98,8 @@ func (f *CityBrandConstraints) Boundary(route map[int]generic.Point, orderOfLoca
 		return false
 	}
 
-	time := f.timeUpdate(f.StartTime, f.StartLocation.Duration)
-	time = f.timeUpdate(time, points.WalkingTime(f.StartLocation, route[orderOfLocations[0]].(points.CityBrandLocation)))
+	time := f.timeUpdate(f.StartTime, f.StartLocation.VisitDuration())
+	time = f.timeUpdate(time, f.travelTime(f.StartLocation, route[orderOfLocations[0]]))
 	return true
 }
 
@@ -103,13 +118,7 @@ func (f *CityBrandConstraints) SinglePointConstraints(location generic.Point, id
 		return false
 	}
 
-	loc := location.(points.CityBrandLocation)
-	for _, category := range loc.Categories {
-		if category == "Restaurant" {
-			return false
-		}
-	}
-	return true
+	return !f.Restaurants.Match(location)
 }
 
 func (f *CityBrandConstraints) UpdateConstraint(route map[int]generic.Point, orderOfPoints []int, locations []generic.Point) generic.Constraints {
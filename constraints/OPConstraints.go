@@ -2,44 +2,50 @@ package constraints
 
 import (
 	"github.com/mukhinaks/fops/generic"
-	"github.com/mukhinaks/fops/points"
 )
 
 type OPConstraints struct {
+	traveler
+
 	TimeLimit     int
 	StartID       int
 	EndID         int
-	StartLocation points.BaseLocation
-	EndLocation   points.BaseLocation
+	StartLocation generic.Point
+	EndLocation   generic.Point
 }
 
-func (f *OPConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+func (f *OPConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
+	start := locs[f.StartID]
+	end := locs[f.EndID]
 
 	f.StartLocation = start
 	f.EndLocation = end
 
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f *OPConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route == nil {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, f.EndLocation)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, f.EndLocation)
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)) +
-			route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]]) +
+			route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	}
 	return duration
@@ -51,15 +57,15 @@ func (f *OPConstraints) FinalRouteTime(route map[int]generic.Point, orderOfLocat
 		return 0
 	}
 	if len(orderOfLocations) == 1 {
-		return route[orderOfLocations[0]].(points.BaseLocation).Duration
+		return route[orderOfLocations[0]].VisitDuration()
 	}
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-		duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+		walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+		duration += route[key].VisitDuration() + int(walkTime)
 	}
 
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	return duration
 }
@@ -75,15 +81,15 @@ func (f *OPConstraints) Boundary(route map[int]generic.Point, orderOfLocations [
 
 func (f *OPConstraints) ReducePoints(route map[int]generic.Point, orderOfLocations []int, locations map[int]generic.Point) map[int]generic.Point {
 	/*
-		currentRouteTime := f.FinalRouteTime(route, orderOfLocations) + f.StartLocation.Duration + f.EndLocation.Duration
+		currentRouteTime := f.FinalRouteTime(route, orderOfLocations) + f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration()
 		if len(orderOfLocations) != 0 {
-			currentRouteTime += points.WalkingTime(f.StartLocation, route[orderOfLocations[0]].(points.BaseLocation))
+			currentRouteTime += f.travelTime(f.StartLocation, route[orderOfLocations[0]])
 		}
 		filteredLocations := make(map[int]generic.Point)
 		for i, location := range locations {
-			time := currentRouteTime + location.(points.BaseLocation).Duration + points.WalkingTime(f.EndLocation, location.(points.BaseLocation))
+			time := currentRouteTime + location.VisitDuration() + f.travelTime(f.EndLocation, location)
 			if len(orderOfLocations) != 0 {
-				time += points.WalkingTime(location.(points.BaseLocation), route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation))
+				time += f.travelTime(location, route[orderOfLocations[len(orderOfLocations)-1]])
 			}
 
 			if time <= f.TimeLimit {
@@ -101,8 +107,8 @@ func (f *OPConstraints) SinglePointConstraints(location generic.Point, id int) b
 		return false
 	}
 
-	time := location.(points.BaseLocation).Duration + points.WalkingTime(f.EndLocation, location.(points.BaseLocation)) +
-		f.EndLocation.Duration + f.StartLocation.Duration + points.WalkingTime(f.StartLocation, location)
+	time := location.VisitDuration() + f.travelTime(f.EndLocation, location) +
+		f.EndLocation.VisitDuration() + f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, location)
 
 	if time > f.TimeLimit {
 		return false
@@ -112,13 +118,13 @@ func (f *OPConstraints) SinglePointConstraints(location generic.Point, id int) b
 }
 
 func (f *OPConstraints) ComputeRouteTimeFromSample(locationsID []int, allLocations []generic.Point) int {
-	lastLocation := allLocations[len(locationsID)-1].(points.BaseLocation)
-	time := lastLocation.Duration
+	lastLocation := allLocations[locationsID[len(locationsID)-1]]
+	time := lastLocation.VisitDuration()
 
 	for i := 0; i < len(locationsID)-1; i++ {
-		loc1 := allLocations[locationsID[i]].(points.BaseLocation)
-		loc2 := allLocations[locationsID[i+1]].(points.BaseLocation)
-		time += loc1.Duration + points.WalkingTime(loc1, loc2)
+		loc1 := allLocations[locationsID[i]]
+		loc2 := allLocations[locationsID[i+1]]
+		time += loc1.VisitDuration() + f.travelTime(loc1, loc2)
 	}
 
 	return time
@@ -0,0 +1,40 @@
+package constraints
+
+import (
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+// constantTravel is a travel model with the same travel time between any locations.
+type constantTravel int
+
+func (t constantTravel) TravelTime(from generic.Point, to generic.Point) int {
+	return int(t)
+}
+
+func TestComputeRouteTimeFromSample(t *testing.T) {
+	locations := []generic.Point{
+		&points.BaseLocation{Duration: 10},
+		&points.BaseLocation{Duration: 20},
+		&points.BaseLocation{Duration: 40},
+	}
+	f := &OPConstraints{traveler: traveler{Travel: constantTravel(5)}}
+	tests := []struct {
+		name string
+		ids  []int
+		want int
+	}{
+		{"one location", []int{2}, 40},
+		// the last stop is the last location of the route, not the location at position len(ids)-1:
+		// before the fix the route 0, 2 took 10 + 5 + 20 minutes
+		{"last stop is not next in dataset", []int{0, 2}, 10 + 5 + 40},
+		{"unordered route", []int{2, 0, 1}, 40 + 5 + 10 + 5 + 20},
+	}
+	for _, test := range tests {
+		if got := f.ComputeRouteTimeFromSample(test.ids, locations); got != test.want {
+			t.Errorf("%s: ComputeRouteTimeFromSample(%v) = %d, want %d", test.name, test.ids, got, test.want)
+		}
+	}
+}
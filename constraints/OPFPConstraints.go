@@ -5,47 +5,72 @@ import (
 
 	"github.com/mukhinaks/fops/generic"
 	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/spatial"
 )
 
 type OPFPConstraints struct {
+	traveler
+
 	TimeLimit              int
 	StartID                int
 	EndID                  int
 	StartLocationDistances map[int]float64
 	EndLocationDistance    map[int]float64
 	StartEndDistance       float64
-	StartLocation          points.BaseLocation
-	EndLocation            points.BaseLocation
+	StartLocation          generic.Point
+	EndLocation            generic.Point
+
+	// distancesOf are start and end IDs of computed distances.
+	distancesOf [2]int
+	// index of dataset locations, it is set on Init.
+	index *spatial.KDTree
+}
+
+func (f *OPFPConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
+	f.Travel = solver.Travel
+	f.index = generic.IndexOf(solver.Points)
+	f.setLocations(locs)
+	return f, nil
 }
 
-func (f *OPFPConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+// setLocations computes distances to start and end locations,
+// they are kept between intervals if start and end are not changed.
+func (f *OPFPConstraints) setLocations(locs []generic.Point) {
+	start := locs[f.StartID]
+	end := locs[f.EndID]
+	if f.distancesOf == [2]int{f.StartID, f.EndID} && len(f.StartLocationDistances) == len(locs) {
+		f.StartLocation = start
+		f.EndLocation = end
+		return
+	}
+	f.distancesOf = [2]int{f.StartID, f.EndID}
 
 	f.StartLocationDistances = make(map[int]float64)
 	f.EndLocationDistance = make(map[int]float64)
 
 	for idx, location := range locs {
-		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location.(points.BaseLocation))
-		f.EndLocationDistance[idx] = points.EuclidianDistance(end, location.(points.BaseLocation))
+		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location)
+		f.EndLocationDistance[idx] = points.EuclidianDistance(end, location)
 
 	}
 	f.StartEndDistance = points.EuclidianDistance(start, end)
 
 	f.StartLocation = start
 	f.EndLocation = end
-
-	return f
 }
 
 func (f *OPFPConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 
 	if route == nil {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, f.EndLocation)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, f.EndLocation)
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = f.StartLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
@@ -59,15 +84,15 @@ func (f *OPFPConstraints) routeTime(route map[int]generic.Point, orderOfLocation
 				}
 			}()
 
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 
 		}
 
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
-		duration += points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)) +
-			f.EndLocation.Duration
+		duration += f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]]) +
+			f.EndLocation.VisitDuration()
 
 	}
 	return duration
@@ -80,14 +105,14 @@ func (f *OPFPConstraints) FinalRouteTime(route map[int]generic.Point, orderOfLoc
 		return 0
 	}
 	if len(orderOfLocations) == 1 {
-		return route[orderOfLocations[0]].(points.BaseLocation).Duration
+		return route[orderOfLocations[0]].VisitDuration()
 	}
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-		duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+		walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+		duration += route[key].VisitDuration() + int(walkTime)
 	}
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	return duration
 }
@@ -113,12 +138,12 @@ func (f *OPFPConstraints) ReducePoints(route map[int]generic.Point, orderOfLocat
 		return locations
 	}
 
-	latestLocation := route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)
+	latestLocation := route[orderOfLocations[len(orderOfLocations)-1]]
 
 	distance := points.EuclidianDistance(latestLocation, f.EndLocation)
 
-	for key, location := range locations {
-
+	for _, key := range locationsWithin(f.index, locations, latestLocation, distance) {
+		location := locations[key]
 		if points.EuclidianDistance(location, latestLocation) <= distance && f.EndLocationDistance[key] <= distance {
 			filteredLocations[key] = location
 
@@ -147,8 +172,8 @@ func (f *OPFPConstraints) SinglePointConstraints(location generic.Point, id int)
 		return false
 	}
 
-	time := location.(points.BaseLocation).Duration + points.WalkingTime(f.EndLocation, location.(points.BaseLocation)) +
-		f.EndLocation.Duration + f.StartLocation.Duration + points.WalkingTime(f.StartLocation, location)
+	time := location.VisitDuration() + f.travelTime(f.EndLocation, location) +
+		f.EndLocation.VisitDuration() + f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, location)
 
 	if time > f.TimeLimit {
 		return false
@@ -161,5 +186,6 @@ func (f *OPFPConstraints) UpdateConstraint(route map[int]generic.Point, orderOfP
 	f.StartID = orderOfPoints[0]
 	f.EndID = orderOfPoints[len(orderOfPoints)-1]
 
-	return f.Init(locations)
+	f.setLocations(locations)
+	return f
 }
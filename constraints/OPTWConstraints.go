@@ -2,47 +2,53 @@ package constraints
 
 import (
 	"github.com/mukhinaks/fops/generic"
-	"github.com/mukhinaks/fops/points"
 )
 
 // OPTWConstraints implements Constraint interface for solving orienteering problem with time windows
 type OPTWConstraints struct {
+	traveler
+
 	TimeLimit          int
 	StartID            int
 	EndID              int
-	StartLocation      points.BaseLocation
-	EndLocation        points.BaseLocation
+	StartLocation      generic.Point
+	EndLocation        generic.Point
 	StartTime          int
 	DayOfWeek          string
 	ForbiddenLocations []int
 }
 
-func (f *OPTWConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+func (f *OPTWConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
+	start := locs[f.StartID]
+	end := locs[f.EndID]
 
 	f.StartLocation = start
 	f.EndLocation = end
 
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f *OPTWConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route == nil {
-		duration = f.StartLocation.Duration
+		duration = f.StartLocation.VisitDuration()
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = f.StartLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration + f.EndLocation.Duration + points.WalkingTime(route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation), f.EndLocation)
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(route[orderOfLocations[len(orderOfLocations)-1]], f.EndLocation)
 
 	}
 
@@ -67,10 +73,10 @@ func (f *OPTWConstraints) FinalRouteTime(route map[int]generic.Point, orderOfLoc
 	}
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-		duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+		walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+		duration += route[key].VisitDuration() + int(walkTime)
 	}
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	return duration
 }
@@ -81,31 +87,25 @@ func (f *OPTWConstraints) Boundary(route map[int]generic.Point, orderOfLocations
 		return false
 	}
 
-	time := f.TimeUpdate(f.StartTime, f.StartLocation.Duration)
-	time = f.TimeUpdate(time, points.WalkingTime(f.StartLocation, route[orderOfLocations[0]].(points.BaseLocation)))
+	time := f.TimeUpdate(f.StartTime, f.StartLocation.VisitDuration())
+	time = f.TimeUpdate(time, f.travelTime(f.StartLocation, route[orderOfLocations[0]]))
 
 	for i := 0; i <= len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
 		if key == f.StartID || key == f.EndID {
 			continue
 		}
-		location := route[key].(points.BaseLocation)
+		location := route[key]
 
-		openHours, ok := location.OpenHours[f.DayOfWeek]
+		openTime, closeTime, ok := location.OpeningHours(f.DayOfWeek)
 		if !ok {
 			return false
 		}
 
-		startWorking := openHours[0]
-		endWorking := openHours[1]
-		if endWorking < startWorking {
-			endWorking += 2400
-		}
-
-		if openHours[0] <= time && openHours[1] >= f.TimeUpdate(time, location.Duration) {
-			time = f.TimeUpdate(time, location.Duration)
+		if openTime <= time && closeTime >= f.TimeUpdate(time, location.VisitDuration()) {
+			time = f.TimeUpdate(time, location.VisitDuration())
 			if i != len(orderOfLocations)-1 {
-				walkTime := points.WalkingTime(location, route[orderOfLocations[i+1]].(points.BaseLocation))
+				walkTime := f.travelTime(location, route[orderOfLocations[i+1]])
 				time = f.TimeUpdate(time, walkTime)
 			}
 		} else {
@@ -114,20 +114,14 @@ func (f *OPTWConstraints) Boundary(route map[int]generic.Point, orderOfLocations
 	}
 
 	if len(orderOfLocations) > 0 {
-		walkTime := points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation))
+		walkTime := f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]])
 		time = f.TimeUpdate(time, walkTime)
-		openHours, ok := f.EndLocation.OpenHours[f.DayOfWeek]
+		openTime, closeTime, ok := f.EndLocation.OpeningHours(f.DayOfWeek)
 		if !ok {
 			return false
 		}
 
-		startWorking := openHours[0]
-		endWorking := openHours[1]
-		if endWorking < startWorking {
-			endWorking += 2400
-		}
-
-		if openHours[0] <= time && openHours[1] >= f.TimeUpdate(time, f.EndLocation.Duration) {
+		if openTime <= time && closeTime >= f.TimeUpdate(time, f.EndLocation.VisitDuration()) {
 			return true
 
 		}
@@ -152,8 +146,8 @@ func (f *OPTWConstraints) SinglePointConstraints(location generic.Point, id int)
 		return false
 	}
 
-	time := location.(points.BaseLocation).Duration + points.WalkingTime(f.EndLocation, location.(points.BaseLocation)) +
-		f.EndLocation.Duration + f.StartLocation.Duration + points.WalkingTime(f.StartLocation, location)
+	time := location.VisitDuration() + f.travelTime(f.EndLocation, location) +
+		f.EndLocation.VisitDuration() + f.StartLocation.VisitDuration() + f.travelTime(f.StartLocation, location)
 
 	if time > f.TimeLimit {
 		return false
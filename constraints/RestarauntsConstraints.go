@@ -3,47 +3,62 @@ package constraints
 import (
 	"github.com/mukhinaks/fops/generic"
 	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/query"
 )
 
 type RestarauntsConstraints struct {
+	traveler
+
 	TimeLimit int
 	StartID   int
 	EndID     int
 
-	StartLocation      points.CityBrandLocation
-	EndLocation        points.CityBrandLocation
+	StartLocation      generic.Point
+	EndLocation        generic.Point
 	ForbiddenLocations []int
 	StartTime          int
 	DayOfWeek          int
+	// Restaurants are locations which are visited, see points.RestaurantsOf.
+	Restaurants *query.Expression
 }
 
-func (f RestarauntsConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.CityBrandLocation)
-	end := locs[f.EndID].(points.CityBrandLocation)
+func (f RestarauntsConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
+	start := locs[f.StartID]
+	end := locs[f.EndID]
+	restaurants, err := points.RestaurantsOf(solver.Points)
+	if err != nil {
+		return nil, err
+	}
 
 	f.StartLocation = start
 	f.EndLocation = end
+	f.Restaurants = restaurants
 
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f RestarauntsConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route == nil {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, f.EndLocation)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, f.EndLocation)
 	} else {
-		loc := route[orderOfLocations[0]].(points.CityBrandLocation)
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.CityBrandLocation), route[orderOfLocations[i+1]].(points.CityBrandLocation))
-			duration += route[key].(points.CityBrandLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.CityBrandLocation)) +
-			route[orderOfLocations[len(orderOfLocations)-1]].(points.CityBrandLocation).Duration
+		duration += f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]]) +
+			route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	}
 	return duration
@@ -56,10 +71,10 @@ func (f RestarauntsConstraints) FinalRouteTime(route map[int]generic.Point, orde
 	} else {
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	}
 	return duration
@@ -88,8 +103,8 @@ func (f RestarauntsConstraints) Boundary(route map[int]generic.Point, orderOfLoc
 		return false
 	}
 
-	time := f.TimeUpdate(f.StartTime, f.StartLocation.Duration)
-	time = f.TimeUpdate(time, points.WalkingTime(f.StartLocation, route[orderOfLocations[0]].(points.CityBrandLocation)))
+	time := f.TimeUpdate(f.StartTime, f.StartLocation.VisitDuration())
+	time = f.TimeUpdate(time, f.travelTime(f.StartLocation, route[orderOfLocations[0]]))
 
 	return true
 }
@@ -109,13 +124,7 @@ func (f RestarauntsConstraints) SinglePointConstraints(location generic.Point, i
 		return false
 	}
 
-	loc := location.(points.CityBrandLocation)
-	for _, category := range loc.Categories {
-		if category == "Restaurant" {
-			return true
-		}
-	}
-	return false
+	return f.Restaurants.Match(location)
 }
 
 func (f RestarauntsConstraints) UpdateConstraint(route map[int]generic.Point, orderOfPoints []int, locations []generic.Point) generic.Constraints {
@@ -2,39 +2,83 @@ package constraints
 
 import (
 	"math/rand"
+	"time"
 
 	"github.com/mukhinaks/fops/generic"
-	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/traffic"
 )
 
 type TDOPConstraints struct {
+	traveler
+
 	TimeLimit int
 	StartID   int
 	EndID     int
 
-	StartLocation     points.BaseLocation
-	EndLocation       points.BaseLocation
+	StartLocation     generic.Point
+	EndLocation       generic.Point
 	SpeedDistribution map[int][]float64
 	StartTime         int
+	// Seed makes speed distribution reproducible, random seed is used if it is zero.
+	Seed int64
+	// DayOfWeek selects speed profiles of the day, "0" is Sunday.
+	DayOfWeek string
+	// Profile replaces random speed distribution if it is set, it is loaded from SpeedProfilePath.
+	Profile *traffic.Profile
+	// zones are zones of profile and ids are IDs of locations by location position,
+	// they are computed once for all intervals.
+	zones []int
+	ids   []generic.LocationID
 }
 
-func (f *TDOPConstraints) Init(locs []generic.Point) generic.Constraints {
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+func (f *TDOPConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
+	start := locs[f.StartID]
+	end := locs[f.EndID]
 
-	f.SpeedDistribution = make(map[int][]float64)
+	if f.Profile == nil && solver.Configuration.SpeedProfilePath != "" {
+		profile, err := traffic.Load(solver.Configuration.SpeedProfilePath)
+		if err != nil {
+			return nil, err
+		}
+		f.Profile = profile
+	}
+	if f.Profile != nil && len(f.zones) != len(locs) {
+		f.zones = make([]int, len(locs))
+		f.ids = make([]generic.LocationID, len(locs))
+		for i, loc := range locs {
+			f.zones[i] = f.Profile.ZoneOf(loc.LatLng())
+			f.ids[i] = generic.IDOf(solver.Points, i)
+		}
+	}
 
-	for idx := range locs {
-		f.SpeedDistribution[idx] = make([]float64, 24)
-		for i := 0; i < 24; i++ {
-			f.SpeedDistribution[idx][i] = rand.NormFloat64()
+	// random speed distribution is used only without profile
+	if f.Profile == nil {
+		f.SpeedDistribution = make(map[int][]float64)
+		seed := f.Seed
+		if seed == 0 {
+			seed = solver.Configuration.Seed
+		}
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		random := rand.New(rand.NewSource(seed))
+		for idx := range locs {
+			f.SpeedDistribution[idx] = make([]float64, 24)
+			for i := 0; i < 24; i++ {
+				f.SpeedDistribution[idx][i] = random.NormFloat64()
+			}
 		}
 	}
 
 	f.StartLocation = start
 	f.EndLocation = end
 
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f *TDOPConstraints) TimeUpdate(currentTime int, duration int) int {
@@ -61,30 +105,44 @@ func (f *TDOPConstraints) updatedTime(walkingTime int, stockID int, currentTime
 	return newTime
 }
 
+// travelTimeAt returns travel time between locations fromID and toID departing at currentTime in HHMM format.
+// Timetables of time-dependent travel model and speed profile replace random speed distribution.
+func (f *TDOPConstraints) travelTimeAt(from generic.Point, to generic.Point, fromID int, toID int, currentTime int) int {
+	if travel, ok := f.Travel.(generic.TimeDependentTravelModel); ok {
+		return travel.TravelTimeAt(from, to, currentTime)
+	}
+	if f.Profile != nil {
+		departure := currentTime/100*60 + currentTime%100
+		return f.Profile.TravelTime(f.travelTime(from, to), f.ids[fromID], f.ids[toID], f.zones[toID], f.DayOfWeek,
+			departure)
+	}
+	return f.updatedTime(f.travelTime(from, to), toID, currentTime)
+}
+
 func (f *TDOPConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	time := f.StartTime
 	if route == nil {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + f.updatedTime(points.WalkingTime(f.StartLocation, f.EndLocation), f.EndID, time)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTimeAt(f.StartLocation, f.EndLocation, f.StartID, f.EndID, time)
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = f.StartLocation.Duration + f.updatedTime(points.WalkingTime(f.StartLocation, loc), orderOfLocations[0], time)
+		loc := route[orderOfLocations[0]]
+		duration = f.StartLocation.VisitDuration() + f.travelTimeAt(f.StartLocation, loc, f.StartID, orderOfLocations[0], time)
 		time = f.TimeUpdate(f.StartTime, duration)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			duration += route[key].(points.BaseLocation).Duration
+			duration += route[key].VisitDuration()
 			time = f.TimeUpdate(f.StartTime, duration)
-			walkTime := f.updatedTime(points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation)), orderOfLocations[i+1], time)
+			walkTime := f.travelTimeAt(route[key], route[orderOfLocations[i+1]], key, orderOfLocations[i+1], time)
 			duration += int(walkTime)
 			time = f.TimeUpdate(f.StartTime, duration)
 		}
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 		time = f.TimeUpdate(f.StartTime, duration)
-		duration += f.updatedTime(points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)), f.EndID, time) +
-			f.EndLocation.Duration
+		duration += f.travelTimeAt(route[orderOfLocations[len(orderOfLocations)-1]], f.EndLocation, orderOfLocations[len(orderOfLocations)-1], f.EndID, time) +
+			f.EndLocation.VisitDuration()
 
 	}
 	return duration
@@ -98,13 +156,13 @@ func (f *TDOPConstraints) FinalRouteTime(route map[int]generic.Point, orderOfLoc
 	}
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		duration += route[key].(points.BaseLocation).Duration
+		duration += route[key].VisitDuration()
 		time = f.TimeUpdate(f.StartTime, duration)
-		walkTime := f.updatedTime(points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation)), orderOfLocations[i+1], time)
+		walkTime := f.travelTimeAt(route[key], route[orderOfLocations[i+1]], key, orderOfLocations[i+1], time)
 		duration += int(walkTime)
 		time = f.TimeUpdate(f.StartTime, duration)
 	}
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	return duration
 }
@@ -127,14 +185,14 @@ func (f *TDOPConstraints) SinglePointConstraints(location generic.Point, id int)
 		return false
 	}
 
-	time := f.StartLocation.Duration
+	time := f.StartLocation.VisitDuration()
 	t := f.TimeUpdate(f.StartTime, time)
 
-	time += f.updatedTime(points.WalkingTime(f.StartLocation, location), id, t) + location.(points.BaseLocation).Duration
+	time += f.travelTimeAt(f.StartLocation, location, f.StartID, id, t) + location.VisitDuration()
 	t = f.TimeUpdate(f.StartTime, time)
 
-	time += f.updatedTime(points.WalkingTime(f.EndLocation, location.(points.BaseLocation)), f.EndID, t) +
-		f.EndLocation.Duration
+	time += f.travelTimeAt(location, f.EndLocation, id, f.EndID, t) +
+		f.EndLocation.VisitDuration()
 
 	if time > f.TimeLimit {
 		return false
@@ -0,0 +1,100 @@
+package constraints
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/traffic"
+)
+
+// identifiedLocations are locations with external IDs, other methods of Points are not used.
+type identifiedLocations struct {
+	generic.Points
+	locations []generic.Point
+	ids       []generic.LocationID
+}
+
+func (l identifiedLocations) GetAllPoints() []generic.Point {
+	return l.locations
+}
+
+func (l identifiedLocations) ID(position int) generic.LocationID {
+	return l.ids[position]
+}
+
+func (l identifiedLocations) Position(id generic.LocationID) (int, bool) {
+	for i, locationID := range l.ids {
+		if locationID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func TestTDOPConstraintsProfile(t *testing.T) {
+	profile, err := traffic.Read(strings.NewReader(`{"slot_minutes": 720, "zones": [
+		{"name": "center", "polygon": [[59, 30], [59, 31], [60, 31], [60, 30]]}], "profiles": [
+		{"from": "museum", "to": 17, "speeds": [0.5, 0.5]},
+		{"zone": "center", "speeds": [2, 2]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations := identifiedLocations{
+		locations: []generic.Point{
+			&points.BaseLocation{Lat: 55.75, Lng: 37.62},
+			&points.BaseLocation{Lat: 55.76, Lng: 37.63},
+			&points.BaseLocation{Lat: 59.93, Lng: 30.31},
+		},
+		ids: []generic.LocationID{"museum", "17", "node/5"},
+	}
+	solver := &generic.Solver{Points: locations, Travel: constantTravel(10)}
+	f := &TDOPConstraints{StartID: 0, EndID: 1, StartTime: 1000, Profile: profile}
+	if _, err := f.Init(solver); err != nil {
+		t.Fatal(err)
+	}
+	if f.SpeedDistribution != nil {
+		t.Errorf("random speed distribution is filled with profile")
+	}
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     int
+	}{
+		{"edge by IDs", 0, 1, 20},
+		{"edge is directed", 1, 0, 10},
+		{"zone of destination", 1, 2, 5},
+	}
+	for _, test := range tests {
+		got := f.travelTimeAt(locations.locations[test.from], locations.locations[test.to], test.from, test.to, 1000)
+		if got != test.want {
+			t.Errorf("%s: travel time = %d, want %d", test.name, got, test.want)
+		}
+	}
+
+	// zones are computed once for all intervals
+	zones := f.zones
+	if _, err := f.Init(solver); err != nil {
+		t.Fatal(err)
+	}
+	if &f.zones[0] != &zones[0] {
+		t.Errorf("zones are computed again for the next interval")
+	}
+}
+
+func TestTDOPConstraintsRandomSpeeds(t *testing.T) {
+	locations := identifiedLocations{
+		locations: []generic.Point{&points.BaseLocation{}, &points.BaseLocation{}, &points.BaseLocation{}},
+		ids:       []generic.LocationID{"1", "2", "3"},
+	}
+	solver := &generic.Solver{Points: locations, Travel: constantTravel(10)}
+	f := &TDOPConstraints{StartID: 0, EndID: 1, StartTime: 1000, Seed: 1}
+	if _, err := f.Init(solver); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.SpeedDistribution) != 3 || len(f.SpeedDistribution[2]) != 24 {
+		t.Errorf("random speed distribution %v, want 24 speeds of 3 locations", f.SpeedDistribution)
+	}
+}
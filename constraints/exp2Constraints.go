@@ -8,6 +8,8 @@ import (
 )
 
 type EnrichmentConstraints struct {
+	traveler
+
 	TimeLimit              []int
 	NumberOfInterval       int
 	StartID                int
@@ -15,24 +17,27 @@ type EnrichmentConstraints struct {
 	StartLocationDistances map[int]float64
 	EndLocationDistance    map[int]float64
 	StartEndDistance       float64
-	StartLocation          points.BaseLocation
-	EndLocation            points.BaseLocation
+	StartLocation          generic.Point
+	EndLocation            generic.Point
 	ForbiddenLocations     []int
 	CompulsoryLocations    []int
 	RouteTimeLimit         int
 }
 
-func (f EnrichmentConstraints) Init(locs []generic.Point) generic.Constraints {
+func (f EnrichmentConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := checkCompulsoryLocations(f.CompulsoryLocations, f.NumberOfInterval, locs); err != nil {
+		return nil, err
+	}
 	f.StartID = f.CompulsoryLocations[f.NumberOfInterval]
 	f.EndID = f.CompulsoryLocations[f.NumberOfInterval+1]
-	start := locs[f.CompulsoryLocations[f.NumberOfInterval]].(points.BaseLocation)
-	end := locs[f.CompulsoryLocations[f.NumberOfInterval+1]].(points.BaseLocation)
+	start := locs[f.CompulsoryLocations[f.NumberOfInterval]]
+	end := locs[f.CompulsoryLocations[f.NumberOfInterval+1]]
 
 	f.StartLocationDistances = make(map[int]float64)
 	f.EndLocationDistance = make(map[int]float64)
 
-	for idx, loc := range locs {
-		location := loc.(points.BaseLocation)
+	for idx, location := range locs {
 		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location)
 		f.EndLocationDistance[idx] = points.EuclidianDistance(end, location)
 	}
@@ -41,7 +46,8 @@ func (f EnrichmentConstraints) Init(locs []generic.Point) generic.Constraints {
 	f.EndLocation = end
 
 	f.TimeLimit = f.computeTimeLimits(f.RouteTimeLimit, locs)
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f EnrichmentConstraints) computeTimeLimits(routeTimeLimit int, locs []generic.Point) []int {
@@ -53,7 +59,7 @@ func (f EnrichmentConstraints) computeTimeLimits(routeTimeLimit int, locs []gene
 	for i := 0; i < len(f.CompulsoryLocations)-1; i++ {
 		keyStart := f.CompulsoryLocations[i]
 		keyEnd := f.CompulsoryLocations[i+1]
-		distance := points.EuclidianDistance(locs[keyStart].(points.BaseLocation), locs[keyEnd].(points.BaseLocation))
+		distance := points.EuclidianDistance(locs[keyStart], locs[keyEnd])
 
 		value := 0
 		for id, loc := range locs {
@@ -63,8 +69,8 @@ func (f EnrichmentConstraints) computeTimeLimits(routeTimeLimit int, locs []gene
 				}
 			}
 
-			if points.EuclidianDistance(loc.(points.BaseLocation), locs[keyEnd].(points.BaseLocation)) <= distance ||
-				points.EuclidianDistance(loc.(points.BaseLocation), locs[keyStart].(points.BaseLocation)) <= distance {
+			if points.EuclidianDistance(loc, locs[keyEnd]) <= distance ||
+				points.EuclidianDistance(loc, locs[keyStart]) <= distance {
 				value++
 			}
 		}
@@ -72,11 +78,11 @@ func (f EnrichmentConstraints) computeTimeLimits(routeTimeLimit int, locs []gene
 		sumLocationsCount += value
 
 		time :=
-			points.WalkingTime(locs[keyStart].(points.BaseLocation), locs[keyEnd].(points.BaseLocation))
+			f.travelTime(locs[keyStart], locs[keyEnd])
 		if i == 0 {
-			time += locs[keyStart].(points.BaseLocation).Duration + locs[keyEnd].(points.BaseLocation).Duration
+			time += locs[keyStart].VisitDuration() + locs[keyEnd].VisitDuration()
 		} else {
-			time += locs[keyEnd].(points.BaseLocation).Duration
+			time += locs[keyEnd].VisitDuration()
 
 		}
 		sumTime += time
@@ -104,22 +110,22 @@ func (f EnrichmentConstraints) computeTimeLimits(routeTimeLimit int, locs []gene
 func (f EnrichmentConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route == nil {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, f.EndLocation)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, f.EndLocation)
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = f.EndLocation.Duration + points.WalkingTime(f.StartLocation, loc)
+		loc := route[orderOfLocations[0]]
+		duration = f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, loc)
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)) +
-			route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]]) +
+			route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 		if f.NumberOfInterval == 0 {
-			duration += f.StartLocation.Duration
+			duration += f.StartLocation.VisitDuration()
 		}
 
 	}
@@ -133,10 +139,10 @@ func (f EnrichmentConstraints) FinalRouteTime(route map[int]generic.Point, order
 	} else {
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 
 	}
 	return duration
@@ -179,3 +185,16 @@ func (f EnrichmentConstraints) SinglePointConstraints(location generic.Point, id
 func (f EnrichmentConstraints) UpdateConstraint(route map[int]generic.Point, orderOfPoints []int, locations []generic.Point) generic.Constraints {
 	return f
 }
+
+// checkCompulsoryLocations checks that all compulsory locations exist and interval is within the route.
+func checkCompulsoryLocations(compulsoryLocations []int, numberOfInterval int, locs []generic.Point) error {
+	for _, id := range compulsoryLocations {
+		if err := generic.CheckPointIndex("compulsory", id, locs); err != nil {
+			return err
+		}
+	}
+	if numberOfInterval < 0 || numberOfInterval+1 >= len(compulsoryLocations) {
+		return fmt.Errorf("interval %d does not exist in route of %d compulsory locations", numberOfInterval, len(compulsoryLocations))
+	}
+	return nil
+}
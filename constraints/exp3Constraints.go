@@ -8,6 +8,8 @@ import (
 )
 
 type MultidaysConstraints struct {
+	traveler
+
 	DayTimeLimit int
 	DaysNumber   int
 
@@ -16,8 +18,8 @@ type MultidaysConstraints struct {
 	StartLocationDistances map[int]float64
 	EndLocationDistance    map[int]float64
 	StartEndDistance       float64
-	StartLocation          points.BaseLocation
-	EndLocation            points.BaseLocation
+	StartLocation          generic.Point
+	EndLocation            generic.Point
 
 	TimeLimit           []int
 	CurrentDay          int
@@ -27,45 +29,49 @@ type MultidaysConstraints struct {
 	NumberOfInterval    int
 }
 
-func (f MultidaysConstraints) Init(locs []generic.Point) generic.Constraints {
+func (f MultidaysConstraints) Init(solver *generic.Solver) (generic.Constraints, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := checkCompulsoryLocations(f.CompulsoryLocations, f.NumberOfInterval, locs); err != nil {
+		return nil, err
+	}
 	f.StartID = f.CompulsoryLocations[f.NumberOfInterval]
 	f.EndID = f.CompulsoryLocations[f.NumberOfInterval+1]
-	start := locs[f.CompulsoryLocations[f.NumberOfInterval]].(points.BaseLocation)
-	end := locs[f.CompulsoryLocations[f.NumberOfInterval+1]].(points.BaseLocation)
+	start := locs[f.CompulsoryLocations[f.NumberOfInterval]]
+	end := locs[f.CompulsoryLocations[f.NumberOfInterval+1]]
 
 	f.StartLocationDistances = make(map[int]float64)
 	f.EndLocationDistance = make(map[int]float64)
 
-	for idx, loc := range locs {
-		location := loc.(points.BaseLocation)
+	for idx, location := range locs {
 		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location)
 		f.EndLocationDistance[idx] = points.EuclidianDistance(end, location)
 	}
 	f.StartEndDistance = points.EuclidianDistance(start, end)
 	f.StartLocation = start
 	f.EndLocation = end
-	return f
+	f.Travel = solver.Travel
+	return f, nil
 }
 
 func (f MultidaysConstraints) routeTime(route map[int]generic.Point, orderOfLocations []int) int {
 	duration := 0
 	if route[orderOfLocations[0]] == nil || len(orderOfLocations) == 0 {
-		duration = f.StartLocation.Duration + f.EndLocation.Duration + points.WalkingTime(f.StartLocation, f.EndLocation)
+		duration = f.StartLocation.VisitDuration() + f.EndLocation.VisitDuration() + f.travelTime(f.StartLocation, f.EndLocation)
 	} else {
-		loc := route[orderOfLocations[0]].(points.BaseLocation)
-		duration = points.WalkingTime(f.StartLocation, loc) + f.EndLocation.Duration
+		loc := route[orderOfLocations[0]]
+		duration = f.travelTime(f.StartLocation, loc) + f.EndLocation.VisitDuration()
 		for i := 0; i < len(orderOfLocations)-1; i++ {
 			key := orderOfLocations[i]
 			if key == f.StartID || key == f.EndID {
 				continue
 			}
-			walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-			duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+			walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+			duration += route[key].VisitDuration() + int(walkTime)
 		}
-		duration += points.WalkingTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation)) +
-			route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+		duration += f.travelTime(f.EndLocation, route[orderOfLocations[len(orderOfLocations)-1]]) +
+			route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 		if f.NumberOfInterval == 0 {
-			duration += f.StartLocation.Duration
+			duration += f.StartLocation.VisitDuration()
 		}
 	}
 	return duration
@@ -78,10 +84,10 @@ func (f MultidaysConstraints) FinalRouteTime(route map[int]generic.Point, orderO
 	duration := 0
 	for i := 0; i < len(orderOfLocations)-1; i++ {
 		key := orderOfLocations[i]
-		walkTime := points.WalkingTime(route[key].(points.BaseLocation), route[orderOfLocations[i+1]].(points.BaseLocation))
-		duration += route[key].(points.BaseLocation).Duration + int(walkTime)
+		walkTime := f.travelTime(route[key], route[orderOfLocations[i+1]])
+		duration += route[key].VisitDuration() + int(walkTime)
 	}
-	duration += route[orderOfLocations[len(orderOfLocations)-1]].(points.BaseLocation).Duration
+	duration += route[orderOfLocations[len(orderOfLocations)-1]].VisitDuration()
 	return duration
 
 }
@@ -157,7 +163,7 @@ func (f MultidaysConstraints) computeTimeLimits(routeTimeLimit int, locs []gener
 	for i := 0; i < len(compulsoryLocations)-1; i++ {
 		keyStart := compulsoryLocations[i]
 		keyEnd := compulsoryLocations[i+1]
-		distance := points.EuclidianDistance(locs[keyStart].(points.BaseLocation), locs[keyEnd].(points.BaseLocation))
+		distance := points.EuclidianDistance(locs[keyStart], locs[keyEnd])
 
 		value := 0
 		for id, loc := range locs {
@@ -167,8 +173,8 @@ func (f MultidaysConstraints) computeTimeLimits(routeTimeLimit int, locs []gener
 				}
 			}
 
-			if points.EuclidianDistance(loc.(points.BaseLocation), locs[keyEnd].(points.BaseLocation)) <= distance ||
-				points.EuclidianDistance(loc.(points.BaseLocation), locs[keyStart].(points.BaseLocation)) <= distance {
+			if points.EuclidianDistance(loc, locs[keyEnd]) <= distance ||
+				points.EuclidianDistance(loc, locs[keyStart]) <= distance {
 				value++
 			}
 		}
@@ -176,11 +182,11 @@ func (f MultidaysConstraints) computeTimeLimits(routeTimeLimit int, locs []gener
 		sumLocationsCount += value
 
 		time :=
-			points.WalkingTime(locs[keyStart].(points.BaseLocation), locs[keyEnd].(points.BaseLocation))
+			f.travelTime(locs[keyStart], locs[keyEnd])
 		if i == 0 {
-			time += locs[keyStart].(points.BaseLocation).Duration + locs[keyEnd].(points.BaseLocation).Duration
+			time += locs[keyStart].VisitDuration() + locs[keyEnd].VisitDuration()
 		} else {
-			time += locs[keyEnd].(points.BaseLocation).Duration
+			time += locs[keyEnd].VisitDuration()
 		}
 		sumTime += time
 		minimumTime = append(minimumTime, time)
@@ -0,0 +1,32 @@
+package constraints
+
+import (
+	"sort"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// borderTolerance widens index queries, so that locations on the border are not lost because of rounding errors.
+const borderTolerance = 1e-9
+
+// locationsWithin returns sorted keys of locations which may be within distance from the point,
+// callers check exact distance. All keys are returned if index is nil.
+func locationsWithin(index *spatial.KDTree, locations map[int]generic.Point, point generic.Point, distance float64) []int {
+	keys := make([]int, 0)
+	if index == nil {
+		for key := range locations {
+			keys = append(keys, key)
+		}
+		sort.Ints(keys)
+		return keys
+	}
+
+	x, y := point.Coordinates()
+	for _, key := range index.Radius(x, y, distance*(1+borderTolerance)) {
+		if _, ok := locations[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,120 @@
+package constraints
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+func init() {
+	generic.RegisterConstraints("op", func(task generic.Task) (generic.Constraints, error) {
+		return &OPConstraints{
+			StartID:   task.StartID,
+			EndID:     task.EndID,
+			TimeLimit: task.TimeLimit,
+		}, nil
+	})
+	generic.RegisterConstraints("optw", func(task generic.Task) (generic.Constraints, error) {
+		return &OPTWConstraints{
+			StartID:            task.StartID,
+			EndID:              task.EndID,
+			TimeLimit:          task.TimeLimit,
+			StartTime:          task.StartTime,
+			DayOfWeek:          task.DayOfWeek,
+			ForbiddenLocations: task.ForbiddenLocations,
+		}, nil
+	})
+	generic.RegisterConstraints("tdop", func(task generic.Task) (generic.Constraints, error) {
+		return &TDOPConstraints{
+			StartID:   task.StartID,
+			EndID:     task.EndID,
+			TimeLimit: task.TimeLimit,
+			StartTime: task.StartTime,
+			Seed:      task.Seed,
+			DayOfWeek: task.DayOfWeek,
+		}, nil
+	})
+	generic.RegisterConstraints("opfp", func(task generic.Task) (generic.Constraints, error) {
+		return &OPFPConstraints{
+			StartID:   task.StartID,
+			EndID:     task.EndID,
+			TimeLimit: task.TimeLimit,
+		}, nil
+	})
+	generic.RegisterConstraints("eropfp", func(task generic.Task) (generic.Constraints, error) {
+		return &EROPFPConstraints{
+			StartID:   task.StartID,
+			EndID:     task.EndID,
+			TimeLimit: task.TimeLimit,
+		}, nil
+	})
+	generic.RegisterConstraints("opcv", func(task generic.Task) (generic.Constraints, error) {
+		c := EnrichmentConstraints{
+			CompulsoryLocations: task.CompulsoryLocations,
+			RouteTimeLimit:      task.TimeLimit,
+		}
+		c.ForbiddenLocations = append(c.ForbiddenLocations, task.ForbiddenLocations...)
+		c.ForbiddenLocations = append(c.ForbiddenLocations, task.CompulsoryLocations...)
+		return c, nil
+	})
+	generic.RegisterConstraints("multidays", func(task generic.Task) (generic.Constraints, error) {
+		c := MultidaysConstraints{
+			CompulsoryLocations: task.CompulsoryLocations,
+			DayTimeLimit:        task.TimeLimit,
+			DaysNumber:          task.Days,
+		}
+		c.ForbiddenLocations = append(c.ForbiddenLocations, task.ForbiddenLocations...)
+		c.ForbiddenLocations = append(c.ForbiddenLocations, task.CompulsoryLocations...)
+		return c, nil
+	})
+	generic.RegisterConstraints("citybrand", func(task generic.Task) (generic.Constraints, error) {
+		dayOfWeek, err := dayNumber(task.DayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+		return &CityBrandConstraints{
+			StartID:            task.StartID,
+			TimeLimit:          task.TimeLimit,
+			StartTime:          task.StartTime,
+			DayOfWeek:          dayOfWeek,
+			ForbiddenLocations: task.ForbiddenLocations,
+		}, nil
+	})
+	generic.RegisterConstraints("restaurants", func(task generic.Task) (generic.Constraints, error) {
+		dayOfWeek, err := dayNumber(task.DayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+		return RestarauntsConstraints{
+			StartID:            task.StartID,
+			EndID:              task.EndID,
+			TimeLimit:          task.TimeLimit,
+			StartTime:          task.StartTime,
+			DayOfWeek:          dayOfWeek,
+			ForbiddenLocations: task.ForbiddenLocations,
+		}, nil
+	})
+}
+
+func dayNumber(dayOfWeek string) (int, error) {
+	day, err := strconv.Atoi(dayOfWeek)
+	if err != nil {
+		return 0, fmt.Errorf("invalid day of week %q", dayOfWeek)
+	}
+	return day, nil
+}
+
+// traveler computes travel time with travel model of the solver.
+// Walking is used until constraints are initialized.
+type traveler struct {
+	Travel generic.TravelModel
+}
+
+func (t traveler) travelTime(from generic.Point, to generic.Point) int {
+	if t.Travel == nil {
+		return points.WalkingTime(from, to)
+	}
+	return t.Travel.TravelTime(from, to)
+}
@@ -0,0 +1,239 @@
+// Package fops is a framework for orienteering problem solving.
+// It composes points, score, constraints and path algorithm into a solver
+// and exposes a single Solve entry point for all supported problem types.
+package fops
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/geofence"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/query"
+)
+
+// Supported problem kinds.
+const (
+	OP        = "op"
+	OPCV      = "opcv"
+	OPTW      = "optw"
+	TDOP      = "tdop"
+	OPFP      = "opfp"
+	CityBrand = "citybrand"
+)
+
+// Kinds are supported problem kinds.
+var Kinds = []string{OP, OPCV, OPTW, TDOP, OPFP, CityBrand}
+
+// Supported path algorithms.
+const (
+	ACO = "ACO"
+	RGA = "RGA"
+)
+
+// Problem describes a single routing request.
+type Problem struct {
+	// Kind is one of OP, OPCV, OPTW, TDOP, OPFP or CityBrand, it may be empty if Constraints are set.
+	Kind string `json:"kind"`
+	// Algorithm is a registered path algorithm, e.g. ACO or RGA. ACO is used if empty.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Points, Score and Constraints are names of registered components which replace default
+	// components of the problem kind. They are supported by OP, OPTW, TDOP and OPFP and by problems
+	// without kind, which require Constraints.
+	Points      string `json:"points,omitempty"`
+	Score       string `json:"score,omitempty"`
+	Constraints string `json:"constraints,omitempty"`
+	// ConfigPath is a path to solver configuration, config.json is used if empty.
+	ConfigPath string `json:"config,omitempty"`
+	// DataPath replaces dataset path from configuration if set.
+	DataPath string `json:"data,omitempty"`
+	// Seed makes randomized algorithms reproducible, random seed is used if it is zero.
+	Seed int64 `json:"seed,omitempty"`
+	// Settings replace configuration values, keys are configuration field names, e.g. "Iterations".
+	Settings map[string]string `json:"settings,omitempty"`
+	// TravelMode is a registered travel model, e.g. walking, cycling or driving. It replaces TravelMode
+	// from configuration if set.
+	TravelMode string `json:"travel_mode,omitempty"`
+
+	// Locations are given by IDs of dataset: "id" field or position in dataset for locations without it.
+	StartID generic.LocationID `json:"start_id"`
+	EndID   generic.LocationID `json:"end_id"`
+	// CompulsoryLocations are visited in the given order (OPCV only).
+	CompulsoryLocations []generic.LocationID `json:"compulsory_locations,omitempty"`
+	// ReferencePath defines start, end and time budget of OP from existing route.
+	ReferencePath []generic.LocationID `json:"reference_path,omitempty"`
+	// Days splits compulsory locations into several daily routes (OPCV only).
+	Days int `json:"days,omitempty"`
+
+	// TimeLimit is route time budget in minutes. For multi-day OPCV it is a daily budget.
+	TimeLimit int `json:"time_limit"`
+	// StartTime is a time of the day in HHMM format.
+	StartTime int `json:"start_time,omitempty"`
+	// DayOfWeek is a key of location open hours, "0" is Sunday.
+	DayOfWeek Weekday `json:"day_of_week,omitempty"`
+	// ForbiddenLocations are not visited, IDs which are not in dataset are skipped.
+	ForbiddenLocations []generic.LocationID `json:"forbidden_locations,omitempty"`
+	// AllowedAreas and ForbiddenAreas are GeoJSON files of polygons and multipolygons. Locations outside
+	// of allowed areas and inside of forbidden areas are not visited, start and end are not checked.
+	AllowedAreas   string `json:"allowed_areas,omitempty"`
+	ForbiddenAreas string `json:"forbidden_areas,omitempty"`
+	// CrossingPenalty is subtracted from route score for every leg which crosses forbidden areas.
+	CrossingPenalty float64 `json:"crossing_penalty,omitempty"`
+	// DiversityPenalty is subtracted from route score for every category of a location which is already
+	// visited by the route, categories are compared with their ancestors of taxonomy (see DataTaxonomy).
+	DiversityPenalty float64 `json:"diversity_penalty,omitempty"`
+	// Include and Exclude are filter expressions over location attributes (see package query), e.g.
+	// `category in ["Museums & Libraries"] and tripAdvisor_rating >= 4`. Only locations which match Include
+	// and do not match Exclude are visited, start, end and compulsory locations are not checked. Filters
+	// choose sights, restaurants of CityBrand routes are chosen regardless of them.
+	Include string `json:"include,omitempty"`
+	Exclude string `json:"exclude,omitempty"`
+
+	// OutputPath is a JSON file for resulting route, nothing is written if empty.
+	OutputPath string `json:"output,omitempty"`
+}
+
+// Itinerary is a solution of the Problem.
+type Itinerary struct {
+	// Order are positions of visited locations in dataset, IDs are their external IDs.
+	Order     []int
+	IDs       []generic.LocationID
+	Locations []generic.Point
+	Score     float64
+	RouteTime int
+}
+
+// Config loads solver configuration of the problem: configuration file and environment variables
+// overridden by problem settings, dataset path and seed.
+func (problem Problem) Config() (misc.Config, error) {
+	overrides := make(map[string]string)
+	for key, value := range problem.Settings {
+		overrides[key] = value
+	}
+	if problem.DataPath != "" {
+		overrides["DataPath"] = problem.DataPath
+	}
+	if problem.TravelMode != "" {
+		overrides["TravelMode"] = problem.TravelMode
+	}
+	if _, ok := overrides["TransitDayOfWeek"]; !ok && problem.DayOfWeek != "" {
+		// transit timetable runs on the day of the route unless settings ask for another day
+		overrides["TransitDayOfWeek"] = string(problem.DayOfWeek)
+	}
+	if problem.Seed != 0 {
+		overrides["Seed"] = strconv.FormatInt(problem.Seed, 10)
+	}
+	return misc.LoadConfig(problem.ConfigPath, overrides)
+}
+
+// positions are locations of the problem resolved to positions in dataset.
+type positions struct {
+	start      int
+	end        int
+	compulsory []int
+	reference  []int
+	forbidden  []int
+	// fence holds geofences of the problem, outside are positions of locations which geofences do not
+	// allow and excluded are positions of locations which geofences or filters of the problem do not allow.
+	fence    geofence.Fence
+	outside  map[int]bool
+	excluded map[int]bool
+	penalty  float64
+	// diversity is a penalty of repeated categories.
+	diversity float64
+}
+
+// resolve maps IDs of problem locations to positions of loaded points. Start and end are resolved
+// if they are set, forbidden locations which are not in dataset are skipped. Locations which
+// geofences or filters of the problem do not allow are excluded.
+func (problem Problem) resolve(locations generic.Points) (positions, error) {
+	var p positions
+	var err error
+	if problem.AllowedAreas != "" {
+		if p.fence.Allowed, err = geofence.ReadArea(problem.AllowedAreas); err != nil {
+			return p, err
+		}
+	}
+	if problem.ForbiddenAreas != "" {
+		if p.fence.Forbidden, err = geofence.ReadArea(problem.ForbiddenAreas); err != nil {
+			return p, err
+		}
+	}
+	if problem.CrossingPenalty < 0 || (problem.CrossingPenalty > 0 && len(p.fence.Forbidden) == 0) {
+		return p, fmt.Errorf("crossing penalty should be positive and requires forbidden areas, got %v",
+			problem.CrossingPenalty)
+	}
+	p.penalty = problem.CrossingPenalty
+	if problem.DiversityPenalty < 0 {
+		return p, fmt.Errorf("diversity penalty should not be negative, got %v", problem.DiversityPenalty)
+	}
+	p.diversity = problem.DiversityPenalty
+	var include, exclude *query.Expression
+	if problem.Include != "" {
+		if include, err = query.Parse(problem.Include); err != nil {
+			return p, err
+		}
+	}
+	if problem.Exclude != "" {
+		if exclude, err = query.Parse(problem.Exclude); err != nil {
+			return p, err
+		}
+	}
+	if !p.fence.Empty() || include != nil || exclude != nil {
+		p.outside = make(map[int]bool)
+		p.excluded = make(map[int]bool)
+		for i, location := range locations.GetAllPoints() {
+			if !p.fence.Allows(location.LatLng()) {
+				p.outside[i] = true
+				p.excluded[i] = true
+			} else if (include != nil && !include.Match(location)) || (exclude != nil && exclude.Match(location)) {
+				p.excluded[i] = true
+			}
+		}
+	}
+	if problem.StartID != "" {
+		if p.start, err = generic.ResolveID(locations, "start", problem.StartID); err != nil {
+			return p, err
+		}
+	}
+	if problem.EndID != "" {
+		if p.end, err = generic.ResolveID(locations, "end", problem.EndID); err != nil {
+			return p, err
+		}
+	}
+	for _, id := range problem.CompulsoryLocations {
+		position, err := generic.ResolveID(locations, "compulsory", id)
+		if err != nil {
+			return p, err
+		}
+		p.compulsory = append(p.compulsory, position)
+	}
+	for _, id := range problem.ReferencePath {
+		position, err := generic.ResolveID(locations, "reference path", id)
+		if err != nil {
+			return p, err
+		}
+		p.reference = append(p.reference, position)
+	}
+	for _, id := range problem.ForbiddenLocations {
+		if position, ok := generic.PositionOf(locations, id); ok {
+			p.forbidden = append(p.forbidden, position)
+		}
+	}
+	return p, nil
+}
+
+func (problem Problem) task(p positions) generic.Task {
+	return generic.Task{
+		StartID:             p.start,
+		EndID:               p.end,
+		TimeLimit:           problem.TimeLimit,
+		StartTime:           problem.StartTime,
+		DayOfWeek:           string(problem.DayOfWeek),
+		ForbiddenLocations:  p.forbidden,
+		CompulsoryLocations: p.compulsory,
+		Days:                problem.Days,
+		Seed:                problem.Seed,
+	}
+}
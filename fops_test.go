@@ -0,0 +1,183 @@
+package fops
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/score"
+)
+
+// location has coordinates and duration, other methods of generic.Point are not used.
+type location struct {
+	generic.Point
+	lat, lng float64
+	duration float64
+}
+
+func (l location) LatLng() (float64, float64) {
+	return l.lat, l.lng
+}
+
+func (l location) Attribute(name string) float64 {
+	if name == "duration" {
+		return l.duration
+	}
+	return 0
+}
+
+func (l location) CategoryNames() []string {
+	return nil
+}
+
+type dataset struct {
+	generic.Points
+	locations []generic.Point
+}
+
+func (d dataset) GetAllPoints() []generic.Point {
+	return d.locations
+}
+
+// TestResolveExcluded checks that geofences exclude all locations and filters exclude only sights.
+func TestResolveExcluded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	forbidden := filepath.Join(dir, "forbidden.geojson")
+	area := `{"type": "Polygon", "coordinates": [[[37.0, 55.0], [38.0, 55.0], [38.0, 56.0], [37.0, 56.0], [37.0, 55.0]]]}`
+	if err := ioutil.WriteFile(forbidden, []byte(area), 0644); err != nil {
+		t.Fatal(err)
+	}
+	locations := dataset{locations: []generic.Point{
+		location{lat: 55.5, lng: 37.5, duration: 90},
+		location{lat: 59.9, lng: 30.3, duration: 30},
+		location{lat: 59.9, lng: 30.3, duration: 90},
+	}}
+
+	tests := []struct {
+		name     string
+		problem  Problem
+		outside  map[int]bool
+		excluded map[int]bool
+	}{
+		{"none", Problem{}, nil, nil},
+		{"filters", Problem{Include: "duration >= 60"}, map[int]bool{}, map[int]bool{1: true}},
+		{"geofences", Problem{ForbiddenAreas: forbidden}, map[int]bool{0: true}, map[int]bool{0: true}},
+		{"geofences and filters", Problem{ForbiddenAreas: forbidden, Exclude: "duration < 60"},
+			map[int]bool{0: true}, map[int]bool{0: true, 1: true}},
+	}
+	for _, test := range tests {
+		p, err := test.problem.resolve(locations)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(p.outside, test.outside) || !reflect.DeepEqual(p.excluded, test.excluded) {
+			t.Errorf("%s: outside %v excluded %v, want %v %v", test.name, p.outside, p.excluded, test.outside, test.excluded)
+		}
+	}
+}
+
+// TestSolveExcluded checks that locations outside of geofences and rejected by filters never appear
+// in solved routes while the time budget is enough to visit all locations.
+func TestSolveExcluded(t *testing.T) {
+	config, remove := testCity(t)
+	defer remove()
+	dir := filepath.Dir(config)
+	// location 13 is in forbidden area, location 12 is outside of allowed area
+	areas := map[string]string{
+		"forbidden.geojson": `{"type": "Polygon", "coordinates": [[[37.624, 55.749], [37.626, 55.749], [37.626, 55.751], [37.624, 55.751]]]}`,
+		"allowed.geojson":   `{"type": "Polygon", "coordinates": [[[37.61, 55.745], [37.63, 55.745], [37.63, 55.755], [37.61, 55.755]]]}`,
+	}
+	for name, area := range areas {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(area), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := Problem{ConfigPath: config, StartID: "10", EndID: "15", TimeLimit: 300}
+	restricted := base
+	restricted.ForbiddenAreas = filepath.Join(dir, "forbidden.geojson")
+	restricted.AllowedAreas = filepath.Join(dir, "allowed.geojson")
+	restricted.Exclude = "instagram_visitorsNumber == 200"
+
+	for _, kind := range []string{OP, OPTW, TDOP} {
+		for _, algorithm := range []string{ACO, RGA} {
+			for seed := int64(1); seed <= 3; seed++ {
+				name := fmt.Sprintf("%s %s seed %d", kind, algorithm, seed)
+				problem := base
+				problem.Kind, problem.Algorithm, problem.Seed = kind, algorithm, seed
+				if kind != OP {
+					problem.StartTime, problem.DayOfWeek = 1000, "1"
+				}
+				itinerary, err := Solve(context.Background(), problem)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				// excluded locations are visited without restrictions
+				visited := visitedIDs(itinerary)
+				if !visited["11"] && !visited["12"] && !visited["13"] {
+					t.Errorf("%s: route %v without restrictions", name, itinerary.IDs)
+				}
+
+				problem.ForbiddenAreas, problem.AllowedAreas, problem.Exclude =
+					restricted.ForbiddenAreas, restricted.AllowedAreas, restricted.Exclude
+				if itinerary, err = Solve(context.Background(), problem); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				visited = visitedIDs(itinerary)
+				if visited["11"] || visited["12"] || visited["13"] {
+					t.Errorf("%s: route %v visits excluded locations 11, 12 or 13", name, itinerary.IDs)
+				}
+				if !visited["10"] || !visited["14"] || !visited["15"] {
+					t.Errorf("%s: route %v does not visit allowed locations 10, 14 and 15", name, itinerary.IDs)
+				}
+			}
+		}
+	}
+}
+
+func visitedIDs(itinerary Itinerary) map[generic.LocationID]bool {
+	visited := make(map[generic.LocationID]bool)
+	for _, id := range itinerary.IDs {
+		visited[id] = true
+	}
+	return visited
+}
+
+func TestResolvePenalties(t *testing.T) {
+	locations := dataset{locations: []generic.Point{location{lat: 55.5, lng: 37.5, duration: 90}}}
+	p, err := Problem{DiversityPenalty: 0.5}.resolve(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := p.penalized(nil, 0, -1)
+	if diversity, ok := sc.(score.DiversityScore); !ok || diversity.Penalty != 0.5 {
+		t.Errorf("penalized score %#v, want diversity score with penalty 0.5", sc)
+	}
+	if sc := (positions{}).penalized(nil, 0, -1); sc != nil {
+		t.Errorf("penalized score %#v without penalties", sc)
+	}
+
+	tests := []struct {
+		name    string
+		problem Problem
+		err     string
+	}{
+		{"negative diversity penalty", Problem{DiversityPenalty: -1}, "diversity penalty should not be negative, got -1"},
+		{"crossing penalty without areas", Problem{CrossingPenalty: 1},
+			"crossing penalty should be positive and requires forbidden areas, got 1"},
+	}
+	for _, test := range tests {
+		if _, err := test.problem.resolve(locations); err == nil || err.Error() != test.err {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
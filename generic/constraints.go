@@ -1,7 +1,7 @@
 package generic
 
 type Constraints interface {
-	Init(locations []Point) Constraints
+	Init(solver *Solver) (Constraints, error)
 	Boundary(route map[int]Point, orderOfPoints []int) bool
 	SinglePointConstraints(place Point, id int) bool
 	ReducePoints(route map[int]Point, orderOfLocations []int, locations map[int]Point) map[int]Point
@@ -0,0 +1,39 @@
+package generic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoPoints is returned when dataset has no locations.
+var ErrNoPoints = errors.New("dataset has no locations")
+
+// CheckPointIndex returns UnknownLocationError if id is not a position of points.
+func CheckPointIndex(role string, id int, points []Point) error {
+	if id < 0 || id >= len(points) {
+		return UnknownLocationError{Role: role, ID: PositionID(id)}
+	}
+	return nil
+}
+
+// CheckStartEnd checks indices of start and end locations.
+func CheckStartEnd(startID int, endID int, points []Point) error {
+	if err := CheckPointIndex("start", startID, points); err != nil {
+		return err
+	}
+	return CheckPointIndex("end", endID, points)
+}
+
+// UnknownLocationError is returned when dataset has no location with external ID.
+type UnknownLocationError struct {
+	// Role is a purpose of the location in the problem, e.g. "start", "end" or "compulsory".
+	Role string
+	ID   LocationID
+}
+
+func (e UnknownLocationError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("%s location is not specified", e.Role)
+	}
+	return fmt.Sprintf("%s location %q is not in dataset", e.Role, string(e.ID))
+}
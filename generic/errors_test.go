@@ -0,0 +1,52 @@
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCheckStartEnd(t *testing.T) {
+	points := make([]Point, 3)
+	tests := []struct {
+		start, end int
+		err        error
+	}{
+		{0, 2, nil},
+		{1, 1, nil},
+		{3, 0, UnknownLocationError{Role: "start", ID: "3"}},
+		{0, -1, UnknownLocationError{Role: "end", ID: "-1"}},
+	}
+	for _, test := range tests {
+		err := CheckStartEnd(test.start, test.end, points)
+		if err != test.err {
+			t.Errorf("CheckStartEnd(%d, %d) = %v, want %v", test.start, test.end, err, test.err)
+		}
+	}
+}
+
+// dataset has locations without IDs, other methods of Points are not used.
+type dataset struct {
+	Points
+	points []Point
+}
+
+func (d dataset) GetAllPoints() []Point {
+	return d.points
+}
+
+// TestUnknownLocationError checks that positions and IDs are reported by the same error type.
+func TestUnknownLocationError(t *testing.T) {
+	points := dataset{points: make([]Point, 2)}
+	_, resolveErr := ResolveID(points, "start", "5")
+	tests := []error{
+		fmt.Errorf("fops: %w", CheckPointIndex("compulsory", 2, points.GetAllPoints())),
+		fmt.Errorf("fops: %w", resolveErr),
+	}
+	for _, err := range tests {
+		var unknown UnknownLocationError
+		if !errors.As(err, &unknown) {
+			t.Errorf("%v is not UnknownLocationError", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// LocationID is an external ID of dataset location, e.g. 15 or "node/123". Datasets and requests write it
+// as an integer or a string. Positions of locations are internal IDs used by scores, constraints and
+// algorithms, locations without external ID are identified by their position.
+type LocationID string
+
+// PositionID returns ID of location which has no external ID.
+func PositionID(position int) LocationID {
+	return LocationID(strconv.Itoa(position))
+}
+
+// UnmarshalJSON accepts strings, integers and null.
+func (id *LocationID) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		*id = LocationID(v)
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err != nil {
+			return fmt.Errorf("location id %s should be an integer or a string", data)
+		}
+		*id = LocationID(v.String())
+	case nil:
+		*id = ""
+	default:
+		return fmt.Errorf("location id %s should be an integer or a string", data)
+	}
+	return nil
+}
+
+// MarshalJSON writes integer IDs as numbers, so IDs keep their type in outputs.
+func (id LocationID) MarshalJSON() ([]byte, error) {
+	if n, err := strconv.ParseInt(string(id), 10, 64); err == nil && strconv.FormatInt(n, 10) == string(id) {
+		return []byte(id), nil
+	}
+	return json.Marshal(string(id))
+}
+
+// IdentifiedPoints are points with external IDs of locations.
+// Points without these methods use positions of locations as IDs.
+type IdentifiedPoints interface {
+	Points
+	// Position returns position of location with ID, ok is false if dataset has no such location.
+	Position(id LocationID) (position int, ok bool)
+	// ID returns external ID of location at position.
+	ID(position int) LocationID
+}
+
+// PositionOf returns position of location with ID.
+func PositionOf(points Points, id LocationID) (int, bool) {
+	if identified, ok := points.(IdentifiedPoints); ok {
+		return identified.Position(id)
+	}
+	position, err := strconv.Atoi(string(id))
+	if err != nil || position < 0 || position >= len(points.GetAllPoints()) {
+		return 0, false
+	}
+	return position, true
+}
+
+// IDOf returns ID of location at position.
+func IDOf(points Points, position int) LocationID {
+	if identified, ok := points.(IdentifiedPoints); ok {
+		return identified.ID(position)
+	}
+	return PositionID(position)
+}
+
+// ResolveID returns position of location with ID or UnknownLocationError.
+func ResolveID(points Points, role string, id LocationID) (int, error) {
+	position, ok := PositionOf(points, id)
+	if !ok {
+		return 0, UnknownLocationError{Role: role, ID: id}
+	}
+	return position, nil
+}
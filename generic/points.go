@@ -1,10 +1,77 @@
 package generic
 
-type Point interface{}
+import (
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// Point is a location of dataset. Scores, constraints and distance functions access locations
+// only through this interface, so any dataset schema can be used with them.
+type Point interface {
+	// Name returns human readable name of the location.
+	Name() string
+	// Coordinates returns projected coordinates of the location in meters, all locations of a dataset
+	// share one projection, see ProjectedPoints.
+	Coordinates() (x float64, y float64)
+	// LatLng returns geographic coordinates of the location in degrees.
+	LatLng() (lat float64, lng float64)
+	// VisitDuration returns time of visit in minutes.
+	VisitDuration() int
+	// OpeningHours returns opening and closing time in HHMM format for the day of week, "0" is Sunday.
+	// ok is false if the location does not work this day.
+	OpeningHours(dayOfWeek string) (open int, close int, ok bool)
+	// Attribute returns numeric attribute by name of the dataset field, e.g. "instagram_visitorsNumber".
+	// Length is returned for list attributes and zero for unknown attributes.
+	Attribute(name string) float64
+	// CategoryNames returns categories of the location.
+	CategoryNames() []string
+}
 
 type Points interface {
-	Init(solver *Solver) Points
+	Init(solver *Solver) (Points, error)
+	// GetAllPoints returns locations by position in dataset, it may return the same slice on every call,
+	// so callers should not modify it.
 	GetAllPoints() []Point
 	GetCurrentPoints() map[int]Point
 	GetPointsInArea(startID int, endID int) map[int]Point
 }
+
+// ProjectedPoints are points which know projection of their coordinates.
+// Points without this method are supposed to use geo.WebMercator.
+type ProjectedPoints interface {
+	Points
+	Projection() geo.Projection
+}
+
+// ProjectionOf returns projection of point coordinates.
+func ProjectionOf(points Points) geo.Projection {
+	if projected, ok := points.(ProjectedPoints); ok && projected.Projection() != nil {
+		return projected.Projection()
+	}
+	return geo.WebMercator{}
+}
+
+// IndexedPoints are points with spatial index of coordinates built on initialization,
+// item IDs of the index are positions of locations in GetAllPoints.
+type IndexedPoints interface {
+	Points
+	Index() *spatial.KDTree
+}
+
+// IndexOf returns spatial index of points, it is built if points are not indexed.
+func IndexOf(points Points) *spatial.KDTree {
+	if indexed, ok := points.(IndexedPoints); ok && indexed.Index() != nil {
+		return indexed.Index()
+	}
+	return NewIndex(points.GetAllPoints())
+}
+
+// NewIndex builds spatial index of location coordinates, item IDs are positions of locations.
+func NewIndex(locations []Point) *spatial.KDTree {
+	items := make([]spatial.Item, len(locations))
+	for i, location := range locations {
+		x, y := location.Coordinates()
+		items[i] = spatial.Item{ID: i, X: x, Y: y}
+	}
+	return spatial.NewKDTree(items)
+}
@@ -0,0 +1,173 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Component types of the registry.
+const (
+	AlgorithmComponent   = "algorithm"
+	ScoreComponent       = "score"
+	ConstraintsComponent = "constraints"
+	PointsComponent      = "points"
+)
+
+// Task holds parameters of a routing request which are used to construct solver components.
+// Locations are positions in dataset. Points are constructed before IDs of locations are resolved,
+// so their task has no locations.
+type Task struct {
+	StartID   int
+	EndID     int
+	TimeLimit int
+	// StartTime is a time of the day in HHMM format.
+	StartTime int
+	// DayOfWeek is a key of location open hours, "0" is Sunday.
+	DayOfWeek           string
+	ForbiddenLocations  []int
+	CompulsoryLocations []int
+	Days                int
+	Seed                int64
+}
+
+// Constructors of solver components, they are registered by name.
+// Constructor returns error if the task is not supported by the component.
+type (
+	AlgorithmConstructor   func(task Task) (PathAlgorithm, error)
+	ScoreConstructor       func(task Task) (Score, error)
+	ConstraintsConstructor func(task Task) (Constraints, error)
+	PointsConstructor      func(task Task) (Points, error)
+)
+
+var registry = struct {
+	sync.RWMutex
+	// constructors are keyed by component type and lower case name.
+	constructors map[string]map[string]interface{}
+}{
+	constructors: map[string]map[string]interface{}{
+		AlgorithmComponent:   {},
+		ScoreComponent:       {},
+		ConstraintsComponent: {},
+		PointsComponent:      {},
+		TravelComponent:      {},
+	},
+}
+
+// RegisterAlgorithm makes path algorithm available by name, names are case insensitive.
+// It panics if the name is already registered, so it is supposed to be called from init functions.
+func RegisterAlgorithm(name string, constructor AlgorithmConstructor) {
+	register(AlgorithmComponent, name, constructor)
+}
+
+// RegisterScore makes score available by name, see RegisterAlgorithm.
+func RegisterScore(name string, constructor ScoreConstructor) {
+	register(ScoreComponent, name, constructor)
+}
+
+// RegisterConstraints makes constraints available by name, see RegisterAlgorithm.
+func RegisterConstraints(name string, constructor ConstraintsConstructor) {
+	register(ConstraintsComponent, name, constructor)
+}
+
+// RegisterPoints makes points available by name, see RegisterAlgorithm.
+func RegisterPoints(name string, constructor PointsConstructor) {
+	register(PointsComponent, name, constructor)
+}
+
+func register(component string, name string, constructor interface{}) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	key := strings.ToLower(name)
+	if key == "" {
+		panic(fmt.Sprintf("generic: empty %s name", component))
+	}
+	if _, ok := registry.constructors[component][key]; ok {
+		panic(fmt.Sprintf("generic: %s %q is already registered", component, name))
+	}
+	registry.constructors[component][key] = constructor
+}
+
+func lookup(component string, name string) (interface{}, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	constructor, ok := registry.constructors[component][strings.ToLower(name)]
+	if !ok {
+		return nil, UnknownComponentError{component, name, registeredNames(component)}
+	}
+	return constructor, nil
+}
+
+// UnknownComponentError is returned when component with requested name is not registered.
+type UnknownComponentError struct {
+	// Component is a component type, e.g. ScoreComponent.
+	Component string
+	Name      string
+	// Known are registered names of the component type.
+	Known []string
+}
+
+func (e UnknownComponentError) Error() string {
+	return fmt.Sprintf("unknown %s %q, registered: %s", e.Component, e.Name, strings.Join(e.Known, ", "))
+}
+
+// NewAlgorithm constructs registered path algorithm.
+func NewAlgorithm(name string, task Task) (PathAlgorithm, error) {
+	constructor, err := lookup(AlgorithmComponent, name)
+	if err != nil {
+		return nil, err
+	}
+	return constructor.(AlgorithmConstructor)(task)
+}
+
+// NewScore constructs registered score.
+func NewScore(name string, task Task) (Score, error) {
+	constructor, err := lookup(ScoreComponent, name)
+	if err != nil {
+		return nil, err
+	}
+	return constructor.(ScoreConstructor)(task)
+}
+
+// NewConstraints constructs registered constraints.
+func NewConstraints(name string, task Task) (Constraints, error) {
+	constructor, err := lookup(ConstraintsComponent, name)
+	if err != nil {
+		return nil, err
+	}
+	return constructor.(ConstraintsConstructor)(task)
+}
+
+// NewPoints constructs registered points.
+func NewPoints(name string, task Task) (Points, error) {
+	constructor, err := lookup(PointsComponent, name)
+	if err != nil {
+		return nil, err
+	}
+	return constructor.(PointsConstructor)(task)
+}
+
+// Components returns registered names by component type.
+func Components() map[string][]string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	components := make(map[string][]string)
+	for component := range registry.constructors {
+		components[component] = registeredNames(component)
+	}
+	return components
+}
+
+// registeredNames should be called with registry lock held.
+func registeredNames(component string) []string {
+	names := make([]string, 0, len(registry.constructors[component]))
+	for name := range registry.constructors[component] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,120 @@
+package generic
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// unregister removes test components, so tests can be run repeatedly.
+func unregister(component string, name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.constructors[component], name)
+}
+
+// testScore is a score of registry tests, other methods of Score are not used.
+type testScore struct {
+	Score
+	task Task
+}
+
+func TestRegistry(t *testing.T) {
+	RegisterScore("Test-Score", func(task Task) (Score, error) {
+		return testScore{task: task}, nil
+	})
+	defer unregister(ScoreComponent, "test-score")
+	unsupported := errors.New("unsupported task")
+	RegisterConstraints("test-constraints", func(task Task) (Constraints, error) {
+		return nil, unsupported
+	})
+	defer unregister(ConstraintsComponent, "test-constraints")
+
+	task := Task{StartID: 1, EndID: 2, TimeLimit: 60}
+	for _, name := range []string{"Test-Score", "test-score", "TEST-SCORE"} {
+		score, err := NewScore(name, task)
+		if err != nil {
+			t.Errorf("NewScore(%q): unexpected error %v", name, err)
+			continue
+		}
+		if got := score.(testScore).task; !reflect.DeepEqual(got, task) {
+			t.Errorf("NewScore(%q) got task %+v, want %+v", name, got, task)
+		}
+	}
+
+	if _, err := NewConstraints("test-constraints", task); err != unsupported {
+		t.Errorf("NewConstraints error %v, want %v", err, unsupported)
+	}
+
+	components := Components()
+	if !contains(components[ScoreComponent], "test-score") {
+		t.Errorf("Components() scores %v, want test-score", components[ScoreComponent])
+	}
+	if contains(components[AlgorithmComponent], "test-score") {
+		t.Errorf("Components() algorithms %v contain score", components[AlgorithmComponent])
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	constructor := func(task Task) (PathAlgorithm, error) { return nil, nil }
+	RegisterAlgorithm("test-algorithm", constructor)
+	defer unregister(AlgorithmComponent, "test-algorithm")
+
+	tests := []struct {
+		name    string
+		panics  bool
+		message string
+	}{
+		{"Test-Algorithm", true, `generic: algorithm "Test-Algorithm" is already registered`},
+		{"", true, "generic: empty algorithm name"},
+		{"test-other-algorithm", false, ""},
+	}
+	for _, test := range tests {
+		message := func() (message interface{}) {
+			defer func() { message = recover() }()
+			RegisterAlgorithm(test.name, constructor)
+			return nil
+		}()
+		if !test.panics {
+			if message != nil {
+				t.Errorf("RegisterAlgorithm(%q) panics %v", test.name, message)
+			}
+			unregister(AlgorithmComponent, test.name)
+			continue
+		}
+		if message != test.message {
+			t.Errorf("RegisterAlgorithm(%q) panics %v, want %q", test.name, message, test.message)
+		}
+	}
+}
+
+func TestRegistryUnknown(t *testing.T) {
+	RegisterPoints("test-points", func(task Task) (Points, error) { return nil, nil })
+	defer unregister(PointsComponent, "test-points")
+
+	_, err := NewPoints("missing", Task{})
+	var unknown UnknownComponentError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("NewPoints error %v, want UnknownComponentError", err)
+	}
+	if unknown.Component != PointsComponent || unknown.Name != "missing" || !contains(unknown.Known, "test-points") {
+		t.Errorf("NewPoints error %+v", unknown)
+	}
+
+	// names are registered by component type
+	if _, err := NewScore("test-points", Task{}); err == nil {
+		t.Errorf("NewScore found points")
+	}
+	if _, err := NewAlgorithm("test-points", Task{}); err == nil {
+		t.Errorf("NewAlgorithm found points")
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,7 @@
 package generic
 
 type Score interface {
-	Init(points []Point) Score
+	Init(solver *Solver) (Score, error)
 	SinglePointScore(route map[int]Point, orderOfPoints []int, place Point, id int) float64
 	RouteScore(route map[int]Point, orderOfPoints []int) float64
 	UpdateScore(route map[int]Point, orderOfPoints []int, locations map[int]Point) Score
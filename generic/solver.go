@@ -1,6 +1,7 @@
 package generic
 
 import (
+	"github.com/mukhinaks/fops/geo"
 	"github.com/mukhinaks/fops/misc"
 )
 
@@ -9,21 +10,71 @@ type Solver struct {
 	Score         Score
 	Points        Points
 	Constraints   Constraints
-	Configuration map[string]interface{}
+	Configuration misc.Config
+	// Travel is used by constraints and scores, it is created from configuration on start if nil.
+	Travel TravelModel
+	// Projection of point coordinates, it is set on start.
+	Projection geo.Projection
+	// loaded is true if points are loaded by Load.
+	loaded bool
 }
 
-func (solver *Solver) Start(configPath string) {
-	solver.Configuration = misc.ReadConfig(configPath)
-	solver.Points = solver.Points.Init(solver)
-	points := solver.Points.GetAllPoints()
+// Load sets configuration, creates travel model and loads points. Start calls it if points are not loaded,
+// so external IDs of locations can be resolved before score and constraints are created.
+func (solver *Solver) Load(config misc.Config) error {
+	solver.Configuration = config
+	if solver.Travel == nil {
+		travel, err := NewTravelModel(config.TravelMode, config)
+		if err != nil {
+			return err
+		}
+		solver.Travel = travel
+	}
+
+	points, err := solver.Points.Init(solver)
+	if err != nil {
+		return err
+	}
+	solver.Points = points
+	solver.loaded = true
+	return nil
+}
+
+// Start loads points unless they are loaded by Load and initializes travel model, algorithm, score and constraints.
+func (solver *Solver) Start(config misc.Config) error {
+	if !solver.loaded {
+		if err := solver.Load(config); err != nil {
+			return err
+		}
+	}
+	solver.Projection = ProjectionOf(solver.Points)
+	if travel, ok := solver.Travel.(PreparedTravelModel); ok {
+		if err := travel.Prepare(solver); err != nil {
+			return err
+		}
+	}
 	solver.Algorithm = solver.Algorithm.Init(solver)
-	solver.Score = solver.Score.Init(points)
-	solver.Constraints = solver.Constraints.Init(points)
+	return solver.initInterval()
+}
+
+func (solver *Solver) NextInterval() (map[int]Point, []int, float64, error) {
+	if err := solver.initInterval(); err != nil {
+		return nil, nil, 0, err
+	}
+	route, order, score := solver.Algorithm.CreateRoute()
+	return route, order, score, nil
 }
 
-func (solver *Solver) NextInterval() (map[int]Point, []int, float64) {
-	points := solver.Points.GetAllPoints()
-	solver.Score = solver.Score.Init(points)
-	solver.Constraints = solver.Constraints.Init(points)
-	return solver.Algorithm.CreateRoute()
+func (solver *Solver) initInterval() error {
+	score, err := solver.Score.Init(solver)
+	if err != nil {
+		return err
+	}
+	constraints, err := solver.Constraints.Init(solver)
+	if err != nil {
+		return err
+	}
+	solver.Score = score
+	solver.Constraints = constraints
+	return nil
 }
@@ -0,0 +1,46 @@
+package generic
+
+import (
+	"github.com/mukhinaks/fops/misc"
+)
+
+// TravelModel computes travel time between locations.
+type TravelModel interface {
+	// TravelTime returns travel time in minutes.
+	TravelTime(from Point, to Point) int
+}
+
+// PreparedTravelModel is a travel model which precomputes data for dataset locations,
+// solver calls Prepare after points initialization and projection setup.
+type PreparedTravelModel interface {
+	TravelModel
+	Prepare(solver *Solver) error
+}
+
+// TimeDependentTravelModel computes travel time depending on departure time, e.g. by timetables.
+type TimeDependentTravelModel interface {
+	TravelModel
+	// TravelTimeAt returns travel time in minutes for departure at time of the day in HHMM format.
+	TravelTimeAt(from Point, to Point, departure int) int
+}
+
+// TravelComponent is a component type of travel models in the registry.
+const TravelComponent = "travel"
+
+// TravelModelConstructor creates travel model from solver configuration.
+type TravelModelConstructor func(config misc.Config) (TravelModel, error)
+
+// RegisterTravelModel makes travel model available by name, see RegisterAlgorithm.
+// Travel model is selected by TravelMode of configuration.
+func RegisterTravelModel(name string, constructor TravelModelConstructor) {
+	register(TravelComponent, name, constructor)
+}
+
+// NewTravelModel constructs registered travel model.
+func NewTravelModel(name string, config misc.Config) (TravelModel, error) {
+	constructor, err := lookup(TravelComponent, name)
+	if err != nil {
+		return nil, err
+	}
+	return constructor.(TravelModelConstructor)(config)
+}
@@ -0,0 +1,91 @@
+// Package geo computes geodesic distances and projects geographic coordinates to planar coordinates in meters.
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadius is a mean radius of the Earth in meters.
+const EarthRadius = 6371008.8
+
+// Parameters of WGS 84 ellipsoid, semi-major axis is also a radius of Web Mercator.
+const (
+	wgs84SemiMajorAxis = 6378137.0
+	wgs84Flattening    = 1 / 298.257223563
+	wgs84SemiMinorAxis = wgs84SemiMajorAxis * (1 - wgs84Flattening)
+)
+
+// ErrNoConvergence is returned by Vincenty for nearly antipodal points.
+var ErrNoConvergence = errors.New("geo: vincenty formula does not converge")
+
+// Radians converts degrees to radians.
+func Radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// Degrees converts radians to degrees.
+func Degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// Haversine returns great-circle distance in meters between points given in degrees.
+// Error of the spherical model is up to 0.5%.
+func Haversine(lat1 float64, lng1 float64, lat2 float64, lng2 float64) float64 {
+	phi1 := Radians(lat1)
+	phi2 := Radians(lat2)
+	deltaPhi := Radians(lat2 - lat1)
+	deltaLambda := Radians(lng2 - lng1)
+
+	a := math.Pow(math.Sin(deltaPhi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(deltaLambda/2), 2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return EarthRadius * c
+}
+
+// Vincenty returns distance in meters between points given in degrees on WGS 84 ellipsoid,
+// it is accurate to millimeters. ErrNoConvergence is returned for nearly antipodal points.
+func Vincenty(lat1 float64, lng1 float64, lat2 float64, lng2 float64) (float64, error) {
+	const (
+		a = wgs84SemiMajorAxis
+		b = wgs84SemiMinorAxis
+		f = wgs84Flattening
+	)
+
+	L := Radians(lng2 - lng1)
+	U1 := math.Atan((1 - f) * math.Tan(Radians(lat1)))
+	U2 := math.Atan((1 - f) * math.Tan(Radians(lat2)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Sqrt(math.Pow(cosU2*sinLambda, 2) + math.Pow(cosU1*sinU2-sinU1*cosU2*cosLambda, 2))
+		if sinSigma == 0 {
+			// coincident points
+			return 0, nil
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cosSqAlpha != 0 {
+			// points on equator have cosSqAlpha equal to zero
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		previous := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-previous) < 1e-12 {
+			uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+			A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return b * A * (sigma - deltaSigma), nil
+		}
+	}
+	return 0, ErrNoConvergence
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{"same point", 59.93, 30.31, 59.93, 30.31, 0},
+		{"one degree of meridian", 0, 0, 1, 0, EarthRadius * math.Pi / 180},
+		{"quarter of equator", 0, 0, 0, 90, EarthRadius * math.Pi / 2},
+		// short distances are planar: 0.0047 degrees of meridian and 0.0097 degrees of parallel at 59.94
+		{"short distance", 59.9390, 30.3158, 59.9343, 30.3061,
+			math.Hypot(EarthRadius*Radians(0.0047), EarthRadius*Radians(0.0097)*math.Cos(Radians(59.9365)))},
+	}
+	for _, test := range tests {
+		got := Haversine(test.lat1, test.lng1, test.lat2, test.lng2)
+		if math.Abs(got-test.want) > 0.01*test.want+1e-6 {
+			t.Errorf("%s: Haversine = %.1f, want %.1f", test.name, got, test.want)
+		}
+	}
+}
+
+// TestVincenty compares distances with published geodesics of WGS 84 ellipsoid (GeographicLib).
+func TestVincenty(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{"same point", 59.93, 30.31, 59.93, 30.31, 0},
+		{"one degree of equator", 0, 0, 0, 1, 111319.491},
+		{"one degree of meridian", 0, 0, 1, 0, 110574.389},
+		{"quarter of meridian", 0, 0, 90, 0, 10001965.729},
+		{"quarter of equator", 0, 0, 0, 90, 10018754.171},
+		// Flinders Peak to Buninyong of Vincenty's paper: 37°57'03.72030"S 144°25'29.52440"E
+		// to 37°39'10.15610"S 143°55'35.38390"E
+		{"Flinders Peak to Buninyong", -(37 + 57/60.0 + 3.72030/3600), 144 + 25/60.0 + 29.52440/3600,
+			-(37 + 39/60.0 + 10.15610/3600), 143 + 55/60.0 + 35.38390/3600, 54972.271},
+		{"nearly antipodal", 0, 0, 0.5, 179.5, 19936288.579},
+	}
+	for _, test := range tests {
+		got, err := Vincenty(test.lat1, test.lng1, test.lat2, test.lng2)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("%s: Vincenty = %.4f, want %.3f", test.name, got, test.want)
+		}
+	}
+
+	// the formula does not converge for nearly antipodal points, e.g. geodesic to 0.5, 179.7 is 19944127.421 meters
+	for _, point := range [][2]float64{{0.5, 179.7}, {0, 179.5}} {
+		if d, err := Vincenty(0, 0, point[0], point[1]); err != ErrNoConvergence {
+			t.Errorf("Vincenty(0, 0, %v, %v) = %v, %v, want %v", point[0], point[1], d, err, ErrNoConvergence)
+		}
+	}
+}
+
+func TestWebMercatorUnproject(t *testing.T) {
+	for _, point := range [][2]float64{{0, 0}, {59.93, 30.31}, {-33.87, 151.21}, {40.71, -74.01}} {
+		x, y := WebMercator{}.Project(point[0], point[1])
+		lat, lng := WebMercator{}.Unproject(x, y)
+		if math.Abs(lat-point[0]) > 1e-9 || math.Abs(lng-point[1]) > 1e-9 {
+			t.Errorf("Unproject(Project(%v)) = %v, %v", point, lat, lng)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name       string
+		projection Projection
+		lat, lng   float64
+		want       float64
+	}{
+		{"Web Mercator on equator", WebMercator{}, 0, 0, 1},
+		{"Web Mercator in St. Petersburg", WebMercator{}, 59.93, 30.31, 1 / math.Cos(Radians(59.93))},
+		{"local projection at center", Local{CenterLat: 59.93, CenterLng: 30.31}, 59.93, 30.31, 1},
+	}
+	for _, test := range tests {
+		if got := Scale(test.projection, test.lat, test.lng); math.Abs(got-test.want) > 0.01*test.want {
+			t.Errorf("%s: Scale = %.3f, want %.3f", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLocalDistances(t *testing.T) {
+	// distances of local projection are true within 1% in 100 km of the center
+	lats := []float64{59.5, 60.3}
+	lngs := []float64{29.6, 31.0}
+	projection := LocalFor(lats, lngs)
+	points := [][2]float64{{59.93, 30.31}, {59.5, 29.6}, {60.3, 31.0}, {59.72, 30.40}}
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+			x1, y1 := projection.Project(points[i][0], points[i][1])
+			x2, y2 := projection.Project(points[j][0], points[j][1])
+			want := Haversine(points[i][0], points[i][1], points[j][0], points[j][1])
+			if got := math.Hypot(x2-x1, y2-y1); math.Abs(got-want) > 0.01*want {
+				t.Errorf("distance between %v and %v is %.0f, want %.0f", points[i], points[j], got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package geo
+
+import "math"
+
+// Projection converts latitude and longitude in degrees to planar x and y in meters.
+type Projection interface {
+	Project(lat float64, lng float64) (x float64, y float64)
+}
+
+// WebMercator is a projection of the published datasets (EPSG:3857). It is conformal, but distances
+// are stretched by 1/cos(lat), e.g. twice in St. Petersburg.
+type WebMercator struct{}
+
+// Project implements Projection.
+func (WebMercator) Project(lat float64, lng float64) (float64, float64) {
+	x := wgs84SemiMajorAxis * Radians(lng)
+	y := wgs84SemiMajorAxis * math.Log(math.Tan(math.Pi/4+Radians(lat)/2))
+	return x, y
+}
+
+// Unproject returns latitude and longitude in degrees of Web Mercator coordinates.
+func (WebMercator) Unproject(x float64, y float64) (float64, float64) {
+	lng := Degrees(x / wgs84SemiMajorAxis)
+	lat := Degrees(2*math.Atan(math.Exp(y/wgs84SemiMajorAxis)) - math.Pi/2)
+	return lat, lng
+}
+
+// Local is an equirectangular projection with true distances near the center,
+// distance error is below 1% within 100 km of the center in latitudes up to 60°.
+type Local struct {
+	CenterLat float64
+	CenterLng float64
+}
+
+// Project implements Projection.
+func (p Local) Project(lat float64, lng float64) (float64, float64) {
+	x := EarthRadius * Radians(lng-p.CenterLng) * math.Cos(Radians(p.CenterLat))
+	y := EarthRadius * Radians(lat-p.CenterLat)
+	return x, y
+}
+
+// LocalFor returns local projection centered in the middle of bounding box of points.
+func LocalFor(lats []float64, lngs []float64) Local {
+	if len(lats) == 0 || len(lngs) == 0 {
+		return Local{}
+	}
+	minLat, maxLat := lats[0], lats[0]
+	for _, lat := range lats {
+		minLat, maxLat = math.Min(minLat, lat), math.Max(maxLat, lat)
+	}
+	minLng, maxLng := lngs[0], lngs[0]
+	for _, lng := range lngs {
+		minLng, maxLng = math.Min(minLng, lng), math.Max(maxLng, lng)
+	}
+	return Local{CenterLat: (minLat + maxLat) / 2, CenterLng: (minLng + maxLng) / 2}
+}
+
+// Scale returns ratio of projected distance to geodesic distance near the point,
+// e.g. 1/cos(lat) for WebMercator. It is averaged over north and east directions.
+func Scale(p Projection, lat float64, lng float64) float64 {
+	const step = 1e-4 // degrees, about 10 meters
+	x, y := p.Project(lat, lng)
+	xNorth, yNorth := p.Project(lat+step, lng)
+	xEast, yEast := p.Project(lat, lng+step)
+
+	north := math.Hypot(xNorth-x, yNorth-y) / Haversine(lat, lng, lat+step, lng)
+	east := math.Hypot(xEast-x, yEast-y) / Haversine(lat, lng, lat, lng+step)
+	return (north + east) / 2
+}
@@ -0,0 +1,248 @@
+// Package geofence restricts routes to areas: locations outside of allowed areas and inside of forbidden
+// areas are not visited, legs of routes may be checked for crossing of forbidden areas. Areas are polygons
+// and multipolygons of GeoJSON files. Polygons are treated as planar in longitude and latitude, which is
+// accurate for areas of city scale.
+package geofence
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+)
+
+// Ring is a closed line of [lng, lat] positions, the last position may repeat the first one.
+type Ring [][2]float64
+
+// Polygon is an outer ring with holes, polygons are created by ParseArea.
+type Polygon struct {
+	Outer Ring
+	Holes []Ring
+	// bounds are minLng, minLat, maxLng, maxLat of the outer ring.
+	bounds [4]float64
+}
+
+// newPolygon returns polygon of GeoJSON rings, the first ring is outer.
+func newPolygon(rings []Ring) (Polygon, error) {
+	if len(rings) == 0 {
+		return Polygon{}, fmt.Errorf("polygon has no rings")
+	}
+	for _, ring := range rings {
+		if len(ring) < 3 {
+			return Polygon{}, fmt.Errorf("ring should have at least 3 positions, got %d", len(ring))
+		}
+	}
+	p := Polygon{Outer: rings[0], Holes: rings[1:]}
+	p.bounds = [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, position := range p.Outer {
+		p.bounds[0] = math.Min(p.bounds[0], position[0])
+		p.bounds[1] = math.Min(p.bounds[1], position[1])
+		p.bounds[2] = math.Max(p.bounds[2], position[0])
+		p.bounds[3] = math.Max(p.bounds[3], position[1])
+	}
+	return p, nil
+}
+
+// Contains tells if the point is inside of the outer ring and outside of holes. A point of an edge
+// shared by adjacent polygons is contained in exactly one of them.
+func (p Polygon) Contains(lat float64, lng float64) bool {
+	if lng < p.bounds[0] || lat < p.bounds[1] || lng > p.bounds[2] || lat > p.bounds[3] {
+		return false
+	}
+	if !p.Outer.contains(lat, lng) {
+		return false
+	}
+	for _, hole := range p.Holes {
+		if hole.contains(lat, lng) {
+			return false
+		}
+	}
+	return true
+}
+
+// Crosses tells if the segment between points has common points with the polygon.
+func (p Polygon) Crosses(lat1 float64, lng1 float64, lat2 float64, lng2 float64) bool {
+	if math.Max(lng1, lng2) < p.bounds[0] || math.Max(lat1, lat2) < p.bounds[1] ||
+		math.Min(lng1, lng2) > p.bounds[2] || math.Min(lat1, lat2) > p.bounds[3] {
+		return false
+	}
+	if p.Contains(lat1, lng1) || p.Contains(lat2, lng2) {
+		return true
+	}
+	// segment with both ends outside enters the polygon through one of its edges
+	for _, ring := range append([]Ring{p.Outer}, p.Holes...) {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if segmentsIntersect([2]float64{lng1, lat1}, [2]float64{lng2, lat2}, a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contains is an even-odd ray casting test.
+func (r Ring) contains(lat float64, lng float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a[1] > lat) != (b[1] > lat) && lng < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func segmentsIntersect(p1 [2]float64, p2 [2]float64, q1 [2]float64, q2 [2]float64) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(q1, q2, p1)) || (d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) || (d4 == 0 && onSegment(p1, p2, q2))
+}
+
+func orientation(a [2]float64, b [2]float64, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// onSegment tells if collinear point c lies on segment ab.
+func onSegment(a [2]float64, b [2]float64, c [2]float64) bool {
+	return math.Min(a[0], b[0]) <= c[0] && c[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= c[1] && c[1] <= math.Max(a[1], b[1])
+}
+
+// Area is a union of polygons, e.g. a district or a historic centre.
+type Area []Polygon
+
+// Contains tells if the point is inside of one of polygons.
+func (a Area) Contains(lat float64, lng float64) bool {
+	for _, polygon := range a {
+		if polygon.Contains(lat, lng) {
+			return true
+		}
+	}
+	return false
+}
+
+// Crosses tells if the segment between points has common points with one of polygons.
+func (a Area) Crosses(lat1 float64, lng1 float64, lat2 float64, lng2 float64) bool {
+	for _, polygon := range a {
+		if polygon.Crosses(lat1, lng1, lat2, lng2) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fence combines allowed and forbidden areas.
+type Fence struct {
+	// Allowed area is not checked if it is empty.
+	Allowed   Area
+	Forbidden Area
+}
+
+// Allows tells if the point is inside of allowed area and outside of forbidden area.
+func (f Fence) Allows(lat float64, lng float64) bool {
+	if len(f.Allowed) > 0 && !f.Allowed.Contains(lat, lng) {
+		return false
+	}
+	return !f.Forbidden.Contains(lat, lng)
+}
+
+// Crosses tells if the segment between points crosses forbidden area.
+func (f Fence) Crosses(lat1 float64, lng1 float64, lat2 float64, lng2 float64) bool {
+	return f.Forbidden.Crosses(lat1, lng1, lat2, lng2)
+}
+
+// Empty tells if the fence has no areas.
+func (f Fence) Empty() bool {
+	return len(f.Allowed) == 0 && len(f.Forbidden) == 0
+}
+
+// geoJSON is a FeatureCollection, Feature or geometry object.
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Geometries  []geoJSON       `json:"geometries"`
+	Features    []geoJSON       `json:"features"`
+}
+
+// ReadArea reads polygons and multipolygons of GeoJSON file, other geometries are skipped.
+func ReadArea(path string) (Area, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("geofence: %v", err)
+	}
+	area, err := ParseArea(data)
+	if err != nil {
+		return nil, fmt.Errorf("geofence: %s: %v", path, err)
+	}
+	return area, nil
+}
+
+// ParseArea decodes polygons and multipolygons of GeoJSON FeatureCollection, Feature or geometry.
+func ParseArea(data []byte) (Area, error) {
+	var object geoJSON
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	area := make(Area, 0)
+	if err := object.collect(&area); err != nil {
+		return nil, err
+	}
+	if len(area) == 0 {
+		return nil, fmt.Errorf("no polygons")
+	}
+	return area, nil
+}
+
+func (object geoJSON) collect(area *Area) error {
+	switch object.Type {
+	case "FeatureCollection":
+		for i, feature := range object.Features {
+			if err := feature.collect(area); err != nil {
+				return fmt.Errorf("feature %d: %v", i, err)
+			}
+		}
+	case "Feature":
+		if object.Geometry != nil {
+			return object.Geometry.collect(area)
+		}
+	case "GeometryCollection":
+		for _, geometry := range object.Geometries {
+			if err := geometry.collect(area); err != nil {
+				return err
+			}
+		}
+	case "Polygon":
+		var rings []Ring
+		if err := json.Unmarshal(object.Coordinates, &rings); err != nil {
+			return fmt.Errorf("polygon: %v", err)
+		}
+		polygon, err := newPolygon(rings)
+		if err != nil {
+			return err
+		}
+		*area = append(*area, polygon)
+	case "MultiPolygon":
+		var polygons [][]Ring
+		if err := json.Unmarshal(object.Coordinates, &polygons); err != nil {
+			return fmt.Errorf("multipolygon: %v", err)
+		}
+		for _, rings := range polygons {
+			polygon, err := newPolygon(rings)
+			if err != nil {
+				return err
+			}
+			*area = append(*area, polygon)
+		}
+	case "":
+		return fmt.Errorf("GeoJSON object without type")
+	}
+	return nil
+}
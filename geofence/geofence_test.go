@@ -0,0 +1,217 @@
+package geofence
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// polygon returns polygon of rings of [lng, lat] positions.
+func polygon(t *testing.T, rings ...Ring) Polygon {
+	p, err := newPolygon(rings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// square is [0, 10] x [0, 10] with square hole [4, 6] x [4, 6].
+func square(t *testing.T) Polygon {
+	return polygon(t, Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, Ring{{4, 4}, {6, 4}, {6, 6}, {4, 6}})
+}
+
+// letterU is a concave polygon with a notch [1, 2] x [1, 3] from above.
+func letterU(t *testing.T) Polygon {
+	return polygon(t, Ring{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}})
+}
+
+func TestPolygonContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  Polygon
+		lat, lng float64
+		want     bool
+	}{
+		{"inside of square", square(t), 2, 2, true},
+		{"outside of bounds", square(t), 5, 11, false},
+		{"inside of hole", square(t), 5, 5, false},
+		{"between hole and outer ring", square(t), 8, 5, true},
+		{"left arm of U", letterU(t), 2, 0.5, true},
+		{"right arm of U", letterU(t), 2, 2.5, true},
+		{"bottom of U", letterU(t), 0.5, 1.5, true},
+		{"notch of U", letterU(t), 2, 1.5, false},
+		{"notch of U at the level of vertices", letterU(t), 3, 1.5, false},
+		{"below U", letterU(t), -0.5, 1.5, false},
+	}
+	for _, test := range tests {
+		if got := test.polygon.Contains(test.lat, test.lng); got != test.want {
+			t.Errorf("%s: Contains(%v, %v) = %v, want %v", test.name, test.lat, test.lng, got, test.want)
+		}
+	}
+}
+
+// TestPolygonContainsBoundary checks that points of common edges of adjacent polygons belong to exactly
+// one of them, so partitions of a city never lose or duplicate locations.
+func TestPolygonContainsBoundary(t *testing.T) {
+	partitions := [][]Polygon{
+		// left and right halves of square
+		{polygon(t, Ring{{0, 0}, {5, 0}, {5, 10}, {0, 10}}), polygon(t, Ring{{5, 0}, {10, 0}, {10, 10}, {5, 10}})},
+		// bottom and top halves of square
+		{polygon(t, Ring{{0, 0}, {10, 0}, {10, 5}, {0, 5}}), polygon(t, Ring{{0, 5}, {10, 5}, {10, 10}, {0, 10}})},
+		// triangles of square divided by diagonal
+		{polygon(t, Ring{{0, 0}, {10, 0}, {10, 10}}), polygon(t, Ring{{0, 0}, {10, 10}, {0, 10}})},
+		// square and its hole filled by other polygon
+		{square(t), polygon(t, Ring{{4, 4}, {6, 4}, {6, 6}, {4, 6}})},
+	}
+	points := [][2]float64{{5, 5}, {5, 2.5}, {2.5, 5}, {2.5, 2.5}, {7.5, 7.5}, {4, 5}, {6, 5}, {5, 4}, {5, 6}, {4, 4}}
+	for i, partition := range partitions {
+		for _, point := range points {
+			count := 0
+			for _, p := range partition {
+				if p.Contains(point[0], point[1]) {
+					count++
+				}
+			}
+			if count != 1 {
+				t.Errorf("partition %d: point %v is contained in %d polygons, want 1", i, point, count)
+			}
+		}
+	}
+}
+
+func TestPolygonCrosses(t *testing.T) {
+	tests := []struct {
+		name    string
+		polygon Polygon
+		segment [4]float64
+		want    bool
+	}{
+		{"outside of bounds", square(t), [4]float64{11, 0, 11, 10}, false},
+		{"end inside", square(t), [4]float64{2, 2, 20, 20}, true},
+		{"through polygon", square(t), [4]float64{2, -1, 2, 11}, true},
+		{"along edge", square(t), [4]float64{10, -1, 10, 11}, true},
+		{"through vertex", square(t), [4]float64{11, 9, 9, 11}, true},
+		{"near vertex", square(t), [4]float64{11, 10, 10, 11}, false},
+		{"inside of hole", square(t), [4]float64{5, 4.5, 5, 5.5}, false},
+		{"from hole to polygon", square(t), [4]float64{5, 5, 5, 8}, true},
+		{"across arms of U", letterU(t), [4]float64{2, -1, 2, 4}, true},
+		{"inside of notch of U", letterU(t), [4]float64{4, 1.5, 1.5, 1.5}, false},
+		{"diagonal of notch of U", letterU(t), [4]float64{2.9, 1.1, 1.1, 1.9}, false},
+		{"into bottom of U", letterU(t), [4]float64{4, 1.5, 0.5, 1.5}, true},
+	}
+	for _, test := range tests {
+		s := test.segment
+		if got := test.polygon.Crosses(s[0], s[1], s[2], s[3]); got != test.want {
+			t.Errorf("%s: Crosses(%v) = %v, want %v", test.name, s, got, test.want)
+		}
+		// direction of segment does not matter
+		if got := test.polygon.Crosses(s[2], s[3], s[0], s[1]); got != test.want {
+			t.Errorf("%s: reversed Crosses(%v) = %v, want %v", test.name, s, got, test.want)
+		}
+	}
+}
+
+func TestFence(t *testing.T) {
+	left := Area{polygon(t, Ring{{0, 0}, {5, 0}, {5, 10}, {0, 10}})}
+	right := Area{polygon(t, Ring{{5, 0}, {10, 0}, {10, 10}, {5, 10}})}
+	tests := []struct {
+		name     string
+		fence    Fence
+		lat, lng float64
+		allows   bool
+	}{
+		{"empty fence", Fence{}, 5, 20, true},
+		{"inside of allowed", Fence{Allowed: left}, 5, 2, true},
+		{"outside of allowed", Fence{Allowed: left}, 5, 7, false},
+		{"outside of forbidden", Fence{Forbidden: right}, 5, 2, true},
+		{"inside of forbidden", Fence{Forbidden: right}, 5, 7, false},
+		{"allowed and forbidden", Fence{Allowed: append(Area{}, left[0], right[0]), Forbidden: right}, 5, 7, false},
+	}
+	for _, test := range tests {
+		if got := test.fence.Allows(test.lat, test.lng); got != test.allows {
+			t.Errorf("%s: Allows(%v, %v) = %v, want %v", test.name, test.lat, test.lng, got, test.allows)
+		}
+	}
+	fence := Fence{Allowed: left, Forbidden: right}
+	// only forbidden area is crossed
+	if fence.Crosses(2, 1, 2, 4) || !fence.Crosses(2, 1, 2, 20) {
+		t.Errorf("Crosses checks allowed area")
+	}
+}
+
+func TestParseArea(t *testing.T) {
+	data := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [
+			[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]], [[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]]}},
+		{"type": "Feature", "geometry": null},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}},
+		{"type": "Feature", "geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[20, 0], [30, 0], [30, 10]]], [[[40, 0], [50, 0], [50, 10]]]]}},
+		{"type": "Feature", "geometry": {"type": "GeometryCollection", "geometries": [
+			{"type": "LineString", "coordinates": [[0, 0], [1, 1]]},
+			{"type": "Polygon", "coordinates": [[[60, 0], [70, 0], [70, 10]]]}]}}
+	]}`
+	area, err := ParseArea([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(area) != 4 || len(area[0].Holes) != 1 {
+		t.Fatalf("area has %d polygons, want 4 polygons, the first one with a hole: %v", len(area), area)
+	}
+	// positions are [lng, lat]
+	if !area.Contains(1, 45) || area.Contains(45, 1) || area.Contains(5, 5) {
+		t.Errorf("area contains wrong points")
+	}
+}
+
+func TestParseAreaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"malformed", `{"type": "Polygon",`, "unexpected end of JSON input"},
+		{"no type", `{"coordinates": [[[0, 0], [1, 0], [1, 1]]]}`, "GeoJSON object without type"},
+		{"no polygons", `{"type": "Point", "coordinates": [0, 0]}`, "no polygons"},
+		{"empty collection", `{"type": "FeatureCollection", "features": []}`, "no polygons"},
+		{"polygon without rings", `{"type": "Polygon", "coordinates": []}`, "polygon has no rings"},
+		{"small ring", `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0]]]}`,
+			"ring should have at least 3 positions, got 2"},
+		{"small hole", `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1]], [[0, 0]]]}`,
+			"ring should have at least 3 positions, got 1"},
+		{"invalid polygon", `{"type": "Polygon", "coordinates": [[0, 0], [1, 0], [1, 1]]}`, "polygon: json: "},
+		{"invalid multipolygon", `{"type": "MultiPolygon", "coordinates": [[[0, 0], [1, 0], [1, 1]]]}`,
+			"multipolygon: json: "},
+		{"small ring of multipolygon", `{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1]]], [[]]]}`,
+			"ring should have at least 3 positions, got 0"},
+		{"invalid feature", `{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1]]]}},
+			{"type": "Feature", "geometry": {"coordinates": []}}]}`, "feature 1: GeoJSON object without type"},
+	}
+	for _, test := range tests {
+		_, err := ParseArea([]byte(test.data))
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestReadArea(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geofence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "area.geojson")
+	if err := ioutil.WriteFile(path, []byte(`{"type": "Point", "coordinates": [0, 0]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadArea(path); err == nil || err.Error() != "geofence: "+path+": no polygons" {
+		t.Errorf("ReadArea error %v", err)
+	}
+	if _, err := ReadArea(filepath.Join(dir, "missing.geojson")); err == nil || !strings.HasPrefix(err.Error(), "geofence: ") {
+		t.Errorf("ReadArea error of missing file %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// positionFields are taken together from the source of "lat", so coordinates of location are consistent.
+var positionFields = []string{"lat", "lng", "x", "y"}
+
+// combine builds merged record of cluster by rules and precedence of options.
+func combine(sources []Source, cluster []Member, options Options) Record {
+	members := make(map[string]map[string]interface{}, len(cluster))
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, member := range cluster {
+		for _, source := range sources {
+			if source.Name == member.Source {
+				members[member.Source] = source.Records[member.Index]
+			}
+		}
+		for field := range members[member.Source] {
+			if !seen[field] && field != SourcesField && field != ProvenanceField {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+
+	result := Record{
+		Fields:     make(map[string]interface{}),
+		Members:    cluster,
+		Provenance: make(map[string]string),
+	}
+	// order returns names of member sources by precedence of field
+	order := func(field string) []string {
+		precedence, ok := options.Precedence[field]
+		if !ok {
+			precedence = options.Precedence["*"]
+		}
+		names := make([]string, 0, len(cluster))
+		added := make(map[string]bool)
+		for _, name := range precedence {
+			if _, ok := members[name]; ok && !added[name] {
+				names = append(names, name)
+				added[name] = true
+			}
+		}
+		for _, member := range cluster {
+			if !added[member.Source] {
+				names = append(names, member.Source)
+				added[member.Source] = true
+			}
+		}
+		return names
+	}
+
+	for _, name := range order("lat") {
+		if !isEmpty(members[name]["lat"]) {
+			for _, field := range positionFields {
+				if value, ok := members[name][field]; ok {
+					result.Fields[field] = value
+					result.Provenance[field] = name
+				}
+			}
+			break
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := result.Fields[field]; ok || isPosition(field) {
+			continue
+		}
+		values := make([]interface{}, 0, len(cluster))
+		names := make([]string, 0, len(cluster))
+		for _, name := range order(field) {
+			if value := members[name][field]; !isEmpty(value) {
+				values = append(values, value)
+				names = append(names, name)
+			}
+		}
+		if len(values) == 0 {
+			// all sources have empty value, e.g. 0 or "", it is kept
+			for _, name := range order(field) {
+				if value, ok := members[name][field]; ok {
+					result.Fields[field] = value
+					result.Provenance[field] = name
+					break
+				}
+			}
+			continue
+		}
+		value, provenance := apply(options.Rules[field], values, names)
+		result.Fields[field] = value
+		result.Provenance[field] = provenance
+	}
+	return result
+}
+
+// apply combines non-empty values ordered by precedence, rules of numbers take the first value
+// if some values are not numbers.
+func apply(rule string, values []interface{}, names []string) (interface{}, string) {
+	switch rule {
+	case Max, Min, Mean:
+		numbers := make([]float64, len(values))
+		for i, value := range values {
+			n, ok := number(value)
+			if !ok {
+				return values[0], names[0]
+			}
+			numbers[i] = n
+		}
+		best := 0
+		sum := 0.0
+		for i, n := range numbers {
+			sum += n
+			if (rule == Max && n > numbers[best]) || (rule == Min && n < numbers[best]) {
+				best = i
+			}
+		}
+		if rule == Mean {
+			return sum / float64(len(numbers)), strings.Join(names, ",")
+		}
+		return values[best], names[best]
+	case Union:
+		items := make([]interface{}, 0)
+		seen := make(map[string]bool)
+		contributors := make([]string, 0, len(names))
+		for i, value := range values {
+			list, ok := value.([]interface{})
+			if !ok {
+				list = []interface{}{value}
+			}
+			added := false
+			for _, item := range list {
+				key := fmt.Sprint(item)
+				if !seen[key] {
+					seen[key] = true
+					items = append(items, item)
+					added = true
+				}
+			}
+			if added {
+				contributors = append(contributors, names[i])
+			}
+		}
+		return items, strings.Join(contributors, ",")
+	}
+	return values[0], names[0]
+}
+
+func isPosition(field string) bool {
+	for _, position := range positionFields {
+		if field == position {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty tells that source has no value of field, datasets use zero for unknown numbers.
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	n, ok := number(value)
+	return ok && n == 0
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
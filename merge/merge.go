@@ -0,0 +1,248 @@
+// Package merge combines datasets of several sources (e.g. Foursquare, TripAdvisor, Instagram and
+// Facebook) into one dataset. Records of different sources are matched by distance and title similarity,
+// fields of matched records are combined by precedence rules and their sources are recorded.
+package merge
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// Source is a dataset of one provider, records are dataset locations as generic JSON objects.
+type Source struct {
+	Name    string
+	Records []map[string]interface{}
+}
+
+// Options of matching and combination of records.
+type Options struct {
+	// MaxDistance is a maximum distance in meters between matched records, default 100.
+	MaxDistance float64
+	// MinSimilarity is a minimum TitleSimilarity of matched records, default 0.8.
+	MinSimilarity float64
+	// Precedence lists sources by priority for a field, "*" is used for other fields.
+	// Sources which are not listed follow in order of sources.
+	Precedence map[string][]string
+	// Rules are combination rules of fields, default is First.
+	Rules map[string]string
+}
+
+// Combination rules of fields.
+const (
+	// First takes the value of the first source by precedence which has non-empty value.
+	First = "first"
+	// Max and Min take the largest and the smallest number.
+	Max = "max"
+	Min = "min"
+	// Mean averages numbers.
+	Mean = "mean"
+	// Union joins lists without repeated items in order of precedence.
+	Union = "union"
+)
+
+// Rules are supported combination rules.
+var Rules = []string{First, Max, Min, Mean, Union}
+
+// Member is a source record of merged record.
+type Member struct {
+	Source string `json:"source"`
+	// Index is a position of record in source.
+	Index int `json:"index"`
+}
+
+// Record is a merged location.
+type Record struct {
+	Fields  map[string]interface{}
+	Members []Member
+	// Provenance is a source of every field, sources of combined values are separated by comma.
+	Provenance map[string]string
+}
+
+// Reserved fields are added to records by Map and skipped in source records.
+const (
+	SourcesField    = "sources"
+	ProvenanceField = "provenance"
+)
+
+// Map returns fields of record with members and provenance, so merged dataset keeps its sources.
+func (r Record) Map() map[string]interface{} {
+	result := make(map[string]interface{}, len(r.Fields)+2)
+	for field, value := range r.Fields {
+		result[field] = value
+	}
+	result[SourcesField] = r.Members
+	result[ProvenanceField] = r.Provenance
+	return result
+}
+
+// record is a source record in merge.
+type record struct {
+	source int
+	index  int
+	title  string
+	lat    float64
+	lng    float64
+}
+
+// pair is a candidate match of records.
+type pair struct {
+	a, b       int
+	similarity float64
+	distance   float64
+}
+
+// Merge matches records of different sources and combines them. A merged record has at most one record
+// of each source, best matches by title similarity and then by distance are merged first.
+// Records are ordered by their first member in order of sources.
+func Merge(sources []Source, options Options) ([]Record, error) {
+	if err := options.setDefaults(sources); err != nil {
+		return nil, err
+	}
+
+	records := make([]record, 0)
+	for s, source := range sources {
+		for i, fields := range source.Records {
+			lat, okLat := number(fields["lat"])
+			lng, okLng := number(fields["lng"])
+			if !okLat || !okLng || (lat == 0 && lng == 0) {
+				return nil, fmt.Errorf("merge: source %s: record %d has no lat and lng", source.Name, i)
+			}
+			title, _ := fields["title"].(string)
+			records = append(records, record{source: s, index: i, title: title, lat: lat, lng: lng})
+		}
+	}
+
+	// candidates are searched in WebMercator with radius stretched by its scale and checked by geodesic distance
+	var projection geo.WebMercator
+	items := make([]spatial.Item, len(records))
+	for i, r := range records {
+		x, y := projection.Project(r.lat, r.lng)
+		items[i] = spatial.Item{ID: i, X: x, Y: y}
+	}
+	tree := spatial.NewKDTree(items)
+	pairs := make([]pair, 0)
+	for i, r := range records {
+		radius := options.MaxDistance * geo.Scale(projection, r.lat, r.lng)
+		for _, j := range tree.Radius(items[i].X, items[i].Y, radius*1.01) {
+			other := records[j]
+			if j <= i || other.source == r.source {
+				continue
+			}
+			distance := geo.Haversine(r.lat, r.lng, other.lat, other.lng)
+			if distance > options.MaxDistance {
+				continue
+			}
+			if similarity := TitleSimilarity(r.title, other.title); similarity >= options.MinSimilarity {
+				pairs = append(pairs, pair{a: i, b: j, similarity: similarity, distance: distance})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].similarity != pairs[j].similarity {
+			return pairs[i].similarity > pairs[j].similarity
+		}
+		return pairs[i].distance < pairs[j].distance
+	})
+
+	// clusters are joined while they have no common sources
+	parent := make([]int, len(records))
+	clusterSources := make([]map[int]bool, len(records))
+	for i, r := range records {
+		parent[i] = i
+		clusterSources[i] = map[int]bool{r.source: true}
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, p := range pairs {
+		a, b := find(p.a), find(p.b)
+		if a == b || overlap(clusterSources[a], clusterSources[b]) {
+			continue
+		}
+		if b < a {
+			a, b = b, a
+		}
+		parent[b] = a
+		for source := range clusterSources[b] {
+			clusterSources[a][source] = true
+		}
+	}
+
+	// records are in order of sources, so the root is the first member of the cluster
+	members := make(map[int][]int)
+	roots := make([]int, 0)
+	for i := range records {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+	result := make([]Record, 0, len(roots))
+	for _, root := range roots {
+		cluster := make([]Member, 0, len(members[root]))
+		for _, i := range members[root] {
+			cluster = append(cluster, Member{Source: sources[records[i].source].Name, Index: records[i].index})
+		}
+		result = append(result, combine(sources, cluster, options))
+	}
+	return result, nil
+}
+
+func overlap(a map[int]bool, b map[int]bool) bool {
+	for source := range a {
+		if b[source] {
+			return true
+		}
+	}
+	return false
+}
+
+// setDefaults fills zero options and checks names of sources and rules.
+func (o *Options) setDefaults(sources []Source) error {
+	if o.MaxDistance == 0 {
+		o.MaxDistance = 100
+	}
+	if o.MinSimilarity == 0 {
+		o.MinSimilarity = 0.8
+	}
+	if o.MaxDistance < 0 || math.IsNaN(o.MaxDistance) {
+		return fmt.Errorf("merge: max distance should be positive, got %v", o.MaxDistance)
+	}
+	if o.MinSimilarity < 0 || o.MinSimilarity > 1 {
+		return fmt.Errorf("merge: min similarity should be from 0 to 1, got %v", o.MinSimilarity)
+	}
+
+	names := make(map[string]bool)
+	for _, source := range sources {
+		if source.Name == "" || names[source.Name] {
+			return fmt.Errorf("merge: source names should be unique and non-empty, got %q", source.Name)
+		}
+		names[source.Name] = true
+	}
+	for field, order := range o.Precedence {
+		for _, name := range order {
+			if !names[name] {
+				return fmt.Errorf("merge: precedence of %s: unknown source %q", field, name)
+			}
+		}
+	}
+	for field, rule := range o.Rules {
+		known := false
+		for _, r := range Rules {
+			known = known || r == rule
+		}
+		if !known {
+			return fmt.Errorf("merge: rule of %s should be one of %v, got %q", field, Rules, rule)
+		}
+	}
+	return nil
+}
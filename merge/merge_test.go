@@ -0,0 +1,203 @@
+package merge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testSources have the theatre in both sources within 11 meters and squares 330 meters apart.
+func testSources() []Source {
+	return []Source{
+		{Name: "a", Records: []map[string]interface{}{
+			{"title": "Bolshoi Theatre", "lat": 55.7600, "lng": 37.6186, "x": 1.0, "rating": 4.5,
+				"categories": []interface{}{"Theatre"}, "phone": ""},
+			{"title": "Red Square", "lat": 55.7539, "lng": 37.6208},
+		}},
+		{Name: "b", Records: []map[string]interface{}{
+			{"title": "bolshoi theatre", "lat": 55.7601, "lng": 37.6186, "rating": 4.8,
+				"categories": []interface{}{"Theatre", "Landmark"}, "phone": "+7 495"},
+			{"title": "Red Square", "lat": 55.7569, "lng": 37.6208},
+		}},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    []Record
+	}{
+		{"defaults", Options{}, []Record{
+			{
+				Fields: map[string]interface{}{"title": "Bolshoi Theatre", "lat": 55.7600, "lng": 37.6186, "x": 1.0,
+					"rating": 4.5, "categories": []interface{}{"Theatre"}, "phone": "+7 495"},
+				Members: []Member{{"a", 0}, {"b", 0}},
+				Provenance: map[string]string{"title": "a", "lat": "a", "lng": "a", "x": "a", "rating": "a",
+					"categories": "a", "phone": "b"},
+			},
+			{
+				Fields:     map[string]interface{}{"title": "Red Square", "lat": 55.7539, "lng": 37.6208},
+				Members:    []Member{{"a", 1}},
+				Provenance: map[string]string{"title": "a", "lat": "a", "lng": "a"},
+			},
+			{
+				Fields:     map[string]interface{}{"title": "Red Square", "lat": 55.7569, "lng": 37.6208},
+				Members:    []Member{{"b", 1}},
+				Provenance: map[string]string{"title": "b", "lat": "b", "lng": "b"},
+			},
+		}},
+		{"rules and precedence", Options{
+			MaxDistance: 500,
+			Precedence:  map[string][]string{"*": {"b"}, "title": {"a"}},
+			Rules:       map[string]string{"rating": Mean, "categories": Union},
+		}, []Record{
+			{
+				Fields: map[string]interface{}{"title": "Bolshoi Theatre", "lat": 55.7601, "lng": 37.6186,
+					"rating": 4.65, "categories": []interface{}{"Theatre", "Landmark"}, "phone": "+7 495"},
+				Members: []Member{{"a", 0}, {"b", 0}},
+				Provenance: map[string]string{"title": "a", "lat": "b", "lng": "b", "rating": "b,a",
+					"categories": "b", "phone": "b"},
+			},
+			{
+				Fields:     map[string]interface{}{"title": "Red Square", "lat": 55.7569, "lng": 37.6208},
+				Members:    []Member{{"a", 1}, {"b", 1}},
+				Provenance: map[string]string{"title": "a", "lat": "b", "lng": "b"},
+			},
+		}},
+		{"max and min", Options{
+			MinSimilarity: 1,
+			Rules:         map[string]string{"rating": Max, "title": Min},
+		}, []Record{
+			{
+				Fields: map[string]interface{}{"title": "Bolshoi Theatre", "lat": 55.7600, "lng": 37.6186, "x": 1.0,
+					"rating": 4.8, "categories": []interface{}{"Theatre"}, "phone": "+7 495"},
+				Members: []Member{{"a", 0}, {"b", 0}},
+				Provenance: map[string]string{"title": "a", "lat": "a", "lng": "a", "x": "a", "rating": "b",
+					"categories": "a", "phone": "b"},
+			},
+			{
+				Fields:     map[string]interface{}{"title": "Red Square", "lat": 55.7539, "lng": 37.6208},
+				Members:    []Member{{"a", 1}},
+				Provenance: map[string]string{"title": "a", "lat": "a", "lng": "a"},
+			},
+			{
+				Fields:     map[string]interface{}{"title": "Red Square", "lat": 55.7569, "lng": 37.6208},
+				Members:    []Member{{"b", 1}},
+				Provenance: map[string]string{"title": "b", "lat": "b", "lng": "b"},
+			},
+		}},
+	}
+	for _, test := range tests {
+		got, err := Merge(testSources(), test.options)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %d records, want %d", test.name, len(got), len(test.want))
+			continue
+		}
+		for i := range got {
+			if !reflect.DeepEqual(got[i].Members, test.want[i].Members) {
+				t.Errorf("%s: record %d members = %v, want %v", test.name, i, got[i].Members, test.want[i].Members)
+			}
+			if !reflect.DeepEqual(got[i].Provenance, test.want[i].Provenance) {
+				t.Errorf("%s: record %d provenance = %v, want %v", test.name, i, got[i].Provenance, test.want[i].Provenance)
+			}
+			if !equalFields(got[i].Fields, test.want[i].Fields) {
+				t.Errorf("%s: record %d fields = %v, want %v", test.name, i, got[i].Fields, test.want[i].Fields)
+			}
+		}
+	}
+}
+
+// equalFields compares fields with numbers rounded, so means of ratings are equal.
+func equalFields(a map[string]interface{}, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for field, value := range a {
+		x, okX := value.(float64)
+		y, okY := b[field].(float64)
+		if okX && okY {
+			if x-y > 1e-9 || y-x > 1e-9 {
+				return false
+			}
+		} else if !reflect.DeepEqual(value, b[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestMergeOneRecordOfSource checks that a record matches only the best record of other source,
+// equally similar records are matched by distance.
+func TestMergeOneRecordOfSource(t *testing.T) {
+	sources := []Source{
+		{Name: "a", Records: []map[string]interface{}{
+			{"title": "Coffee House", "lat": 55.7600, "lng": 37.6186},
+			{"title": "Coffee House", "lat": 55.7602, "lng": 37.6186},
+		}},
+		{Name: "b", Records: []map[string]interface{}{
+			{"title": "Coffee House", "lat": 55.7601, "lng": 37.6186},
+			{"title": "Coffee-House", "lat": 55.7600, "lng": 37.6186},
+		}},
+	}
+	got, err := Merge(sources, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]Member{{{"a", 0}, {"b", 1}}, {{"a", 1}, {"b", 0}}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if !reflect.DeepEqual(got[i].Members, want[i]) {
+			t.Errorf("record %d members = %v, want %v", i, got[i].Members, want[i])
+		}
+	}
+}
+
+func TestRecordMap(t *testing.T) {
+	record := Record{
+		Fields:     map[string]interface{}{"title": "Red Square"},
+		Members:    []Member{{"a", 1}},
+		Provenance: map[string]string{"title": "a"},
+	}
+	want := map[string]interface{}{
+		"title":         "Red Square",
+		SourcesField:    []Member{{"a", 1}},
+		ProvenanceField: map[string]string{"title": "a"},
+	}
+	if got := record.Map(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []Source
+		options Options
+		err     string
+	}{
+		{"no position", []Source{{Name: "a", Records: []map[string]interface{}{{"title": "Kremlin"}}}},
+			Options{}, "source a: record 0 has no lat and lng"},
+		{"zero position", []Source{{Name: "a", Records: []map[string]interface{}{{"lat": 0.0, "lng": 0.0}}}},
+			Options{}, "source a: record 0 has no lat and lng"},
+		{"same names", []Source{{Name: "a"}, {Name: "a"}}, Options{}, `source names should be unique and non-empty, got "a"`},
+		{"empty name", []Source{{Name: ""}}, Options{}, "source names should be unique and non-empty"},
+		{"distance", testSources(), Options{MaxDistance: -1}, "max distance should be positive"},
+		{"similarity", testSources(), Options{MinSimilarity: 2}, "min similarity should be from 0 to 1"},
+		{"precedence", testSources(), Options{Precedence: map[string][]string{"title": {"c"}}},
+			`precedence of title: unknown source "c"`},
+		{"rule", testSources(), Options{Rules: map[string]string{"rating": "median"}}, `rule of rating should be one of`},
+	}
+	for _, test := range tests {
+		_, err := Merge(test.sources, test.options)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
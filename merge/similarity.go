@@ -0,0 +1,106 @@
+package merge
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TitleSimilarity returns similarity of titles from 0 to 1. Titles are compared in lower case without
+// punctuation, with and without words in parentheses (datasets write "Name (Local name)") and with
+// sorted words, the best pair of variants defines similarity.
+func TitleSimilarity(a string, b string) float64 {
+	best := 0.0
+	for _, x := range titleVariants(a) {
+		for _, y := range titleVariants(b) {
+			if s := ratio(x, y); s > best {
+				best = s
+			}
+		}
+	}
+	return best
+}
+
+// titleVariants returns normalized title, its part outside and inside of parentheses and these
+// variants with sorted words.
+func titleVariants(title string) []string {
+	outside, inside := splitParentheses(title)
+	variants := make([]string, 0, 6)
+	seen := make(map[string]bool)
+	for _, variant := range []string{title, outside, inside} {
+		words := strings.FieldsFunc(strings.ToLower(variant), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			continue
+		}
+		normalized := strings.Join(words, " ")
+		sort.Strings(words)
+		for _, v := range []string{normalized, strings.Join(words, " ")} {
+			if !seen[v] {
+				seen[v] = true
+				variants = append(variants, v)
+			}
+		}
+	}
+	return variants
+}
+
+func splitParentheses(title string) (outside string, inside string) {
+	var out, in strings.Builder
+	depth := 0
+	for _, r := range title {
+		switch {
+		case r == '(':
+			depth++
+			in.WriteRune(' ')
+		case r == ')' && depth > 0:
+			depth--
+			in.WriteRune(' ')
+		case depth > 0:
+			in.WriteRune(r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String(), in.String()
+}
+
+// ratio is 1 - Levenshtein distance divided by length of the longer string in runes.
+func ratio(a string, b string) float64 {
+	x, y := []rune(a), []rune(b)
+	if len(x) == 0 && len(y) == 0 {
+		return 1
+	}
+	longer := len(x)
+	if len(y) > longer {
+		longer = len(y)
+	}
+	return 1 - float64(levenshtein(x, y))/float64(longer)
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,31 @@
+package merge
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Bolshoi Theatre", "Bolshoi Theatre", 1},
+		{"Bolshoi Theatre", "bolshoi  theatre!", 1},
+		{"Bolshoi Theatre", "Theatre Bolshoi", 1},
+		{"Bolshoi Theatre (Большой театр)", "Большой театр", 1},
+		{"Bolshoi Theatre (Большой театр)", "Bolshoi Theatre", 1},
+		{"abc", "abd", 2.0 / 3},
+		{"Kremlin", "Gorky Park", 1 - 9.0/10},
+		{"Kremlin", "", 0},
+		{"", "", 0},
+	}
+	for _, test := range tests {
+		if got := TitleSimilarity(test.a, test.b); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+		if got := TitleSimilarity(test.b, test.a); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", test.b, test.a, got, test.want)
+		}
+	}
+}
@@ -1,28 +1,421 @@
 package misc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
-func ReadConfig(configPath string) map[string]interface{} {
-	defaultConfigPath := "config.json"
+// DefaultConfigPath is used if configuration path is not specified.
+const DefaultConfigPath = "config.json"
+
+// EnvironmentPrefix is a prefix of environment variables which override configuration,
+// e.g. FOPS_ANTS_NUMBER overrides AntsNumber.
+const EnvironmentPrefix = "FOPS_"
+
+// Config is a solver configuration.
+// Fields of component configurations are stored on the top level of configuration file.
+type Config struct {
+	DataConfig
+	ACOConfig
+	TravelConfig
+
+	// TimeLimit is currently not used.
+	TimeLimit int
+	// Seed makes randomized components reproducible, random seed is used if it is zero.
+	Seed int64
+}
+
+// DataConfig describes dataset.
+type DataConfig struct {
+	// DataPath is a path to dataset, required.
+	DataPath string
+	// DataFormat is json, csv or geojson, it is chosen by extension of DataPath if empty.
+	DataFormat string
+	// DataFields maps dataset fields to CSV columns or GeoJSON properties, e.g. "title=name,duration=minutes".
+	// Fields which are not mapped are read from columns or properties with the same name.
+	DataFields string
+	// DataBounds keeps only locations inside of "minLat,minLng,maxLat,maxLng" while dataset is loaded.
+	DataBounds string
+	// DataCategories keeps only locations of one of comma separated categories while dataset is loaded.
+	DataCategories string
+	// DataMinPopularity keeps only locations with DataPopularityField not less than it while dataset is loaded.
+	DataMinPopularity float64
+	// DataPopularityField is a numeric dataset field of popularity, default instagram_visitorsNumber.
+	DataPopularityField string
+	// DataImputeDurations fills zero visit durations with median duration of location category.
+	DataImputeDurations bool
+	// DataImpute fills missing (zero) numeric fields from correlated fields, e.g.
+	// "instagram_visitorsNumber=foursquare_checkinsCount|foursquare_userCount", predictors are tried in order.
+	DataImpute string
+	// DataTaxonomy is a path to taxonomy file which maps categories of the dataset to canonical categories
+	// while dataset is loaded, categories are kept as they are if it is empty.
+	DataTaxonomy string
+	// DataLocalProjection projects all locations from lat and lng with local projection which keeps true
+	// distances in meters. By default x and y of the published datasets (Web Mercator) are used as they are.
+	DataLocalProjection bool
+	// DataRestaurantCategory is a category of restaurants where city brand routes stop for meals, default
+	// Restaurant. It is replaced by its canonical category of DataTaxonomy, e.g. "food/restaurants".
+	DataRestaurantCategory string
+}
+
+// DataFormats are supported dataset formats.
+var DataFormats = []string{"json", "csv", "geojson"}
+
+// FieldMapping parses DataFields to map from dataset field to column name.
+func (config DataConfig) FieldMapping() (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(config.DataFields) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(config.DataFields, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid mapping %q, field=column expected", pair)
+		}
+		mapping[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return mapping, nil
+}
+
+// Imputation is a numeric field filled from its predictors, see DataImpute.
+type Imputation struct {
+	Field      string
+	Predictors []string
+}
+
+// Imputations parses DataImpute in order of fields.
+func (config DataConfig) Imputations() ([]Imputation, error) {
+	imputations := make([]Imputation, 0)
+	if strings.TrimSpace(config.DataImpute) == "" {
+		return imputations, nil
+	}
+	seen := make(map[string]bool)
+	for _, rule := range strings.Split(config.DataImpute, ",") {
+		parts := strings.SplitN(rule, "=", 2)
+		field := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || field == "" || seen[field] {
+			return nil, fmt.Errorf("invalid imputation %q, field=predictor|predictor expected", rule)
+		}
+		seen[field] = true
+		imputation := Imputation{Field: field}
+		for _, predictor := range strings.Split(parts[1], "|") {
+			predictor = strings.TrimSpace(predictor)
+			if predictor == "" || predictor == field {
+				return nil, fmt.Errorf("invalid imputation %q, field=predictor|predictor expected", rule)
+			}
+			imputation.Predictors = append(imputation.Predictors, predictor)
+		}
+		imputations = append(imputations, imputation)
+	}
+	return imputations, nil
+}
+
+// Bounds parses DataBounds, ok is false if it is empty.
+func (config DataConfig) Bounds() (bounds [4]float64, ok bool, err error) {
+	if strings.TrimSpace(config.DataBounds) == "" {
+		return bounds, false, nil
+	}
+	parts := strings.Split(config.DataBounds, ",")
+	if len(parts) != 4 {
+		return bounds, false, fmt.Errorf("invalid bounds %q, minLat,minLng,maxLat,maxLng expected", config.DataBounds)
+	}
+	for i, part := range parts {
+		if bounds[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil {
+			return bounds, false, fmt.Errorf("invalid bounds %q, minLat,minLng,maxLat,maxLng expected", config.DataBounds)
+		}
+	}
+	if bounds[0] > bounds[2] || bounds[1] > bounds[3] {
+		return bounds, false, fmt.Errorf("invalid bounds %q, minimum is greater than maximum", config.DataBounds)
+	}
+	return bounds, true, nil
+}
+
+// ACOConfig configures ant colony optimization.
+type ACOConfig struct {
+	// AntsNumber is a number of ants per point, default 1.
+	AntsNumber float64
+	// Fadeness is pheromone fadeness in (0, 1], default 0.1.
+	Fadeness float64
+	// Iterations is a number of iterations, default 100.
+	Iterations int
+	// AttractivenessControl is an influence of point score in probability computation, default 3.
+	AttractivenessControl float64
+	// PheromoneControl is an influence of pheromone value in probability computation, default 2.
+	PheromoneControl float64
+	// NumberOfChannels is a parameter for parallel launch, default 40.
+	NumberOfChannels int
+}
+
+// TravelConfig configures travel between locations.
+type TravelConfig struct {
+	// TravelMode is a name of registered travel model: walking, cycling, driving, network or transit, default walking.
+	TravelMode string
+	// RoadNetworkPath is a path to street network (OpenStreetMap XML or edge list) of network travel mode.
+	RoadNetworkPath string
+	// TravelMatrix enables precomputed travel times between all dataset locations.
+	TravelMatrix bool
+	// TravelCacheDir is a folder where travel time matrices are persisted, they are kept only in memory if empty.
+	TravelCacheDir string
+	// TransitPath is a folder of GTFS feed of transit travel mode.
+	TransitPath string
+	// TransitDayOfWeek selects services of GTFS calendar, "0" is Sunday, all services are used if empty.
+	TransitDayOfWeek string
+	// TransitDepartureTime in HHMM format is used by constraints which do not depend on time, default 1200.
+	TransitDepartureTime int
+	// TransitMaxWalk is a maximum walking distance to, from and between stops in meters, default 1000.
+	TransitMaxWalk float64
+	// SpeedProfilePath is a file of speed profiles by time of day of tdop problem, random speed changes are used if empty.
+	SpeedProfilePath string
+	// WalkingSpeed is in meters per minute, default 66.7.
+	WalkingSpeed float64
+	// CyclingSpeed is in meters per minute, default 250.
+	CyclingSpeed float64
+	// DrivingSpeed is in meters per minute, default 500.
+	DrivingSpeed float64
+}
+
+// ConfigError describes invalid configuration value.
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("config: %s %s", e.Field, e.Message)
+}
+
+// ConfigErrors is a list of all invalid configuration values.
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// DefaultConfig returns configuration with default values of all optional fields.
+func DefaultConfig() Config {
+	return Config{
+		DataConfig: DataConfig{
+			DataPopularityField:    "instagram_visitorsNumber",
+			DataRestaurantCategory: "Restaurant",
+		},
+		ACOConfig: ACOConfig{
+			AntsNumber:            1,
+			Fadeness:              0.1,
+			Iterations:            100,
+			AttractivenessControl: 3,
+			PheromoneControl:      2,
+			NumberOfChannels:      40,
+		},
+		TravelConfig: TravelConfig{
+			TravelMode:           "walking",
+			WalkingSpeed:         66.7,
+			CyclingSpeed:         250,
+			DrivingSpeed:         500,
+			TransitDepartureTime: 1200,
+			TransitMaxWalk:       1000,
+		},
+		TimeLimit: 600,
+	}
+}
+
+// LoadConfig builds configuration from layers: defaults, configuration file, environment variables
+// and overrides (e.g. from command line flags), each next layer replaces values of the previous one.
+// Overrides are keyed by configuration field names. If configPath is empty, DefaultConfigPath is used
+// when it exists. Resulting configuration is validated.
+func LoadConfig(configPath string, overrides map[string]string) (Config, error) {
+	config := DefaultConfig()
+
 	if configPath == "" {
-		configPath = defaultConfigPath
-		fmt.Println("Default path is used for configuration file.")
+		if _, err := os.Stat(DefaultConfigPath); err == nil {
+			configPath = DefaultConfigPath
+		}
+	}
+	if configPath != "" {
+		if err := config.readFile(configPath); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := config.ApplyEnvironment(os.LookupEnv); err != nil {
+		return Config{}, err
 	}
-	file, err := os.Open(configPath)
+	for key, value := range overrides {
+		if err := config.Set(key, value); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+func (config *Config) readFile(configPath string) error {
+	raw, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		fmt.Println("Unable to open configuration file.")
-		return nil
+		return fmt.Errorf("config: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return fmt.Errorf("config: %s: %v", configPath, err)
+	}
+	return nil
+}
+
+// Validate checks all configuration values.
+func (config Config) Validate() error {
+	errs := make(ConfigErrors, 0)
+	check := func(ok bool, field string, message string) {
+		if !ok {
+			errs = append(errs, ConfigError{field, message})
+		}
+	}
+
+	check(config.DataPath != "", "DataPath", "is required")
+	knownFormat := config.DataFormat == ""
+	for _, format := range DataFormats {
+		knownFormat = knownFormat || config.DataFormat == format
+	}
+	check(knownFormat, "DataFormat", "should be one of "+strings.Join(DataFormats, ", "))
+	_, err := config.FieldMapping()
+	check(err == nil, "DataFields", "should be comma separated field=column pairs")
+	_, _, err = config.Bounds()
+	check(err == nil, "DataBounds", "should be minLat,minLng,maxLat,maxLng")
+	_, err = config.Imputations()
+	check(err == nil, "DataImpute", "should be comma separated field=predictor|predictor rules")
+	check(strings.TrimSpace(config.DataRestaurantCategory) != "", "DataRestaurantCategory", "is required")
+	check(config.AntsNumber > 0, "AntsNumber", "should be positive")
+	check(config.Fadeness > 0 && config.Fadeness <= 1, "Fadeness", "should be in (0, 1]")
+	check(config.Iterations > 0, "Iterations", "should be positive")
+	check(config.AttractivenessControl >= 0, "AttractivenessControl", "should not be negative")
+	check(config.PheromoneControl >= 0, "PheromoneControl", "should not be negative")
+	check(config.NumberOfChannels > 0, "NumberOfChannels", "should be positive")
+	check(config.TravelMode != "", "TravelMode", "is required")
+	check(config.WalkingSpeed > 0, "WalkingSpeed", "should be positive")
+	check(config.CyclingSpeed > 0, "CyclingSpeed", "should be positive")
+	check(config.DrivingSpeed > 0, "DrivingSpeed", "should be positive")
+	check(config.TransitDepartureTime >= 0 && config.TransitDepartureTime < 2400 && config.TransitDepartureTime%100 < 60,
+		"TransitDepartureTime", "should be time in HHMM format")
+	check(config.TransitMaxWalk >= 0, "TransitMaxWalk", "should not be negative")
+	check(config.TimeLimit >= 0, "TimeLimit", "should not be negative")
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Keys returns names of all configuration fields.
+func (config Config) Keys() []string {
+	keys := make([]string, 0)
+	forEachField(reflect.ValueOf(&config).Elem(), func(name string, _ reflect.Value) bool {
+		keys = append(keys, name)
+		return true
+	})
+	return keys
+}
+
+// Set parses value of configuration field with given name, names are case insensitive.
+func (config *Config) Set(key string, value string) error {
+	found := false
+	var err error
+	forEachField(reflect.ValueOf(config).Elem(), func(name string, field reflect.Value) bool {
+		if !strings.EqualFold(name, key) {
+			return true
+		}
+		found = true
+		err = setField(field, value)
+		return false
+	})
+
+	if !found {
+		return ConfigError{key, "is unknown"}
 	}
-	var config map[string]interface{}
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&config)
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return ConfigError{key, fmt.Sprintf("has invalid value %q", value)}
+	}
+	return nil
+}
+
+// ApplyEnvironment sets configuration fields from environment variables, e.g. FOPS_DATA_PATH sets DataPath.
+func (config *Config) ApplyEnvironment(lookup func(key string) (string, bool)) error {
+	for _, key := range config.Keys() {
+		if value, ok := lookup(EnvironmentVariable(key)); ok {
+			if err := config.Set(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnvironmentVariable returns name of environment variable for configuration field.
+func EnvironmentVariable(key string) string {
+	name := make([]rune, 0, len(key)+4)
+	for i, r := range key {
+		if i > 0 && unicode.IsUpper(r) {
+			name = append(name, '_')
+		}
+		name = append(name, unicode.ToUpper(r))
+	}
+	return EnvironmentPrefix + string(name)
+}
+
+func forEachField(value reflect.Value, visit func(name string, field reflect.Value) bool) bool {
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if !forEachField(value.Field(i), visit) {
+				return false
+			}
+			continue
+		}
+		if !visit(field.Name, value.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(v)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
 	}
-	return config
+	return nil
 }
@@ -0,0 +1,175 @@
+package misc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func setEnvironment(t *testing.T, variables map[string]string) func() {
+	for key, value := range variables {
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for key := range variables {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLoadConfigOverrideOrder(t *testing.T) {
+	path, remove := writeConfig(t, `{"DataPath": "file.json", "Iterations": 10, "AntsNumber": 2, "Fadeness": 0.2}`)
+	defer remove()
+	defer setEnvironment(t, map[string]string{
+		"FOPS_ITERATIONS":  "20",
+		"FOPS_ANTS_NUMBER": "3",
+	})()
+
+	config, err := LoadConfig(path, map[string]string{"iterations": "30"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"default", config.PheromoneControl, 2.0},
+		{"file over default", config.Fadeness, 0.2},
+		{"file", config.DataPath, "file.json"},
+		{"environment over file", config.AntsNumber, 3.0},
+		{"override over environment", config.Iterations, 30},
+	}
+	for _, test := range tests {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestLoadConfigWithoutFile(t *testing.T) {
+	defer setEnvironment(t, map[string]string{"FOPS_DATA_PATH": "env.json"})()
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	config, err := LoadConfig("", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := DefaultConfig()
+	want.DataPath = "env.json"
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("LoadConfig = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		environment map[string]string
+		overrides   map[string]string
+		err         string
+		fields      []string
+	}{
+		{"unknown file field", `{"DataPath": "a.json", "Ants": 1}`, nil, nil, `unknown field "Ants"`, nil},
+		{"malformed file", `{"DataPath": `, nil, nil, "unexpected EOF", nil},
+		{"invalid environment", `{"DataPath": "a.json"}`, map[string]string{"FOPS_ITERATIONS": "many"}, nil,
+			`config: Iterations has invalid value "many"`, nil},
+		{"unknown override", `{"DataPath": "a.json"}`, nil, map[string]string{"Ants": "1"}, "config: Ants is unknown", nil},
+		{"invalid override", `{"DataPath": "a.json"}`, nil, map[string]string{"Fadeness": "x"},
+			`config: Fadeness has invalid value "x"`, nil},
+		{"missing data path", `{}`, nil, nil, "config: DataPath is required", []string{"DataPath"}},
+		{"all invalid values", `{"DataPath": "a.json", "DataFormat": "xml", "DataFields": "title", ` +
+			`"DataBounds": "1,2,3", "DataImpute": "a=a", "DataRestaurantCategory": " ", "AntsNumber": 0, ` +
+			`"Fadeness": 1.5, "Iterations": 0, "AttractivenessControl": -1, "PheromoneControl": -1, ` +
+			`"NumberOfChannels": 0, "TravelMode": "", "WalkingSpeed": 0, "CyclingSpeed": -1, "DrivingSpeed": 0, ` +
+			`"TransitDepartureTime": 1260, "TransitMaxWalk": -1, "TimeLimit": -1}`, nil, nil, "",
+			[]string{"DataFormat", "DataFields", "DataBounds", "DataImpute", "DataRestaurantCategory", "AntsNumber",
+				"Fadeness", "Iterations", "AttractivenessControl", "PheromoneControl", "NumberOfChannels",
+				"TravelMode", "WalkingSpeed", "CyclingSpeed", "DrivingSpeed", "TransitDepartureTime",
+				"TransitMaxWalk", "TimeLimit"}},
+		{"invalid value of environment", `{"DataPath": "a.json"}`, map[string]string{"FOPS_ITERATIONS": "-1"}, nil,
+			"config: Iterations should be positive", []string{"Iterations"}},
+	}
+	for _, test := range tests {
+		path, remove := writeConfig(t, test.file)
+		restore := setEnvironment(t, test.environment)
+		_, err := LoadConfig(path, test.overrides)
+		restore()
+		remove()
+
+		if err == nil {
+			t.Errorf("%s: error expected", test.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %q, want %q", test.name, err, test.err)
+		}
+		if test.fields == nil {
+			continue
+		}
+		errs, ok := err.(ConfigErrors)
+		if !ok {
+			t.Errorf("%s: error %T, want ConfigErrors", test.name, err)
+			continue
+		}
+		fields := make([]string, 0, len(errs))
+		for _, e := range errs {
+			fields = append(fields, e.Field)
+		}
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%s: invalid fields %v, want %v", test.name, fields, test.fields)
+		}
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(os.TempDir(), "missing", "config.json"), nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "config: ") {
+		t.Errorf("LoadConfig error %v, want config error", err)
+	}
+}
+
+func TestEnvironmentVariable(t *testing.T) {
+	tests := map[string]string{
+		"DataPath":             "FOPS_DATA_PATH",
+		"AntsNumber":           "FOPS_ANTS_NUMBER",
+		"TransitDepartureTime": "FOPS_TRANSIT_DEPARTURE_TIME",
+		"Seed":                 "FOPS_SEED",
+	}
+	for key, want := range tests {
+		if got := EnvironmentVariable(key); got != want {
+			t.Errorf("EnvironmentVariable(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
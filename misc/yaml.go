@@ -0,0 +1,193 @@
+package misc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReadYAML parses simple YAML documents: top level "key: value" pairs, where value is a scalar,
+// an inline list "[1, 2]", a block list of "- item" lines or a mapping of indented "key: value" lines
+// with scalars and inline lists. Deeper nested mappings are not supported. Integers are int64, so large
+// IDs keep all digits, other numbers are float64.
+func ReadYAML(data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	currentKey := ""
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if currentKey == "" {
+				return nil, fmt.Errorf("yaml: line %d: list item without key", lineNumber)
+			}
+			list, ok := result[currentKey].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("yaml: line %d: key %q already has a value", lineNumber, currentKey)
+			}
+			value, err := parseYAMLScalar(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("yaml: line %d: %v", lineNumber, err)
+			}
+			result[currentKey] = append(list, value)
+			continue
+		}
+
+		key, rawValue, err := splitYAMLPair(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("yaml: line %d: %v", lineNumber, err)
+		}
+
+		if line != strings.TrimLeft(line, " \t") {
+			if currentKey == "" {
+				return nil, fmt.Errorf("yaml: line %d: nested mapping without key", lineNumber)
+			}
+			// key without value holds an empty list until its first item or key
+			mapping, ok := result[currentKey].(map[string]interface{})
+			if list, isList := result[currentKey].([]interface{}); isList && len(list) == 0 {
+				mapping, ok = make(map[string]interface{}), true
+				result[currentKey] = mapping
+			}
+			if !ok {
+				return nil, fmt.Errorf("yaml: line %d: key %q already has a value", lineNumber, currentKey)
+			}
+			if _, exists := mapping[key]; exists {
+				return nil, fmt.Errorf("yaml: line %d: duplicate key %q", lineNumber, key)
+			}
+			if rawValue == "" {
+				return nil, fmt.Errorf("yaml: line %d: mappings nested deeper than one level are not supported", lineNumber)
+			}
+			if mapping[key], err = parseYAMLValue(rawValue); err != nil {
+				return nil, fmt.Errorf("yaml: line %d: %v", lineNumber, err)
+			}
+			continue
+		}
+
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("yaml: line %d: duplicate key %q", lineNumber, key)
+		}
+		if rawValue == "" {
+			currentKey = key
+			result[key] = make([]interface{}, 0)
+			continue
+		}
+
+		currentKey = ""
+		value, err := parseYAMLValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("yaml: line %d: %v", lineNumber, err)
+		}
+		result[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// splitYAMLPair splits "key: value" line, value is empty if the line has only key.
+func splitYAMLPair(line string) (key string, value string, err error) {
+	colon := strings.Index(line, ":")
+	if colon <= 0 {
+		return "", "", fmt.Errorf("expected \"key: value\"")
+	}
+	return strings.TrimSpace(line[:colon]), strings.TrimSpace(line[colon+1:]), nil
+}
+
+func stripYAMLComment(line string) string {
+	quote := rune(0)
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func parseYAMLValue(raw string) (interface{}, error) {
+	if !strings.HasPrefix(raw, "[") {
+		return parseYAMLScalar(raw)
+	}
+	if !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("unterminated list %q", raw)
+	}
+
+	list := make([]interface{}, 0)
+	content := strings.TrimSpace(raw[1 : len(raw)-1])
+	if content == "" {
+		return list, nil
+	}
+	for _, item := range splitYAMLList(content) {
+		value, err := parseYAMLScalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+func splitYAMLList(content string) []string {
+	items := make([]string, 0)
+	quote := rune(0)
+	start := 0
+	for i, r := range content {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items = append(items, content[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, content[start:])
+}
+
+func parseYAMLScalar(raw string) (interface{}, error) {
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		return strconv.Unquote(raw)
+	}
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		return strings.Replace(raw[1:len(raw)-1], "''", "'", -1), nil
+	}
+	if strings.HasPrefix(raw, "\"") || strings.HasPrefix(raw, "'") {
+		return nil, fmt.Errorf("unterminated string %s", raw)
+	}
+
+	switch raw {
+	case "", "~", "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return value, nil
+	}
+	if value, err := strconv.ParseFloat(raw, 64); err == nil {
+		return value, nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,74 @@
+package misc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want map[string]interface{}
+	}{
+		{"empty document", "---\n# comment only\n", map[string]interface{}{}},
+		{"scalars", "kind: op\nlimit: 720\nratio: 0.5\nflag: true\noff: false\nnone: ~\nempty: null\n",
+			map[string]interface{}{"kind": "op", "limit": int64(720), "ratio": 0.5, "flag": true, "off": false,
+				"none": nil, "empty": nil}},
+		{"quoted strings", "a: \"x: # y\"\nb: 'it''s'\nc: \"tab\\t\"\n",
+			map[string]interface{}{"a": "x: # y", "b": "it's", "c": "tab\t"}},
+		{"comments", "a: 1 # one\nb: x#y\n# c: 3\n", map[string]interface{}{"a": int64(1), "b": "x#y"}},
+		{"inline list", "ids: [1, 2, \"a, b\"]\nnone: []\n",
+			map[string]interface{}{"ids": []interface{}{int64(1), int64(2), "a, b"}, "none": []interface{}{}}},
+		{"block list", "ids:\n  - 1\n  - two\n- 'three'\nafter: x\n",
+			map[string]interface{}{"ids": []interface{}{int64(1), "two", "three"}, "after": "x"}},
+		{"key without items", "ids:\nafter: 1\n", map[string]interface{}{"ids": []interface{}{}, "after": int64(1)}},
+		{"colon in value", "time: 10:30\n", map[string]interface{}{"time": "10:30"}},
+		{"windows line ends", "a: 1\r\nb: x\r\n", map[string]interface{}{"a": int64(1), "b": "x"}},
+		{"numbers", "id: 9007199254740993\nnegative: -12\nfloat: 1e3\nlarge: 99999999999999999999\n",
+			map[string]interface{}{"id": int64(9007199254740993), "negative": int64(-12), "float": 1000.0,
+				"large": 1e20}},
+		{"nested mapping", "settings:\n  Iterations: 2\n  TravelMode: transit # comment\n  ids: [1, 2]\nkind: op\n",
+			map[string]interface{}{"settings": map[string]interface{}{"Iterations": int64(2), "TravelMode": "transit",
+				"ids": []interface{}{int64(1), int64(2)}}, "kind": "op"}},
+	}
+	for _, test := range tests {
+		got, err := ReadYAML([]byte(test.yaml))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ReadYAML = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"list item without key", "- 1\n", "line 1: list item without key"},
+		{"item after scalar", "a: 1\n- 2\n", "line 2: list item without key"},
+		{"deep mapping", "a:\n  b:\n    c: 1\n", "line 2: mappings nested deeper than one level are not supported"},
+		{"mapping without key", "a: 1\n  b: 1\n", "line 2: nested mapping without key"},
+		{"mapping after list", "a:\n  - 1\n  b: 1\n", "line 3: key \"a\" already has a value"},
+		{"list after mapping", "a:\n  b: 1\n  - 1\n", "line 3: key \"a\" already has a value"},
+		{"duplicate nested key", "a:\n  b: 1\n  b: 2\n", "line 3: duplicate key \"b\""},
+		{"no colon", "a: 1\nb\n", "line 2: expected \"key: value\""},
+		{"empty key", ": 1\n", "line 1: expected \"key: value\""},
+		{"duplicate key", "a: 1\na: 2\n", "line 2: duplicate key \"a\""},
+		{"unterminated list", "a: [1, 2\n", "line 1: unterminated list"},
+		{"invalid escape", "a: \"\\q\"\n", "line 1: invalid syntax"},
+		{"unterminated string", "a: \"x\n", "line 1: unterminated string"},
+	}
+	for _, test := range tests {
+		_, err := ReadYAML([]byte(test.yaml))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: ReadYAML error = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
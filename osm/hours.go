@@ -0,0 +1,222 @@
+package osm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// weekdays of opening_hours, position is day of week of datasets, 0 is Sunday.
+var weekdays = []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// AlwaysOpen returns open hours of location which is open all days.
+func AlwaysOpen() map[string][]int {
+	hours := make(map[string][]int)
+	for day := range weekdays {
+		hours[strconv.Itoa(day)] = []int{0, 2400}
+	}
+	return hours
+}
+
+// ParseOpeningHours converts opening_hours tag to open hours of datasets: day of week ("0" is Sunday)
+// to open and close time in HHMM format, days without hours are closed. Common subset of the syntax is
+// supported: rules separated by ";" where later rules replace days of earlier rules, weekday ranges
+// and lists ("Mo-Fr", "Sa,Su"), time ranges ("09:00-13:00,14:00-18:00"), "24/7" and "off".
+// Dataset keeps one interval per day, so breaks are ignored and intervals after midnight end at 2400.
+// Holidays (PH, SH) are skipped, rules with months, weeks, dates or sun events return error.
+func ParseOpeningHours(value string) (map[string][]int, error) {
+	hours := make(map[string][]int)
+	value = strings.Replace(value, "||", ";", -1)
+	for _, rule := range strings.Split(value, ";") {
+		rule = stripComment(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if err := applyRule(hours, rule); err != nil {
+			return nil, fmt.Errorf("osm: opening_hours %q: %v", value, err)
+		}
+	}
+	return hours, nil
+}
+
+// stripComment removes quoted comments, e.g. "Mo-Fr 09:00-18:00 "by appointment"".
+func stripComment(rule string) string {
+	if i := strings.Index(rule, "\""); i >= 0 {
+		return strings.TrimSpace(rule[:i])
+	}
+	return rule
+}
+
+func applyRule(hours map[string][]int, rule string) error {
+	if rule == "24/7" {
+		for day, interval := range AlwaysOpen() {
+			hours[day] = interval
+		}
+		return nil
+	}
+
+	// weekday selector ends where the first time, state or 24/7 begins
+	selector, times := rule, ""
+	if i := timeStart(rule); i >= 0 {
+		selector, times = rule[:i], rule[i:]
+	}
+	selector = strings.TrimSpace(selector)
+	for _, state := range []string{"off", "closed"} {
+		if strings.HasSuffix(selector, state) {
+			selector, times = strings.TrimSpace(strings.TrimSuffix(selector, state)), state
+		}
+	}
+	selector = strings.TrimSuffix(strings.TrimSpace(selector), ":")
+
+	days, err := parseWeekdays(selector)
+	if err != nil {
+		return err
+	}
+	if days == nil {
+		// rules only for holidays do not change regular days
+		return nil
+	}
+
+	times = strings.TrimSpace(times)
+	if times == "off" || times == "closed" {
+		for _, day := range days {
+			delete(hours, strconv.Itoa(day))
+		}
+		return nil
+	}
+	interval := []int{0, 2400}
+	if times != "" && times != "24/7" {
+		if interval, err = parseTimes(times); err != nil {
+			return err
+		}
+	}
+	for _, day := range days {
+		hours[strconv.Itoa(day)] = interval
+	}
+	return nil
+}
+
+// timeStart returns position of the first digit which is not in brackets of nth weekday or -1.
+func timeStart(rule string) int {
+	depth := 0
+	for i, r := range rule {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0 && r >= '0' && r <= '9':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseWeekdays returns days of selector, all days if selector is empty and nil if it selects only holidays.
+func parseWeekdays(selector string) ([]int, error) {
+	if selector == "" {
+		return []int{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+	// nth weekday of month, e.g. "Sa[1,3]", is approximated by every week
+	for {
+		open, closing := strings.Index(selector, "["), strings.Index(selector, "]")
+		if open < 0 || closing < open {
+			break
+		}
+		selector = selector[:open] + selector[closing+1:]
+	}
+	var days []int
+	for _, item := range strings.Split(selector, ",") {
+		item = strings.TrimSpace(item)
+		if item == "PH" || item == "SH" {
+			continue
+		}
+		bounds := strings.Split(item, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid weekdays %q", item)
+		}
+		first, err := weekday(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = weekday(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		// ranges may wrap around the week, e.g. "Fr-Mo"
+		for day := first; ; day = (day + 1) % len(weekdays) {
+			days = append(days, day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func weekday(name string) (int, error) {
+	for day, weekdayName := range weekdays {
+		if strings.EqualFold(strings.TrimSpace(name), weekdayName) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported selector %q", name)
+}
+
+// parseTimes returns interval from the first opening to the last closing of time ranges.
+func parseTimes(times string) ([]int, error) {
+	interval := []int{2400, 0}
+	for _, item := range strings.Split(times, ",") {
+		item = strings.TrimSpace(item)
+		openEnded := strings.HasSuffix(item, "+")
+		item = strings.TrimSuffix(item, "+")
+
+		bounds := strings.Split(item, "-")
+		if len(bounds) > 2 || (len(bounds) == 1 && !openEnded) {
+			return nil, fmt.Errorf("invalid time range %q", item)
+		}
+		open, err := clock(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		closing := 2400
+		if len(bounds) == 2 {
+			if closing, err = clock(bounds[1]); err != nil {
+				return nil, err
+			}
+			if closing <= open {
+				// open after midnight
+				closing = 2400
+			}
+		}
+		if open < interval[0] {
+			interval[0] = open
+		}
+		if closing > interval[1] {
+			interval[1] = closing
+		}
+	}
+	return interval, nil
+}
+
+// clock parses HH:MM to HHMM, hours after midnight of the next day are truncated to 2400.
+func clock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("unsupported time %q", value)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 48 {
+		return 0, fmt.Errorf("unsupported time %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes >= 60 {
+		return 0, fmt.Errorf("unsupported time %q", value)
+	}
+	if hours >= 24 {
+		return 2400, nil
+	}
+	return hours*100 + minutes, nil
+}
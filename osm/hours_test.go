@@ -0,0 +1,78 @@
+package osm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// days returns open hours with the interval on the days.
+func days(interval []int, days ...string) map[string][]int {
+	hours := make(map[string][]int)
+	for _, day := range days {
+		hours[day] = interval
+	}
+	return hours
+}
+
+func TestParseOpeningHours(t *testing.T) {
+	week := []string{"0", "1", "2", "3", "4", "5", "6"}
+	tests := []struct {
+		value string
+		want  map[string][]int
+	}{
+		{"24/7", AlwaysOpen()},
+		{"09:00-18:00", days([]int{900, 1800}, week...)},
+		{"Mo-Fr 09:00-18:00", days([]int{900, 1800}, "1", "2", "3", "4", "5")},
+		{"Mo-Fr 09:00-18:00; Sa,Su 10:00-16:00", map[string][]int{
+			"0": {1000, 1600}, "1": {900, 1800}, "2": {900, 1800}, "3": {900, 1800}, "4": {900, 1800},
+			"5": {900, 1800}, "6": {1000, 1600}}},
+		{"Mo-Su 10:00-20:00; We off", days([]int{1000, 2000}, "0", "1", "2", "4", "5", "6")},
+		{"Mo-Su 10:00-20:00; Tu closed", days([]int{1000, 2000}, "0", "1", "3", "4", "5", "6")},
+		{"Fr-Mo 12:00-14:00", days([]int{1200, 1400}, "5", "6", "0", "1")},
+		{"Mo 09:00-13:00,14:00-18:00", days([]int{900, 1800}, "1")},
+		{"Sa 20:00-02:00", days([]int{2000, 2400}, "6")},
+		{"Su 18:00+", days([]int{1800, 2400}, "0")},
+		{"Tu 10:00-26:00", days([]int{1000, 2400}, "2")},
+		{"Sa[1,3] 10:00-12:00", days([]int{1000, 1200}, "6")},
+		{"Mo-Fr 09:00-17:00; PH off", days([]int{900, 1700}, "1", "2", "3", "4", "5")},
+		{"Mo: 09:00-12:00", days([]int{900, 1200}, "1")},
+		{"Th", days([]int{0, 2400}, "4")},
+		{`Mo-Fr 08:00-12:00 "by appointment"`, days([]int{800, 1200}, "1", "2", "3", "4", "5")},
+		{"Mo 09:00-10:00 || Tu 11:00-12:00", map[string][]int{"1": {900, 1000}, "2": {1100, 1200}}},
+		{"", map[string][]int{}},
+	}
+	for _, test := range tests {
+		got, err := ParseOpeningHours(test.value)
+		if err != nil {
+			t.Errorf("ParseOpeningHours(%q): unexpected error %v", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseOpeningHours(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestParseOpeningHoursErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{"Jan-Mar 10:00-18:00", `unsupported selector "Jan"`},
+		{"sunrise-sunset", `unsupported selector "sunrise"`},
+		{"Mo-We-Fr 10:00-12:00", "invalid weekdays"},
+		{"Mo 10:00", `invalid time range "10:00"`},
+		{"Mo 10-12", `unsupported time "10"`},
+		{"Mo 10:75-12:00", `unsupported time "10:75"`},
+		{"Mo 49:00-50:00", `unsupported time "49:00"`},
+		{"Mo 10:00-11:00-12:00", "invalid time range"},
+		{"Sa[1 10:00-12:00", "unsupported selector"},
+	}
+	for _, test := range tests {
+		_, err := ParseOpeningHours(test.value)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseOpeningHours(%q) error = %v, want %q", test.value, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package osm reads OpenStreetMap extracts in XML and PBF formats, imports points of interest
+// and parses opening_hours tags.
+package osm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Node is a point with tags, Tags is nil if node has no tags.
+type Node struct {
+	ID   int64
+	Lat  float64
+	Lng  float64
+	Tags map[string]string
+}
+
+// Way is a line or area given by references to nodes.
+type Way struct {
+	ID    int64
+	Nodes []int64
+	Tags  map[string]string
+}
+
+// Handler receives elements of the extract in the order of the file, nodes go before ways in extracts.
+// Nil functions skip elements of the type.
+type Handler struct {
+	Node func(node Node)
+	Way  func(way Way)
+}
+
+// ReadFile reads extract, files with .pbf extension are read as PBF, other files as XML.
+func ReadFile(path string, handler Handler) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("osm: %v", err)
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".pbf") {
+		err = ReadPBF(file, handler)
+	} else {
+		err = ReadXML(file, handler)
+	}
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, path)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// maxBlobSize is a limit of the format for uncompressed blocks.
+const maxBlobSize = 32 * 1024 * 1024
+
+// supportedFeatures are required features of PBF header which reader understands.
+var supportedFeatures = map[string]bool{"OsmSchema-V0.6": true, "DenseNodes": true}
+
+var errTruncated = errors.New("osm: truncated protobuf message")
+
+// ReadPBF reads OpenStreetMap PBF, the format of planet and regional extracts. Blocks compressed
+// with zlib or stored raw are supported, relations are skipped.
+func ReadPBF(r io.Reader, handler Handler) error {
+	var size [4]byte
+	for {
+		if _, err := io.ReadFull(r, size[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("osm: %v", err)
+		}
+		headerSize := binary.BigEndian.Uint32(size[:])
+		if headerSize > 64*1024 {
+			return fmt.Errorf("osm: blob header of %d bytes is too large", headerSize)
+		}
+		header := make([]byte, headerSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return fmt.Errorf("osm: %v", err)
+		}
+		blobType, dataSize, err := parseBlobHeader(header)
+		if err != nil {
+			return err
+		}
+		if dataSize < 0 || dataSize > maxBlobSize {
+			return fmt.Errorf("osm: blob of %d bytes is too large", dataSize)
+		}
+		blob := make([]byte, dataSize)
+		if _, err := io.ReadFull(r, blob); err != nil {
+			return fmt.Errorf("osm: %v", err)
+		}
+		data, err := blobData(blob)
+		if err != nil {
+			return err
+		}
+
+		switch blobType {
+		case "OSMHeader":
+			if err := checkHeader(data); err != nil {
+				return err
+			}
+		case "OSMData":
+			if err := readBlock(data, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parseBlobHeader(data []byte) (blobType string, dataSize int, err error) {
+	p := protobuf{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			blobType = string(p.bytes)
+		case 3:
+			dataSize = int(p.value)
+		}
+	}
+	return blobType, dataSize, p.err
+}
+
+// blobData returns uncompressed content of the blob.
+func blobData(blob []byte) ([]byte, error) {
+	p := protobuf{data: blob}
+	for p.next() {
+		switch p.field {
+		case 1:
+			return p.bytes, nil
+		case 3:
+			reader, err := zlib.NewReader(bytes.NewReader(p.bytes))
+			if err != nil {
+				return nil, fmt.Errorf("osm: %v", err)
+			}
+			data, err := ioutil.ReadAll(io.LimitReader(reader, maxBlobSize))
+			if err != nil {
+				return nil, fmt.Errorf("osm: %v", err)
+			}
+			return data, nil
+		case 4, 5, 6, 7:
+			return nil, fmt.Errorf("osm: blob compression %d is not supported, recompress extract with zlib", p.field)
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return nil, fmt.Errorf("osm: blob has no data")
+}
+
+func checkHeader(data []byte) error {
+	p := protobuf{data: data}
+	for p.next() {
+		if p.field == 4 && !supportedFeatures[string(p.bytes)] {
+			return fmt.Errorf("osm: required feature %s is not supported", p.bytes)
+		}
+	}
+	return p.err
+}
+
+// block is a context of primitive block for decoding of coordinates and tags.
+type block struct {
+	strings     [][]byte
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+func (b *block) coordinate(offset int64, value int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*value)
+}
+
+func (b *block) str(i uint64) (string, error) {
+	if i >= uint64(len(b.strings)) {
+		return "", fmt.Errorf("osm: string %d is out of string table", i)
+	}
+	return string(b.strings[i]), nil
+}
+
+func (b *block) tags(keys []uint64, values []uint64) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("osm: %d keys and %d values of tags", len(keys), len(values))
+	}
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		key, err := b.str(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		value, err := b.str(values[i])
+		if err != nil {
+			return nil, err
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+func readBlock(data []byte, handler Handler) error {
+	b := &block{granularity: 100}
+	groups := make([][]byte, 0)
+	p := protobuf{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			table := protobuf{data: p.bytes}
+			for table.next() {
+				if table.field == 1 {
+					b.strings = append(b.strings, table.bytes)
+				}
+			}
+			if table.err != nil {
+				return table.err
+			}
+		case 2:
+			groups = append(groups, p.bytes)
+		case 17:
+			b.granularity = int64(p.value)
+		case 19:
+			b.latOffset = int64(p.value)
+		case 20:
+			b.lonOffset = int64(p.value)
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+
+	for _, group := range groups {
+		g := protobuf{data: group}
+		for g.next() {
+			var err error
+			switch {
+			case g.field == 1 && handler.Node != nil:
+				err = b.readNode(g.bytes, handler)
+			case g.field == 2 && handler.Node != nil:
+				err = b.readDenseNodes(g.bytes, handler)
+			case g.field == 3 && handler.Way != nil:
+				err = b.readWay(g.bytes, handler)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if g.err != nil {
+			return g.err
+		}
+	}
+	return nil
+}
+
+func (b *block) readNode(data []byte, handler Handler) error {
+	var id, lat, lon int64
+	var keys, values []uint64
+	p := protobuf{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			id = zigzag(p.value)
+		case 2:
+			keys = p.packed()
+		case 3:
+			values = p.packed()
+		case 8:
+			lat = zigzag(p.value)
+		case 9:
+			lon = zigzag(p.value)
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	tags, err := b.tags(keys, values)
+	if err != nil {
+		return err
+	}
+	handler.Node(Node{ID: id, Lat: b.coordinate(b.latOffset, lat), Lng: b.coordinate(b.lonOffset, lon), Tags: tags})
+	return nil
+}
+
+// readDenseNodes decodes delta coded nodes, tags of all nodes are in one list, each node ends with 0.
+func (b *block) readDenseNodes(data []byte, handler Handler) error {
+	var ids, lats, lons, keysValues []uint64
+	p := protobuf{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			ids = p.packed()
+		case 8:
+			lats = p.packed()
+		case 9:
+			lons = p.packed()
+		case 10:
+			keysValues = p.packed()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return fmt.Errorf("osm: dense nodes have %d ids, %d lats and %d lons", len(ids), len(lats), len(lons))
+	}
+
+	var id, lat, lon int64
+	k := 0
+	for i := range ids {
+		id += zigzag(ids[i])
+		lat += zigzag(lats[i])
+		lon += zigzag(lons[i])
+
+		var tags map[string]string
+		for k < len(keysValues) && keysValues[k] != 0 {
+			if k+1 >= len(keysValues) {
+				return errTruncated
+			}
+			key, err := b.str(keysValues[k])
+			if err != nil {
+				return err
+			}
+			value, err := b.str(keysValues[k+1])
+			if err != nil {
+				return err
+			}
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[key] = value
+			k += 2
+		}
+		// skip delimiter of the node
+		k++
+		handler.Node(Node{ID: id, Lat: b.coordinate(b.latOffset, lat), Lng: b.coordinate(b.lonOffset, lon), Tags: tags})
+	}
+	return nil
+}
+
+func (b *block) readWay(data []byte, handler Handler) error {
+	var way Way
+	var keys, values, refs []uint64
+	p := protobuf{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			way.ID = int64(p.value)
+		case 2:
+			keys = p.packed()
+		case 3:
+			values = p.packed()
+		case 8:
+			refs = p.packed()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	tags, err := b.tags(keys, values)
+	if err != nil {
+		return err
+	}
+	way.Tags = tags
+	way.Nodes = make([]int64, len(refs))
+	var ref int64
+	for i, delta := range refs {
+		ref += zigzag(delta)
+		way.Nodes[i] = ref
+	}
+	handler.Way(way)
+	return nil
+}
+
+// protobuf iterates over fields of protobuf message. Value holds varint and fixed size fields,
+// bytes holds length delimited fields.
+type protobuf struct {
+	data  []byte
+	field int
+	value uint64
+	bytes []byte
+	err   error
+}
+
+func (p *protobuf) next() bool {
+	if len(p.data) == 0 || p.err != nil {
+		return false
+	}
+	key, ok := p.varint()
+	if !ok {
+		return false
+	}
+	p.field = int(key >> 3)
+	switch key & 7 {
+	case 0:
+		p.value, ok = p.varint()
+	case 1:
+		ok = len(p.data) >= 8
+		if ok {
+			p.value = binary.LittleEndian.Uint64(p.data)
+			p.data = p.data[8:]
+		}
+	case 2:
+		var length uint64
+		length, ok = p.varint()
+		ok = ok && length <= uint64(len(p.data))
+		if ok {
+			p.bytes = p.data[:length]
+			p.data = p.data[length:]
+		}
+	case 5:
+		ok = len(p.data) >= 4
+		if ok {
+			p.value = uint64(binary.LittleEndian.Uint32(p.data))
+			p.data = p.data[4:]
+		}
+	default:
+		p.err = fmt.Errorf("osm: unknown protobuf wire type %d", key&7)
+		return false
+	}
+	if !ok {
+		p.err = errTruncated
+	}
+	return ok
+}
+
+func (p *protobuf) varint() (uint64, bool) {
+	value, n := binary.Uvarint(p.data)
+	if n <= 0 {
+		p.err = errTruncated
+		return 0, false
+	}
+	p.data = p.data[n:]
+	return value, true
+}
+
+// packed decodes packed repeated varints of the current field.
+func (p *protobuf) packed() []uint64 {
+	values := make([]uint64, 0, len(p.bytes))
+	data := p.bytes
+	for len(data) > 0 {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			p.err = errTruncated
+			return nil
+		}
+		values = append(values, value)
+		data = data[n:]
+	}
+	return values
+}
+
+func zigzag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,202 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// message encodes protobuf messages of tests.
+type message []byte
+
+func (m message) varint(field int, value uint64) message {
+	m = binary.AppendUvarint(m, uint64(field)<<3)
+	return binary.AppendUvarint(m, value)
+}
+
+func (m message) bytes(field int, data []byte) message {
+	m = binary.AppendUvarint(m, uint64(field)<<3|2)
+	m = binary.AppendUvarint(m, uint64(len(data)))
+	return append(m, data...)
+}
+
+func (m message) packed(field int, values ...uint64) message {
+	var data []byte
+	for _, value := range values {
+		data = binary.AppendUvarint(data, value)
+	}
+	return m.bytes(field, data)
+}
+
+func unzigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+// frame returns blob of the type with size prefixed header.
+func frame(blobType string, blob message) []byte {
+	header := message{}.bytes(1, []byte(blobType)).varint(3, uint64(len(blob)))
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(header)))
+	return append(append(size[:], header...), blob...)
+}
+
+func raw(data message) message {
+	return message{}.bytes(1, data)
+}
+
+func compressed(t *testing.T, data message) message {
+	var buffer bytes.Buffer
+	writer := zlib.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return message{}.varint(2, uint64(len(data))).bytes(3, buffer.Bytes())
+}
+
+func stringTable(values ...string) message {
+	table := message{}
+	for _, value := range values {
+		table = table.bytes(1, []byte(value))
+	}
+	return table
+}
+
+// primitiveBlock returns block with string table "", "amenity", "cafe", "name", "Kofe", "highway", "footway".
+func primitiveBlock(groups ...message) message {
+	block := message{}.bytes(1, stringTable("", "amenity", "cafe", "name", "Kofe", "highway", "footway"))
+	for _, group := range groups {
+		block = block.bytes(2, group)
+	}
+	return block
+}
+
+var pbfHeader = message{}.bytes(4, []byte("OsmSchema-V0.6")).bytes(4, []byte("DenseNodes"))
+
+type elements struct {
+	nodes []Node
+	ways  []Way
+}
+
+func readPBF(data []byte) (elements, error) {
+	var e elements
+	err := ReadPBF(bytes.NewReader(data), Handler{
+		Node: func(node Node) { e.nodes = append(e.nodes, node) },
+		Way:  func(way Way) { e.ways = append(e.ways, way) },
+	})
+	return e, err
+}
+
+func TestReadPBF(t *testing.T) {
+	dense := message{}.
+		packed(1, unzigzag(10), unzigzag(2)).
+		packed(8, unzigzag(557500000), unzigzag(-1000)).
+		packed(9, unzigzag(376200000), unzigzag(2000)).
+		packed(10, 1, 2, 3, 4, 0, 0)
+	node := message{}.
+		varint(1, unzigzag(-5)).
+		packed(2, 3).packed(3, 4).
+		varint(8, unzigzag(100)).
+		varint(9, unzigzag(-100))
+	way := message{}.
+		varint(1, 7).
+		packed(2, 5).packed(3, 6).
+		packed(8, unzigzag(10), unzigzag(2), unzigzag(-7))
+	data := primitiveBlock(message{}.bytes(2, dense), message{}.bytes(1, node), message{}.bytes(3, way))
+	offset := primitiveBlock(message{}.bytes(1, message{}.varint(1, unzigzag(1)).varint(8, unzigzag(1)).varint(9, 0))).
+		varint(17, 1000).varint(19, 1000).varint(20, 500)
+
+	tests := []struct {
+		name string
+		data []byte
+		want elements
+	}{
+		{"empty", nil, elements{}},
+		{"header", frame("OSMHeader", raw(pbfHeader)), elements{}},
+		{"raw", append(frame("OSMHeader", raw(pbfHeader)), frame("OSMData", raw(data))...), elements{
+			nodes: []Node{
+				{ID: 10, Lat: 55.75, Lng: 37.62, Tags: map[string]string{"amenity": "cafe", "name": "Kofe"}},
+				{ID: 12, Lat: 55.7499, Lng: 37.6202},
+				{ID: -5, Lat: 0.00001, Lng: -0.00001, Tags: map[string]string{"name": "Kofe"}},
+			},
+			ways: []Way{{ID: 7, Nodes: []int64{10, 12, 5}, Tags: map[string]string{"highway": "footway"}}},
+		}},
+		{"zlib", frame("OSMData", compressed(t, primitiveBlock(message{}.bytes(3, way)))), elements{
+			ways: []Way{{ID: 7, Nodes: []int64{10, 12, 5}, Tags: map[string]string{"highway": "footway"}}},
+		}},
+		{"granularity and offsets", frame("OSMData", raw(offset)), elements{
+			nodes: []Node{{ID: 1, Lat: 0.000002, Lng: 0.0000005}},
+		}},
+		{"unknown blob", frame("OSMIndex", raw(message{}.varint(1, 1))), elements{}},
+	}
+	for _, test := range tests {
+		got, err := readPBF(test.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !equalElements(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+// equalElements compares elements with coordinates rounded to the precision of the format.
+func equalElements(a elements, b elements) bool {
+	if len(a.nodes) != len(b.nodes) || !reflect.DeepEqual(a.ways, b.ways) {
+		return false
+	}
+	for i := range a.nodes {
+		x, y := a.nodes[i], b.nodes[i]
+		if x.ID != y.ID || !reflect.DeepEqual(x.Tags, y.Tags) ||
+			math.Abs(x.Lat-y.Lat) > 1e-9 || math.Abs(x.Lng-y.Lng) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReadPBFErrors(t *testing.T) {
+	hugeHeader := message{}.bytes(1, []byte("OSMData")).varint(3, math.MaxUint64)
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(hugeHeader)))
+	hugeBlob := append(size[:], hugeHeader...)
+	blob := frame("OSMData", raw(message{}.varint(1, 1)))
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"truncated size", []byte{0, 0}, "unexpected EOF"},
+		{"large header", []byte{0, 1, 0, 1}, "blob header of 65537 bytes is too large"},
+		{"truncated header", frame("OSMData", nil)[:4], "EOF"},
+		{"truncated blob", blob[:len(blob)-1], "unexpected EOF"},
+		{"negative blob size", hugeBlob, "is too large"},
+		{"unsupported feature", frame("OSMHeader", raw(message{}.bytes(4, []byte("HistoricalInformation")))),
+			"required feature HistoricalInformation is not supported"},
+		{"lzma", frame("OSMData", message{}.bytes(4, []byte{1})), "blob compression 4 is not supported"},
+		{"no data", frame("OSMData", message{}.varint(2, 10)), "blob has no data"},
+		{"broken zlib", frame("OSMData", message{}.bytes(3, []byte("zlib"))), "osm: zlib"},
+		{"truncated message", frame("OSMData", raw(message{0x12, 10, 1})), "truncated protobuf message"},
+		{"wire type", frame("OSMData", raw(message{0x0f})), "unknown protobuf wire type 7"},
+		{"dense lengths", frame("OSMData", raw(primitiveBlock(message{}.bytes(2,
+			message{}.packed(1, 2).packed(8, 2, 2).packed(9, 2))))), "dense nodes have 1 ids, 2 lats and 1 lons"},
+		{"dense tags", frame("OSMData", raw(primitiveBlock(message{}.bytes(2,
+			message{}.packed(1, 2).packed(8, 2).packed(9, 2).packed(10, 1))))), "truncated protobuf message"},
+		{"string table", frame("OSMData", raw(primitiveBlock(message{}.bytes(3,
+			message{}.varint(1, 1).packed(2, 99).packed(3, 1))))), "string 99 is out of string table"},
+	}
+	for _, test := range tests {
+		_, err := readPBF(test.data)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package osm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// POI is a point of interest in the schema of datasets.
+type POI struct {
+	// ID is an OpenStreetMap element, e.g. "node/123" or "way/45".
+	ID         string
+	Title      string
+	Lat        float64
+	Lng        float64
+	Address    string
+	Categories []string
+	// Duration is an estimated visit duration in minutes.
+	Duration  int
+	OpenHours map[string][]int
+}
+
+// Import is a result of POI import.
+type Import struct {
+	POIs []POI
+	// InvalidHours are opening_hours values which are not supported, such POIs are open all days.
+	InvalidHours []string
+}
+
+// feature describes selected values of OpenStreetMap key, Category is a category of datasets.
+type feature struct {
+	Category string
+	Duration int
+}
+
+// Dataset categories.
+const (
+	SightsCategory      = "Sights & Landmarks"
+	MuseumsCategory     = "Museums & Libraries"
+	NatureCategory      = "Nature & Parks"
+	ShowsCategory       = "Concerts & Shows"
+	RestaurantsCategory = "Restaurant"
+)
+
+// features are selected tags, "*" describes other values of the key, keys are checked in order of featureKeys.
+var features = map[string]map[string]feature{
+	"tourism": {
+		"museum":     {MuseumsCategory, 120},
+		"gallery":    {MuseumsCategory, 60},
+		"zoo":        {NatureCategory, 180},
+		"aquarium":   {NatureCategory, 120},
+		"theme_park": {NatureCategory, 180},
+		"viewpoint":  {SightsCategory, 15},
+		"artwork":    {SightsCategory, 10},
+		"attraction": {SightsCategory, 30},
+	},
+	"historic": {
+		"castle":   {SightsCategory, 60},
+		"fort":     {SightsCategory, 60},
+		"ruins":    {SightsCategory, 30},
+		"monument": {SightsCategory, 15},
+		"memorial": {SightsCategory, 10},
+		"*":        {SightsCategory, 15},
+	},
+	"amenity": {
+		"theatre":          {ShowsCategory, 150},
+		"cinema":           {ShowsCategory, 120},
+		"arts_centre":      {ShowsCategory, 90},
+		"concert_hall":     {ShowsCategory, 150},
+		"library":          {MuseumsCategory, 60},
+		"place_of_worship": {SightsCategory, 20},
+		"fountain":         {SightsCategory, 10},
+		"marketplace":      {SightsCategory, 45},
+		"restaurant":       {RestaurantsCategory, 90},
+		"cafe":             {RestaurantsCategory, 45},
+		"pub":              {RestaurantsCategory, 60},
+		"bar":              {RestaurantsCategory, 60},
+	},
+	"leisure": {
+		"park":           {NatureCategory, 60},
+		"garden":         {NatureCategory, 45},
+		"nature_reserve": {NatureCategory, 120},
+	},
+}
+
+var featureKeys = []string{"tourism", "historic", "amenity", "leisure"}
+
+// classify returns categories and duration of tags, ok is false if element is not a POI.
+// The first matching key defines main category and duration, all matching tags are added as categories.
+func classify(tags map[string]string) (categories []string, duration int, ok bool) {
+	for _, key := range featureKeys {
+		value, tagged := tags[key]
+		if !tagged || value == "no" {
+			continue
+		}
+		f, known := features[key][value]
+		if !known {
+			if f, known = features[key]["*"]; !known {
+				continue
+			}
+		}
+		if !ok {
+			categories = append(categories, f.Category)
+			duration = f.Duration
+			ok = true
+		}
+		categories = append(categories, key+"="+value)
+	}
+	return categories, duration, ok
+}
+
+// ImportPOIs reads named tourism, historic, amenity and leisure features of extract. Ways are placed
+// at average of their nodes, relations are not imported. POIs without opening_hours are open all days.
+func ImportPOIs(path string) (*Import, error) {
+	coordinates := make(map[int64][2]float64)
+	result := &Import{POIs: make([]POI, 0)}
+	add := func(id string, lat float64, lng float64, tags map[string]string) {
+		name := tags["name"]
+		categories, duration, ok := classify(tags)
+		if name == "" || !ok {
+			return
+		}
+		poi := POI{
+			ID:         id,
+			Title:      name,
+			Lat:        lat,
+			Lng:        lng,
+			Address:    address(tags),
+			Categories: categories,
+			Duration:   duration,
+			OpenHours:  AlwaysOpen(),
+		}
+		if value, ok := tags["opening_hours"]; ok {
+			hours, err := ParseOpeningHours(value)
+			if err != nil {
+				result.InvalidHours = append(result.InvalidHours, value)
+			} else {
+				poi.OpenHours = hours
+			}
+		}
+		result.POIs = append(result.POIs, poi)
+	}
+
+	err := ReadFile(path, Handler{
+		Node: func(node Node) {
+			coordinates[node.ID] = [2]float64{node.Lat, node.Lng}
+			if node.Tags != nil {
+				add(fmt.Sprintf("node/%d", node.ID), node.Lat, node.Lng, node.Tags)
+			}
+		},
+		Way: func(way Way) {
+			if way.Tags == nil {
+				return
+			}
+			var lat, lng float64
+			count := 0
+			for i, ref := range way.Nodes {
+				// closed ways repeat the first node
+				if i > 0 && i == len(way.Nodes)-1 && ref == way.Nodes[0] {
+					break
+				}
+				if c, ok := coordinates[ref]; ok {
+					lat, lng = lat+c[0], lng+c[1]
+					count++
+				}
+			}
+			if count > 0 {
+				add(fmt.Sprintf("way/%d", way.ID), lat/float64(count), lng/float64(count), way.Tags)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// address joins street, house number and city of addr tags.
+func address(tags map[string]string) string {
+	parts := make([]string, 0, 3)
+	street := strings.TrimSpace(tags["addr:street"] + " " + tags["addr:housenumber"])
+	for _, part := range []string{street, tags["addr:city"]} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,75 @@
+package osm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type xmlTag struct {
+	Key   string `xml:"k,attr"`
+	Value string `xml:"v,attr"`
+}
+
+type xmlNode struct {
+	ID   int64    `xml:"id,attr"`
+	Lat  float64  `xml:"lat,attr"`
+	Lon  float64  `xml:"lon,attr"`
+	Tags []xmlTag `xml:"tag"`
+}
+
+type xmlWay struct {
+	ID    int64 `xml:"id,attr"`
+	Nodes []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []xmlTag `xml:"tag"`
+}
+
+func tagMap(tags []xmlTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[t.Key] = t.Value
+	}
+	return m
+}
+
+// ReadXML reads OpenStreetMap XML, e.g. exported from openstreetmap.org.
+func ReadXML(r io.Reader, handler Handler) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("osm: %v", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case element.Name.Local == "node" && handler.Node != nil:
+			var n xmlNode
+			if err := decoder.DecodeElement(&n, &element); err != nil {
+				return fmt.Errorf("osm: %v", err)
+			}
+			handler.Node(Node{ID: n.ID, Lat: n.Lat, Lng: n.Lon, Tags: tagMap(n.Tags)})
+		case element.Name.Local == "way" && handler.Way != nil:
+			var w xmlWay
+			if err := decoder.DecodeElement(&w, &element); err != nil {
+				return fmt.Errorf("osm: %v", err)
+			}
+			way := Way{ID: w.ID, Nodes: make([]int64, len(w.Nodes)), Tags: tagMap(w.Tags)}
+			for i, nd := range w.Nodes {
+				way.Nodes[i] = nd.Ref
+			}
+			handler.Way(way)
+		}
+	}
+}
@@ -4,14 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math"
 
 	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/spatial"
+	"github.com/mukhinaks/fops/taxonomy"
 )
 
 type BaseLocations struct {
 	Points []BaseLocation
 	solver *generic.Solver
+	// projection of location coordinates, see projectLocations.
+	projection geo.Projection
+	// index of location coordinates, item IDs are positions in Points.
+	index *spatial.KDTree
+	// all are pointers to Points, they are shared by all calls of GetAllPoints.
+	all []generic.Point
+	// positions of locations by external ID.
+	positions map[generic.LocationID]int
 }
 
 type BaseLocation struct {
@@ -36,49 +47,97 @@ type BaseLocation struct {
 	TripAdvisorReviewsNumber float64 `json:"tripAdvisor_reviewsNumber"`
 	X                        float64 `json:"x"`
 	Y                        float64 `json:"y"`
-	ID                       int     `json:"id"`
+	// ID is an external ID from dataset, it is a position of location if dataset has no IDs.
+	ID generic.LocationID `json:"id"`
+	// Day is a number of the day of multi-day routes.
+	Day int `json:"day,omitempty"`
+	// SourceCategories are categories of dataset before they were normalized, see DataTaxonomy.
+	SourceCategories []string `json:"source_categories,omitempty"`
+	// Imputed lists fields which values were imputed while dataset was loaded, see DataImpute.
+	Imputed []string `json:"imputed,omitempty"`
 }
 
-func (locations BaseLocations) Init(solver *generic.Solver) generic.Points {
+func (locations BaseLocations) Init(solver *generic.Solver) (generic.Points, error) {
 	locations.solver = solver
-	data, err := locations.readLocations(solver.Configuration["DataPath"].(string))
+	categories, err := readTaxonomy(solver.Configuration.DataConfig)
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	data, err := locations.readLocations(solver.Configuration.DataConfig, categories)
+	if err != nil {
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("points: %s: %w", solver.Configuration.DataPath, generic.ErrNoPoints)
+	}
+	projected := make([]projectable, len(data))
+	for i := range data {
+		projected[i] = &data[i]
+	}
+	locations.projection, err = projectLocations(projected, solver.Configuration.DataLocalProjection)
+	if err != nil {
+		return nil, fmt.Errorf("points: %s: %v", solver.Configuration.DataPath, err)
+	}
+	ids := make([]*generic.LocationID, len(data))
+	for i := range data {
+		ids[i] = &data[i].ID
+	}
+	if locations.positions, err = identify(ids); err != nil {
+		return nil, fmt.Errorf("points: %s: %v", solver.Configuration.DataPath, err)
 	}
 	locations.Points = data
-	return locations
+	locations.all = make([]generic.Point, len(data))
+	for i := range data {
+		locations.all[i] = &data[i]
+	}
+	locations.index = generic.NewIndex(locations.all)
+	return locations, nil
+}
+
+// Projection returns projection of location coordinates.
+func (locations BaseLocations) Projection() geo.Projection {
+	return locations.projection
+}
+
+// Index returns spatial index of location coordinates.
+func (locations BaseLocations) Index() *spatial.KDTree {
+	return locations.index
 }
 
 func (l *BaseLocation) String() (string, error) {
 	return string(l.Title), nil
 }
 
-func (locations BaseLocations) readLocations(pathToJSON string) ([]BaseLocation, error) {
-	raw, err := ioutil.ReadFile(pathToJSON)
-	if err != nil {
+func (locations BaseLocations) readLocations(config misc.DataConfig, categories *taxonomy.Taxonomy) ([]BaseLocation, error) {
+	data := make([]BaseLocation, 0)
+	if _, err := readDataset(config, categories, &data); err != nil {
 		return nil, err
 	}
-
-	data := make([]BaseLocation, 1)
-	err = json.Unmarshal(raw, &data)
-	if err != nil {
-		return nil, err
+	if _, err := imputeDataset(config, &data); err != nil {
+		return nil, fmt.Errorf("%s: %v", config.DataPath, err)
 	}
 	return data, nil
 }
 
+// Position returns position of location with external ID.
+func (locations BaseLocations) Position(id generic.LocationID) (int, bool) {
+	position, ok := locations.positions[id]
+	return position, ok
+}
+
+// ID returns external ID of location at position.
+func (locations BaseLocations) ID(position int) generic.LocationID {
+	return locations.Points[position].ID
+}
+
+// GetAllPoints returns locations by position in dataset, the slice is shared and should not be modified.
 func (locations BaseLocations) GetAllPoints() []generic.Point {
-	allLocations := make([]generic.Point, 0)
-	for _, l := range locations.Points {
-		allLocations = append(allLocations, l)
-	}
-	return allLocations
+	return locations.all
 }
 
 func (locations BaseLocations) GetCurrentPoints() map[int]generic.Point {
 	currentLocations := make(map[int]generic.Point)
-	for idx, location := range locations.Points {
+	for idx, location := range locations.all {
 		if locations.solver.Constraints.SinglePointConstraints(location, idx) {
 			currentLocations[idx] = location
 		}
@@ -86,38 +145,26 @@ func (locations BaseLocations) GetCurrentPoints() map[int]generic.Point {
 	return currentLocations
 }
 
-func (l BaseLocations) WriteLocationsToJSON(route map[int]generic.Point, order []int, filePath string) {
+func (l BaseLocations) WriteLocationsToJSON(route map[int]generic.Point, order []int, filePath string) error {
 	locations := make([]BaseLocation, 0)
 	for _, idx := range order {
-		locations = append(locations, route[idx].(BaseLocation))
+		locations = append(locations, *route[idx].(*BaseLocation))
+	}
+	locationsJSON, err := json.Marshal(locations)
+	if err != nil {
+		return err
 	}
-	locationsJSON, _ := json.Marshal(locations)
 
-	ioutil.WriteFile(filePath, locationsJSON, 0644)
+	return ioutil.WriteFile(filePath, locationsJSON, 0644)
 }
 
 func (locations BaseLocations) GetPointsInArea(startID int, endID int) map[int]generic.Point {
-	start := locations.Points[startID]
-	end := locations.Points[endID]
-	distance := EuclidianDistance(start, end)
-
-	currentLocations := make(map[int]generic.Point)
-	for idx, location := range locations.Points {
-		if EuclidianDistance(location, start) <= distance && EuclidianDistance(location, end) <= distance {
-			currentLocations[idx] = location
-		}
-	}
-	return currentLocations
+	return pointsInArea(locations.index, func(idx int) generic.Point { return locations.all[idx] },
+		locations.all[startID], locations.all[endID])
 }
 
+// FindClosestPoint returns location nearest to the point given in degrees.
 func (locations BaseLocations) FindClosestPoint(lat float64, lon float64) generic.Point {
-	minDistance := math.MaxFloat64
-	closestPoint := locations.Points[0]
-
-	for _, location := range locations.Points {
-		if HaversineDistance(location.Lat, location.Lng, lat, lon) <= minDistance {
-			closestPoint = location
-		}
-	}
-	return closestPoint
+	x, y := locations.projection.Project(lat, lon)
+	return locations.all[locations.index.Nearest(x, y, 1)[0]]
 }
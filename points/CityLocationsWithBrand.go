@@ -4,13 +4,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strconv"
 
 	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/query"
+	"github.com/mukhinaks/fops/spatial"
+	"github.com/mukhinaks/fops/taxonomy"
 )
 
 type CityBrandLocations struct {
 	Points []CityBrandLocation
 	solver *generic.Solver
+	// projection of location coordinates, see projectLocations.
+	projection geo.Projection
+	// index of location coordinates, item IDs are positions in Points.
+	index *spatial.KDTree
+	// all are pointers to Points, they are shared by all calls of GetAllPoints.
+	all []generic.Point
+	// positions of locations by external ID.
+	positions map[generic.LocationID]int
+	// restaurants are locations where routes stop for meals, see DataRestaurantCategory.
+	restaurants *query.Expression
 }
 
 type CityBrandLocation struct {
@@ -38,50 +54,133 @@ type CityBrandLocation struct {
 	WikipediaTitle           string           `json:"wikipedia_title"`
 	X                        float64          `json:"x"`
 	Y                        float64          `json:"y"`
-	IntervalNumber           int
+	// ID is an external ID from dataset, it is a position of location if dataset has no IDs.
+	ID generic.LocationID `json:"id"`
+	// SourceCategories are categories of dataset before they were normalized, see DataTaxonomy.
+	SourceCategories []string `json:"source_categories,omitempty"`
+	// Imputed lists fields which values were imputed while dataset was loaded, see DataImpute.
+	Imputed        []string `json:"imputed,omitempty"`
+	IntervalNumber int
 }
 
-func (locations CityBrandLocations) Init(solver *generic.Solver) generic.Points {
+func (locations CityBrandLocations) Init(solver *generic.Solver) (generic.Points, error) {
 	locations.solver = solver
-	data, err := locations.readLocations(solver.Configuration["DataPath"].(string))
+	categories, err := readTaxonomy(solver.Configuration.DataConfig)
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	data, err := locations.readLocations(solver.Configuration.DataConfig, categories)
+	if err != nil {
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("points: %s: %w", solver.Configuration.DataPath, generic.ErrNoPoints)
+	}
+	projected := make([]projectable, len(data))
+	for i := range data {
+		projected[i] = &data[i]
+	}
+	locations.projection, err = projectLocations(projected, solver.Configuration.DataLocalProjection)
+	if err != nil {
+		return nil, fmt.Errorf("points: %s: %v", solver.Configuration.DataPath, err)
+	}
+	ids := make([]*generic.LocationID, len(data))
+	for i := range data {
+		ids[i] = &data[i].ID
+	}
+	if locations.positions, err = identify(ids); err != nil {
+		return nil, fmt.Errorf("points: %s: %v", solver.Configuration.DataPath, err)
 	}
 	locations.Points = data
-	fmt.Println(len(data))
-	return locations
+	locations.all = make([]generic.Point, len(data))
+	for i := range data {
+		locations.all[i] = &data[i]
+	}
+	locations.index = generic.NewIndex(locations.all)
+	category := solver.Configuration.DataRestaurantCategory
+	if locations.restaurants, err = restaurantsFilter(category, categories); err != nil {
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	return locations, nil
+}
+
+// restaurantsFilter returns filter of restaurant category, the category is replaced by its canonical
+// category of taxonomy if dataset has it, so that only its subcategories match, e.g. "food/restaurants"
+// and not "food/cafes".
+func restaurantsFilter(category string, categories *taxonomy.Taxonomy) (*query.Expression, error) {
+	if categories != nil {
+		if canonical, ok := categories.Canonical(category); ok {
+			category = canonical
+		}
+	}
+	return query.Parse("category == " + strconv.Quote(category))
+}
+
+// RestaurantPoints are points where routes stop for meals at restaurants, other locations are sights.
+type RestaurantPoints interface {
+	generic.Points
+	// Restaurants returns filter of restaurants, it is nil if points are not initialized.
+	Restaurants() *query.Expression
+}
+
+// Restaurants returns filter of locations where city brand routes stop for meals, see DataRestaurantCategory.
+func (locations CityBrandLocations) Restaurants() *query.Expression {
+	return locations.restaurants
+}
+
+// RestaurantsOf returns filter of restaurants of loaded points.
+func RestaurantsOf(locations generic.Points) (*query.Expression, error) {
+	withRestaurants, ok := locations.(RestaurantPoints)
+	if !ok || withRestaurants.Restaurants() == nil {
+		return nil, fmt.Errorf("points: restaurants are defined by loaded points with restaurants, got %T", locations)
+	}
+	return withRestaurants.Restaurants(), nil
+}
+
+// Projection returns projection of location coordinates.
+func (locations CityBrandLocations) Projection() geo.Projection {
+	return locations.projection
+}
+
+// Index returns spatial index of location coordinates.
+func (locations CityBrandLocations) Index() *spatial.KDTree {
+	return locations.index
 }
 
 func (l *CityBrandLocation) String() (string, error) {
 	return string(l.Title), nil
 }
 
-func (locations CityBrandLocations) readLocations(pathToJSON string) ([]CityBrandLocation, error) {
-	raw, err := ioutil.ReadFile(pathToJSON)
-	if err != nil {
+func (locations CityBrandLocations) readLocations(config misc.DataConfig, categories *taxonomy.Taxonomy) ([]CityBrandLocation, error) {
+	data := make([]CityBrandLocation, 0)
+	if _, err := readDataset(config, categories, &data); err != nil {
 		return nil, err
 	}
-
-	data := make([]CityBrandLocation, 1)
-	err = json.Unmarshal(raw, &data)
-	if err != nil {
-		return nil, err
+	if _, err := imputeDataset(config, &data); err != nil {
+		return nil, fmt.Errorf("%s: %v", config.DataPath, err)
 	}
 	return data, nil
 }
 
+// Position returns position of location with external ID.
+func (locations CityBrandLocations) Position(id generic.LocationID) (int, bool) {
+	position, ok := locations.positions[id]
+	return position, ok
+}
+
+// ID returns external ID of location at position.
+func (locations CityBrandLocations) ID(position int) generic.LocationID {
+	return locations.Points[position].ID
+}
+
+// GetAllPoints returns locations by position in dataset, the slice is shared and should not be modified.
 func (locations CityBrandLocations) GetAllPoints() []generic.Point {
-	allLocations := make([]generic.Point, 0)
-	for _, l := range locations.Points {
-		allLocations = append(allLocations, l)
-	}
-	return allLocations
+	return locations.all
 }
 
 func (locations CityBrandLocations) GetCurrentPoints() map[int]generic.Point {
 	currentLocations := make(map[int]generic.Point)
-	for idx, location := range locations.Points {
+	for idx, location := range locations.all {
 		if locations.solver.Constraints.SinglePointConstraints(location, idx) {
 			currentLocations[idx] = location
 		}
@@ -90,25 +189,19 @@ func (locations CityBrandLocations) GetCurrentPoints() map[int]generic.Point {
 }
 
 func (locations CityBrandLocations) GetPointsInArea(startID int, endID int) map[int]generic.Point {
-	start := locations.Points[startID]
-	end := locations.Points[endID]
-	distance := EuclidianDistance(start, end)
-
-	currentLocations := make(map[int]generic.Point)
-	for idx, location := range locations.Points {
-		if EuclidianDistance(location, start) <= distance && EuclidianDistance(location, end) <= distance {
-			currentLocations[idx] = location
-		}
-	}
-	return currentLocations
+	return pointsInArea(locations.index, func(idx int) generic.Point { return locations.all[idx] },
+		locations.all[startID], locations.all[endID])
 }
 
-func (l CityBrandLocations) WriteLocationsToJSON(route map[int]generic.Point, order []int, filePath string) {
+func (l CityBrandLocations) WriteLocationsToJSON(route map[int]generic.Point, order []int, filePath string) error {
 	locations := make([]CityBrandLocation, 0)
 	for _, idx := range order {
-		locations = append(locations, route[idx].(CityBrandLocation))
+		locations = append(locations, *route[idx].(*CityBrandLocation))
+	}
+	locationsJSON, err := json.Marshal(locations)
+	if err != nil {
+		return err
 	}
-	locationsJSON, _ := json.Marshal(locations)
 
-	ioutil.WriteFile(filePath, locationsJSON, 0644)
+	return ioutil.WriteFile(filePath, locationsJSON, 0644)
 }
@@ -0,0 +1,82 @@
+package points
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/query"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+func TestRestaurantsFilter(t *testing.T) {
+	categories, err := readTaxonomy(misc.DataConfig{DataTaxonomy: "../experiments/taxonomy.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		category   string
+		taxonomy   *taxonomy.Taxonomy
+		categories []string
+		want       bool
+	}{
+		{"default", "Restaurant", nil, []string{"Restaurant"}, true},
+		{"default sight", "Restaurant", nil, []string{"Sights & Landmarks"}, false},
+		{"quoted", `Bar "Moscow"`, nil, []string{`Bar "Moscow"`}, true},
+		{"taxonomy", "Restaurant", categories, []string{"food/restaurants"}, true},
+		{"taxonomy cafe", "Restaurant", categories, []string{"food/cafes"}, false},
+		{"taxonomy bar", "Restaurant", categories, []string{"culture/museums", "food/bars"}, false},
+		{"taxonomy root", "Restaurant", categories, []string{"food"}, false},
+		{"canonical", "food", categories, []string{"food/cafes"}, true},
+		{"unmapped", "Eatery", categories, []string{"Eatery"}, true},
+	}
+	for _, test := range tests {
+		restaurants, err := restaurantsFilter(test.category, test.taxonomy)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if got := restaurants.Match(&CityBrandLocation{Categories: test.categories}); got != test.want {
+			t.Errorf("%s: %q is restaurant %v, want %v", test.name, test.categories, got, test.want)
+		}
+	}
+
+	if categories, err := readTaxonomy(misc.DataConfig{}); categories != nil || err != nil {
+		t.Errorf("taxonomy %v, error %v without DataTaxonomy", categories, err)
+	}
+	_, err = readTaxonomy(misc.DataConfig{DataTaxonomy: "missing.yaml"})
+	if err == nil || !strings.HasPrefix(err.Error(), "taxonomy: ") {
+		t.Errorf("missing taxonomy error = %v", err)
+	}
+}
+
+// restaurantsOnly are points of any schema with restaurants, other methods of Points are not used.
+type restaurantsOnly struct {
+	generic.Points
+	restaurants *query.Expression
+}
+
+func (p restaurantsOnly) Restaurants() *query.Expression {
+	return p.restaurants
+}
+
+func TestRestaurantsOf(t *testing.T) {
+	restaurants, err := query.Parse(`category == "Restaurant"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, locations := range []generic.Points{restaurantsOnly{restaurants: restaurants},
+		CityBrandLocations{restaurants: restaurants}} {
+		if got, err := RestaurantsOf(locations); got != restaurants || err != nil {
+			t.Errorf("RestaurantsOf(%T) = %v, %v", locations, got, err)
+		}
+	}
+	// points without restaurants and points which are not initialized
+	for _, locations := range []generic.Points{BaseLocations{}, CityBrandLocations{}, restaurantsOnly{}} {
+		if _, err := RestaurantsOf(locations); err == nil || !strings.HasPrefix(err.Error(), "points: ") {
+			t.Errorf("RestaurantsOf(%T) error = %v", locations, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package points
+
+// Accessors implement generic.Point for locations of supported datasets.
+
+func (l BaseLocation) Name() string {
+	return l.Title
+}
+
+func (l BaseLocation) Coordinates() (float64, float64) {
+	return l.X, l.Y
+}
+
+func (l BaseLocation) LatLng() (float64, float64) {
+	return l.Lat, l.Lng
+}
+
+func (l *BaseLocation) setCoordinates(x float64, y float64) {
+	l.X, l.Y = x, y
+}
+
+func (l *BaseLocation) setLatLng(lat float64, lng float64) {
+	l.Lat, l.Lng = lat, lng
+}
+
+func (l BaseLocation) VisitDuration() int {
+	return l.Duration
+}
+
+func (l BaseLocation) OpeningHours(dayOfWeek string) (int, int, bool) {
+	return openingHours(l.OpenHours, dayOfWeek)
+}
+
+// Attribute has a pointer receiver, it is called for every location by scores and filters.
+func (l *BaseLocation) Attribute(name string) float64 {
+	switch name {
+	case "duration":
+		return float64(l.Duration)
+	case "foursquare_checkinsCount":
+		return l.FoursquareCheckinsCount
+	case "foursquare_rating":
+		return l.FoursquareRating
+	case "foursquare_ratingVotes":
+		return l.FoursquareRatingVotes
+	case "foursquare_userCount":
+		return l.FoursquareUserCount
+	case "instagram_visitorsList":
+		return float64(len(l.InstagramVisitorsList))
+	case "instagram_visitorsNumber":
+		return l.InstagramVisitorsNumber
+	case "lat":
+		return l.Lat
+	case "lng":
+		return l.Lng
+	case "officialGuide":
+		return l.OfficialGuide
+	case "tripAdvisor_rating":
+		return l.TripAdvisorRating
+	case "tripAdvisor_reviewsNumber":
+		return l.TripAdvisorReviewsNumber
+	case "x":
+		return l.X
+	case "y":
+		return l.Y
+	case "category":
+		return float64(len(l.Category))
+	}
+	return 0
+}
+
+func (l BaseLocation) CategoryNames() []string {
+	return l.Category
+}
+
+func (l CityBrandLocation) Name() string {
+	return l.Title
+}
+
+func (l CityBrandLocation) Coordinates() (float64, float64) {
+	return l.X, l.Y
+}
+
+func (l CityBrandLocation) LatLng() (float64, float64) {
+	return l.Lat, l.Lng
+}
+
+func (l *CityBrandLocation) setCoordinates(x float64, y float64) {
+	l.X, l.Y = x, y
+}
+
+func (l *CityBrandLocation) setLatLng(lat float64, lng float64) {
+	l.Lat, l.Lng = lat, lng
+}
+
+func (l CityBrandLocation) VisitDuration() int {
+	return l.Duration
+}
+
+func (l CityBrandLocation) OpeningHours(dayOfWeek string) (int, int, bool) {
+	return openingHours(l.OpenHours, dayOfWeek)
+}
+
+func (l *CityBrandLocation) Attribute(name string) float64 {
+	switch name {
+	case "additional_categories":
+		return float64(len(l.AdditionalCategories))
+	case "categories":
+		return float64(len(l.Categories))
+	case "city_brand":
+		return float64(len(l.CityBrand))
+	case "duration":
+		return float64(l.Duration)
+	case "facebook_checkins":
+		return l.FacebookCheckins
+	case "facebook_rating":
+		return l.FacebookRating
+	case "foursquare_checkinsCount":
+		return l.FoursquareCheckinsCount
+	case "foursquare_rating":
+		return l.FoursquareRating
+	case "foursquare_ratingVotes":
+		return l.FoursquareRatingVotes
+	case "foursquare_userCount":
+		return l.FoursquareUserCount
+	case "instagram_visitorsNumber":
+		return l.InstagramVisitorsNumber
+	case "lat":
+		return l.Lat
+	case "lng":
+		return l.Lng
+	case "tripAdvisor_rating":
+		return l.TripAdvisorRating
+	case "tripAdvisor_reviewsNumber":
+		return l.TripAdvisorReviewsNumber
+	case "wikipedia_page":
+		return l.WikipediaPage
+	case "x":
+		return l.X
+	case "y":
+		return l.Y
+	}
+	return 0
+}
+
+func (l CityBrandLocation) CategoryNames() []string {
+	return l.Categories
+}
+
+func (l Location) Name() string {
+	return ""
+}
+
+func (l Location) Coordinates() (float64, float64) {
+	return l.X, l.Y
+}
+
+func (l Location) LatLng() (float64, float64) {
+	return l.Lat, l.Lng
+}
+
+func (l Location) VisitDuration() int {
+	return 0
+}
+
+func (l Location) OpeningHours(dayOfWeek string) (int, int, bool) {
+	return 0, 2400, true
+}
+
+func (l Location) Attribute(name string) float64 {
+	return 0
+}
+
+func (l Location) CategoryNames() []string {
+	return nil
+}
+
+// openingHours reads open hours stored as {"0": [open, close]}, null means that location is closed.
+func openingHours(openHours map[string][]int, dayOfWeek string) (int, int, bool) {
+	hours, ok := openHours[dayOfWeek]
+	if !ok || len(hours) < 2 {
+		return 0, 0, false
+	}
+	return hours[0], hours[1], true
+}
@@ -1,87 +1,33 @@
 package points
 
 import (
-	"fmt"
 	"math"
 
 	"github.com/mukhinaks/fops/generic"
 )
 
 type Location struct {
-	X float64
-	Y float64
+	X   float64
+	Y   float64
+	Lat float64
+	Lng float64
 }
 
 func EuclidianDistance(location1 generic.Point, location2 generic.Point) float64 {
-	switch v := location1.(type) {
-	case Location:
-		loc1 := location1.(Location)
-		loc2 := location2.(Location)
-		return distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y)
-	case BaseLocation:
-		loc1 := location1.(BaseLocation)
-		loc2 := location2.(BaseLocation)
-		return distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y)
-	case CityBrandLocation:
-		loc1 := location1.(CityBrandLocation)
-		loc2 := location2.(CityBrandLocation)
-		return distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y)
-	default:
-		fmt.Println("Unexpected location type", v)
-		return -1
-	}
-
+	x1, y1 := location1.Coordinates()
+	x2, y2 := location2.Coordinates()
+	return distanceToPoint(x1, y1, x2, y2)
 }
 
 func EuclidianDistanceToLineSegment(startLocation generic.Point, endLocation generic.Point, newLocation generic.Point) float64 {
-
-	switch v := startLocation.(type) {
-	case Location:
-		start := startLocation.(Location)
-		end := endLocation.(Location)
-		location := newLocation.(Location)
-
-		return distanceToLine(start.X, start.Y, end.X, end.Y, location.X, location.Y)
-
-	case BaseLocation:
-		start := startLocation.(BaseLocation)
-		end := endLocation.(BaseLocation)
-		location := newLocation.(BaseLocation)
-
-		return distanceToLine(start.X, start.Y, end.X, end.Y, location.X, location.Y)
-
-	case CityBrandLocation:
-		start := startLocation.(CityBrandLocation)
-		end := endLocation.(CityBrandLocation)
-		location := newLocation.(CityBrandLocation)
-
-		return distanceToLine(start.X, start.Y, end.X, end.Y, location.X, location.Y)
-	default:
-		fmt.Println("Unexpected location type", v)
-		return -1
-	}
+	startX, startY := startLocation.Coordinates()
+	endX, endY := endLocation.Coordinates()
+	x, y := newLocation.Coordinates()
+	return distanceToLine(startX, startY, endX, endY, x, y)
 }
 
 func WalkingTime(location1 generic.Point, location2 generic.Point) int {
-
-	switch v := location1.(type) {
-	case Location:
-		loc1 := location1.(Location)
-		loc2 := location2.(Location)
-		return int(distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y) / 66.7)
-	case BaseLocation:
-		loc1 := location1.(BaseLocation)
-		loc2 := location2.(BaseLocation)
-		return int(distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y) / 66.7)
-	case CityBrandLocation:
-		loc1 := location1.(CityBrandLocation)
-		loc2 := location2.(CityBrandLocation)
-		return int(distanceToPoint(loc1.X, loc1.Y, loc2.X, loc2.Y) / 66.7)
-	default:
-		fmt.Println("Unexpected location type", v)
-		return -1
-	}
-
+	return int(EuclidianDistance(location1, location2) / 66.7)
 }
 
 func distanceToPoint(loc1Lat float64, loc1Lng float64, loc2Lat float64, loc2Lng float64) float64 {
@@ -89,26 +35,6 @@ func distanceToPoint(loc1Lat float64, loc1Lng float64, loc2Lat float64, loc2Lng
 	return result
 }
 
-func convertToRadians(angle float64) float64 {
-	rad := angle * 180 / math.Pi
-	return rad
-}
-
-func HaversineDistance(loc1Lat float64, loc1Lng float64, loc2Lat float64, loc2Lng float64) float64 {
-	R := 6371 * 1000.0 // Earth radius in meters
-	phi1 := convertToRadians(loc1Lat)
-	phi2 := convertToRadians(loc2Lat)
-	deltaPhi := convertToRadians(loc2Lat - loc1Lat)
-	deltaLambda := convertToRadians(loc2Lng - loc1Lng)
-
-	a := math.Pow(math.Sin(deltaPhi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(deltaLambda/2), 2)
-
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	distance := R * c
-	return distance
-}
-
 func scalarDot(loc1Lat float64, loc1Lng float64, loc2Lat float64, loc2Lng float64) float64 {
 	result := (loc1Lat * loc2Lat) + (loc1Lng * loc2Lng)
 	return result
@@ -131,8 +57,8 @@ func distanceToLine(loc1Lat float64, loc1Lng float64, loc2Lat float64, loc2Lng f
 	}
 
 	b := scalarProduct / length
-	locLat := loc1Lat + b*vector1Lat
-	locLng := loc1Lng + b*vector1Lng
+	locLat := loc1Lat - b*vector1Lat
+	locLng := loc1Lng - b*vector1Lng
 
 	return distanceToPoint(newLat, newLng, locLat, locLng)
 }
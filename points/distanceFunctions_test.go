@@ -0,0 +1,55 @@
+package points
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+func TestEuclidianDistanceToLineSegment(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end Location
+		location   Location
+		want       float64
+	}{
+		{"above middle", Location{X: 0, Y: 0}, Location{X: 10, Y: 0}, Location{X: 5, Y: 3}, 3},
+		{"near start", Location{X: 0, Y: 0}, Location{X: 10, Y: 0}, Location{X: 2, Y: -4}, 4},
+		{"near end", Location{X: 0, Y: 0}, Location{X: 10, Y: 0}, Location{X: 9, Y: 1}, 1},
+		{"reversed segment", Location{X: 10, Y: 0}, Location{X: 0, Y: 0}, Location{X: 2, Y: -4}, 4},
+		{"diagonal", Location{X: 1, Y: 1}, Location{X: 5, Y: 5}, Location{X: 4, Y: 2}, math.Sqrt2},
+		{"before start", Location{X: 0, Y: 0}, Location{X: 10, Y: 0}, Location{X: -3, Y: 4}, 5},
+		{"after end", Location{X: 0, Y: 0}, Location{X: 10, Y: 0}, Location{X: 13, Y: 4}, 5},
+		{"on segment", Location{X: 0, Y: 0}, Location{X: 0, Y: 10}, Location{X: 0, Y: 7}, 0},
+		{"empty segment", Location{X: 1, Y: 1}, Location{X: 1, Y: 1}, Location{X: 4, Y: 5}, 5},
+	}
+	for _, test := range tests {
+		got := EuclidianDistanceToLineSegment(test.start, test.end, test.location)
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: EuclidianDistanceToLineSegment = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		location generic.Point
+		field    string
+		want     float64
+	}{
+		{"base duration", &BaseLocation{Duration: 45}, "duration", 45},
+		{"base rating", &BaseLocation{TripAdvisorRating: 4.5}, "tripAdvisor_rating", 4.5},
+		{"base categories", &BaseLocation{Category: []string{"a", "b"}}, "category", 2},
+		{"base unknown", &BaseLocation{Duration: 45}, "missing", 0},
+		{"city brand", &CityBrandLocation{CityBrand: []string{"a"}}, "city_brand", 1},
+		{"city brand coordinates", &CityBrandLocation{X: 3, Y: 4}, "y", 4},
+		{"city brand unknown", &CityBrandLocation{Duration: 45}, "category", 0},
+	}
+	for _, test := range tests {
+		if got := test.location.Attribute(test.field); got != test.want {
+			t.Errorf("%s: Attribute(%q) = %v, want %v", test.name, test.field, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package points
+
+import (
+	"strings"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// filter selects locations while dataset is loaded, see DataBounds, DataCategories and DataMinPopularity.
+// Categories of filter are canonical if dataset has taxonomy, they match their subcategories.
+type filter struct {
+	bounds          [4]float64
+	hasBounds       bool
+	categories      map[string]bool
+	popularityField string
+	minPopularity   float64
+}
+
+func newFilter(config misc.DataConfig, categories *taxonomy.Taxonomy) (filter, error) {
+	f := filter{popularityField: config.DataPopularityField, minPopularity: config.DataMinPopularity}
+	var err error
+	if f.bounds, f.hasBounds, err = config.Bounds(); err != nil {
+		return f, err
+	}
+	if strings.TrimSpace(config.DataCategories) != "" {
+		f.categories = make(map[string]bool)
+		for _, category := range strings.Split(config.DataCategories, ",") {
+			category = strings.TrimSpace(category)
+			if categories != nil {
+				if canonical, ok := categories.Canonical(category); ok {
+					category = canonical
+				}
+			}
+			f.categories[category] = true
+		}
+	}
+	return f, nil
+}
+
+func (f filter) accepts(location generic.Point) bool {
+	if f.hasBounds {
+		lat, lng := location.LatLng()
+		if lat < f.bounds[0] || lng < f.bounds[1] || lat > f.bounds[2] || lng > f.bounds[3] {
+			return false
+		}
+	}
+	if f.categories != nil {
+		found := false
+		for _, category := range location.CategoryNames() {
+			for accepted := range f.categories {
+				found = found || taxonomy.Matches(category, accepted)
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return f.minPopularity == 0 || location.Attribute(f.popularityField) >= f.minPopularity
+}
@@ -0,0 +1,160 @@
+package points
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// randomDataset returns JSON array of locations in Moscow with random categories and popularity.
+func randomDataset(t *testing.T, size int) string {
+	random := rand.New(rand.NewSource(1))
+	categories := []string{"Museums", "Parks", "Restaurant", "Sights & Landmarks"}
+	locations := make([]BaseLocation, size)
+	for i := range locations {
+		locations[i] = BaseLocation{
+			Title:                   fmt.Sprintf("Location %d", i),
+			Lat:                     55.6 + random.Float64()*0.3,
+			Lng:                     37.4 + random.Float64()*0.4,
+			Category:                []string{categories[random.Intn(len(categories))]},
+			InstagramVisitorsNumber: float64(random.Intn(1000)),
+			FoursquareCheckinsCount: float64(random.Intn(1000)),
+		}
+		if i%10 == 0 {
+			locations[i].Category = append(locations[i].Category, categories[random.Intn(len(categories))])
+		}
+	}
+	data, err := json.Marshal(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestFilterWhileLoading checks that locations filtered while dataset is read are the same
+// as locations of the whole dataset filtered after loading.
+func TestFilterWhileLoading(t *testing.T) {
+	dataset := randomDataset(t, 500)
+	all, err := readText(t, "city.json", dataset, misc.DataConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 500 {
+		t.Fatalf("dataset has %d locations, want 500", len(all))
+	}
+
+	tests := []struct {
+		name   string
+		config misc.DataConfig
+	}{
+		{"bounds", misc.DataConfig{DataBounds: "55.7,37.5,55.8,37.7"}},
+		{"categories", misc.DataConfig{DataCategories: "Museums, Parks"}},
+		{"popularity", misc.DataConfig{DataMinPopularity: 500, DataPopularityField: "instagram_visitorsNumber"}},
+		{"other popularity field", misc.DataConfig{DataMinPopularity: 900, DataPopularityField: "foursquare_checkinsCount"}},
+		{"all filters", misc.DataConfig{DataBounds: "55.6,37.4,55.8,37.6", DataCategories: "Restaurant",
+			DataMinPopularity: 100, DataPopularityField: "instagram_visitorsNumber"}},
+		{"nothing accepted", misc.DataConfig{DataCategories: "Beaches"}},
+	}
+	for _, test := range tests {
+		streamed, err := readText(t, "city.json", dataset, test.config)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		f, err := newFilter(test.config, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		want := make([]BaseLocation, 0)
+		for i := range all {
+			if f.accepts(&all[i]) {
+				want = append(want, all[i])
+			}
+		}
+		if test.name != "nothing accepted" && (len(want) == 0 || len(want) == len(all)) {
+			t.Errorf("%s: filter keeps %d of %d locations", test.name, len(want), len(all))
+		}
+		if !reflect.DeepEqual(streamed, want) {
+			t.Errorf("%s: %d locations are read, want %d locations of filtered dataset", test.name, len(streamed), len(want))
+		}
+	}
+}
+
+func TestFilterAccepts(t *testing.T) {
+	categories, err := taxonomy.Read("../experiments/taxonomy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		config   misc.DataConfig
+		taxonomy *taxonomy.Taxonomy
+		location BaseLocation
+		want     bool
+	}{
+		{"no filters", misc.DataConfig{}, nil, BaseLocation{}, true},
+		{"inside bounds", misc.DataConfig{DataBounds: "55,37,56,38"}, nil, BaseLocation{Lat: 55.5, Lng: 37.5}, true},
+		{"bounds include border", misc.DataConfig{DataBounds: "55,37,56,38"}, nil, BaseLocation{Lat: 56, Lng: 37}, true},
+		{"outside bounds", misc.DataConfig{DataBounds: "55,37,56,38"}, nil, BaseLocation{Lat: 55.5, Lng: 38.1}, false},
+		{"one of categories", misc.DataConfig{DataCategories: "Parks,Museums"}, nil,
+			BaseLocation{Category: []string{"Shops", "Museums"}}, true},
+		{"other category", misc.DataConfig{DataCategories: "Parks"}, nil, BaseLocation{Category: []string{"Museums"}}, false},
+		{"no category", misc.DataConfig{DataCategories: "Parks"}, nil, BaseLocation{}, false},
+		{"subcategory of taxonomy", misc.DataConfig{DataCategories: "food"}, categories,
+			BaseLocation{Category: []string{"food/cafes"}}, true},
+		{"category of taxonomy by source name", misc.DataConfig{DataCategories: "Restaurant"}, categories,
+			BaseLocation{Category: []string{"food/restaurants"}}, true},
+		{"popular", misc.DataConfig{DataMinPopularity: 10, DataPopularityField: "tripAdvisor_reviewsNumber"}, nil,
+			BaseLocation{TripAdvisorReviewsNumber: 10}, true},
+		{"not popular", misc.DataConfig{DataMinPopularity: 10, DataPopularityField: "tripAdvisor_reviewsNumber"}, nil,
+			BaseLocation{TripAdvisorReviewsNumber: 9, InstagramVisitorsNumber: 100}, false},
+	}
+	for _, test := range tests {
+		f, err := newFilter(test.config, test.taxonomy)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := f.accepts(&test.location); got != test.want {
+			t.Errorf("%s: accepts = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	if _, err := newFilter(misc.DataConfig{DataBounds: "56,37,55,38"}, nil); err == nil {
+		t.Errorf("invalid bounds are accepted")
+	}
+}
+
+// TestReadArrayStop checks that reading stops at the first element which is not accepted by visit.
+func TestReadArrayStop(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`[{"title": "a"}, {"title": "b"}, {"title": "c"}, {"title": "d"}]`))
+	visited := make([]string, 0)
+	err := readArray(decoder, func(i int) error {
+		var location BaseLocation
+		if err := decoder.Decode(&location); err != nil {
+			return err
+		}
+		visited = append(visited, location.Title)
+		if i == 1 {
+			return fmt.Errorf("stop at %d", i)
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "stop at 1" {
+		t.Errorf("readArray error %v, want stop at 1", err)
+	}
+	if !reflect.DeepEqual(visited, []string{"a", "b"}) {
+		t.Errorf("visited %v, want a and b", visited)
+	}
+
+	// invalid location stops loading of the dataset
+	_, err = readText(t, "city.json", `[{"title": "a"}, {"title": "b"}, {"lat": "north"}, {"title": "d"}, {`,
+		misc.DataConfig{})
+	if err == nil || !strings.Contains(err.Error(), "location 2: ") {
+		t.Errorf("readDataset error %v, want error of location 2", err)
+	}
+}
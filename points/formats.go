@@ -0,0 +1,484 @@
+package points
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// datasetFormat returns format of the dataset from configuration or by extension of its path.
+func datasetFormat(config misc.DataConfig) string {
+	if config.DataFormat != "" {
+		return config.DataFormat
+	}
+	switch strings.ToLower(filepath.Ext(config.DataPath)) {
+	case ".csv":
+		return "csv"
+	case ".geojson":
+		return "geojson"
+	default:
+		return "json"
+	}
+}
+
+// readDataset decodes locations of the dataset one by one and appends locations accepted by filter
+// of the configuration to data, a pointer to slice of location structures, so that only selected
+// locations of large datasets are kept in memory. Rows of CSV and features of GeoJSON are converted
+// to JSON objects with fields of the location structure first. Categories are normalized by taxonomy
+// of DataTaxonomy (see readTaxonomy) before the filter, it returns numbers of categories which are not
+// in the taxonomy.
+func readDataset(config misc.DataConfig, categories *taxonomy.Taxonomy, data interface{}) (map[string]int, error) {
+	path := config.DataPath
+	unmapped := make(map[string]int)
+	filter, err := newFilter(config, categories)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	slice := reflect.ValueOf(data).Elem()
+	locationType := slice.Type().Elem()
+	add := func(decode func(location interface{}) error) error {
+		location := reflect.New(locationType)
+		if err := decode(location.Interface()); err != nil {
+			return err
+		}
+		if categories != nil {
+			for _, category := range location.Interface().(categorized).normalizeCategories(categories) {
+				unmapped[category]++
+			}
+		}
+		if filter.accepts(location.Interface().(generic.Point)) {
+			slice.Set(reflect.Append(slice, location.Elem()))
+		}
+		return nil
+	}
+	addRecord := func(record map[string]interface{}) error {
+		return add(func(location interface{}) error {
+			raw, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(raw, location)
+		})
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	format := datasetFormat(config)
+	if format == "json" {
+		decoder := json.NewDecoder(reader)
+		err = readArray(decoder, func(i int) error {
+			if err := add(decoder.Decode); err != nil {
+				return fmt.Errorf("location %d: %v", i, err)
+			}
+			return nil
+		})
+	} else {
+		var mapping map[string]string
+		if mapping, err = config.FieldMapping(); err != nil {
+			return nil, err
+		}
+		fields := schemaOf(locationType)
+		for field := range mapping {
+			if _, ok := fields[field]; !ok {
+				return nil, fmt.Errorf("%s: unknown field %q in DataFields", path, field)
+			}
+		}
+
+		switch format {
+		case "csv":
+			err = readCSV(reader, fields, mapping, addRecord)
+		case "geojson":
+			err = readGeoJSON(reader, fields, mapping, addRecord)
+		default:
+			err = fmt.Errorf("unknown format %q", format)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return unmapped, nil
+}
+
+// readArray calls visit for every element of JSON array, visit decodes the element at position i.
+func readArray(decoder *json.Decoder, visit func(i int) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("JSON array expected")
+	}
+	for i := 0; decoder.More(); i++ {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// fieldKind tells how text values are parsed.
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	numberField
+	integerField
+	listField
+	hoursField
+)
+
+// schemaOf returns kinds of JSON fields of location structure.
+func schemaOf(location reflect.Type) map[string]fieldKind {
+	fields := make(map[string]fieldKind)
+	for i := 0; i < location.NumField(); i++ {
+		field := location.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Float64:
+			fields[name] = numberField
+		case reflect.Int:
+			fields[name] = integerField
+		case reflect.Slice:
+			fields[name] = listField
+		case reflect.Map:
+			fields[name] = hoursField
+		default:
+			fields[name] = textField
+		}
+	}
+	return fields
+}
+
+// column returns name of CSV column or GeoJSON property of the field.
+func column(field string, mapping map[string]string) string {
+	if name, ok := mapping[field]; ok {
+		return name
+	}
+	return field
+}
+
+// readCSV converts rows of CSV with header to records, comma or semicolon separates values.
+// Lists are separated by semicolon (or comma if semicolon separates values) or given as JSON arrays.
+func readCSV(r *bufio.Reader, fields map[string]fieldKind, mapping map[string]string, visit func(record map[string]interface{}) error) error {
+	if bom, _ := r.Peek(3); bytes.Equal(bom, []byte("\ufeff")) {
+		r.Discard(3)
+	}
+	// header is in the buffer unless it is longer than the buffer
+	head, _ := r.Peek(r.Size())
+	if end := bytes.IndexByte(head, '\n'); end >= 0 {
+		head = head[:end]
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	listSeparator := ";"
+	if !bytes.ContainsRune(head, ',') && bytes.ContainsRune(head, ';') {
+		reader.Comma = ';'
+		listSeparator = ","
+	}
+
+	names, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]int)
+	for i, name := range names {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for field, name := range mapping {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("column %q of field %s is missing", name, field)
+		}
+	}
+
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		record := make(map[string]interface{})
+		for field, kind := range fields {
+			i, ok := columns[column(field, mapping)]
+			if !ok || i >= len(values) {
+				continue
+			}
+			value, err := parseText(kind, values[i], listSeparator)
+			if err != nil {
+				return fmt.Errorf("line %d: %s: %v", line, field, err)
+			}
+			if value != nil {
+				record[field] = value
+			}
+		}
+		if err := visit(record); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+}
+
+type feature struct {
+	ID       interface{} `json:"id"`
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// readGeoJSON converts features of FeatureCollection to records. Location of the feature is its point
+// or average of vertices of other geometries, properties are converted to fields.
+func readGeoJSON(r io.Reader, fields map[string]fieldKind, mapping map[string]string, visit func(record map[string]interface{}) error) error {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("FeatureCollection expected")
+	}
+
+	collectionType := ""
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case "type":
+			err = decoder.Decode(&collectionType)
+		case "features":
+			err = readArray(decoder, func(i int) error {
+				var f feature
+				if err := decoder.Decode(&f); err != nil {
+					return fmt.Errorf("feature %d: %v", i, err)
+				}
+				record, err := featureRecord(f, fields, mapping)
+				if err == nil {
+					err = visit(record)
+				}
+				if err != nil {
+					return fmt.Errorf("feature %d: %v", i, err)
+				}
+				return nil
+			})
+		default:
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if collectionType != "FeatureCollection" {
+		return fmt.Errorf("FeatureCollection expected, got %q", collectionType)
+	}
+	return nil
+}
+
+func featureRecord(f feature, fields map[string]fieldKind, mapping map[string]string) (map[string]interface{}, error) {
+	record := make(map[string]interface{})
+	for field, kind := range fields {
+		property, ok := f.Properties[column(field, mapping)]
+		if !ok || property == nil {
+			continue
+		}
+		value, err := convertValue(kind, property)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", field, err)
+		}
+		if value != nil {
+			record[field] = value
+		}
+	}
+	// feature ID is used if properties have no ID
+	if _, ok := record["id"]; !ok && f.ID != nil {
+		if _, ok := fields["id"]; ok {
+			id, err := convertValue(textField, f.ID)
+			if err != nil {
+				return nil, fmt.Errorf("id: %v", err)
+			}
+			record["id"] = id
+		}
+	}
+
+	if f.Geometry != nil {
+		lat, lng, err := geometryCenter(f.Geometry.Coordinates)
+		if err != nil {
+			return nil, fmt.Errorf("%s geometry: %v", f.Geometry.Type, err)
+		}
+		record["lat"], record["lng"] = lat, lng
+	}
+	return record, nil
+}
+
+// geometryCenter returns average of all positions of nested GeoJSON coordinates, position is [lng, lat].
+func geometryCenter(coordinates json.RawMessage) (float64, float64, error) {
+	var nested interface{}
+	if err := json.Unmarshal(coordinates, &nested); err != nil {
+		return 0, 0, err
+	}
+	var sumLat, sumLng float64
+	count := 0
+	var visit func(value interface{}) error
+	visit = func(value interface{}) error {
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("invalid coordinates")
+		}
+		if len(items) >= 2 {
+			lng, lngOK := items[0].(float64)
+			lat, latOK := items[1].(float64)
+			if lngOK && latOK {
+				sumLat += lat
+				sumLng += lng
+				count++
+				return nil
+			}
+		}
+		for _, item := range items {
+			if err := visit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(nested); err != nil {
+		return 0, 0, err
+	}
+	if count == 0 {
+		return 0, 0, fmt.Errorf("no positions")
+	}
+	return sumLat / float64(count), sumLng / float64(count), nil
+}
+
+// convertValue converts GeoJSON property to value of the field, text is parsed like CSV values.
+func convertValue(kind fieldKind, value interface{}) (interface{}, error) {
+	if text, ok := value.(string); ok {
+		return parseText(kind, text, ";")
+	}
+	if kind == textField {
+		if number, ok := value.(float64); ok {
+			// integer IDs are not written in exponent form
+			return strconv.FormatFloat(number, 'f', -1, 64), nil
+		}
+		return fmt.Sprint(value), nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var converted interface{}
+	switch kind {
+	case numberField, integerField:
+		var number float64
+		err = json.Unmarshal(raw, &number)
+		converted = number
+		if kind == integerField {
+			converted = int(math.Round(number))
+		}
+	case listField:
+		var list []interface{}
+		err = json.Unmarshal(raw, &list)
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		converted = items
+	case hoursField:
+		var hours map[string][]int
+		err = json.Unmarshal(raw, &hours)
+		converted = hours
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s", raw)
+	}
+	return converted, nil
+}
+
+// parseText parses text value of the field, empty text is a missing value.
+func parseText(kind fieldKind, text string, listSeparator string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	switch kind {
+	case numberField, integerField:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		if kind == integerField {
+			return int(math.Round(number)), nil
+		}
+		return number, nil
+	case listField:
+		items := make([]string, 0)
+		if strings.HasPrefix(text, "[") {
+			if err := json.Unmarshal([]byte(text), &items); err != nil {
+				return nil, fmt.Errorf("invalid list %q", text)
+			}
+			return items, nil
+		}
+		for _, item := range strings.Split(text, listSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	case hoursField:
+		return parseOpenHours(text)
+	}
+	return text, nil
+}
+
+// parseOpenHours parses open hours as JSON object like in JSON datasets, {"1": [900, 1800]},
+// or as one interval for all days, "09:00-18:00" or "900-1800".
+func parseOpenHours(text string) (map[string][]int, error) {
+	hours := make(map[string][]int)
+	if strings.HasPrefix(text, "{") {
+		if err := json.Unmarshal([]byte(text), &hours); err != nil {
+			return nil, fmt.Errorf("invalid open hours %q", text)
+		}
+		return hours, nil
+	}
+
+	parts := strings.Split(text, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid open hours %q", text)
+	}
+	interval := make([]int, 2)
+	for i, part := range parts {
+		value, err := strconv.Atoi(strings.Replace(strings.TrimSpace(part), ":", "", 1))
+		if err != nil || value < 0 || value > 2400 || value%100 >= 60 {
+			return nil, fmt.Errorf("invalid open hours %q", text)
+		}
+		interval[i] = value
+	}
+	for day := 0; day < 7; day++ {
+		hours[strconv.Itoa(day)] = interval
+	}
+	return hours, nil
+}
@@ -0,0 +1,116 @@
+package points
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/misc"
+)
+
+// readText writes dataset text to a file with name and reads it with configuration.
+func readText(t *testing.T, name string, text string, config misc.DataConfig) ([]BaseLocation, error) {
+	dir, err := ioutil.TempDir("", "fops-points")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config.DataPath = filepath.Join(dir, name)
+	if err := ioutil.WriteFile(config.DataPath, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	categories, err := readTaxonomy(config)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]BaseLocation, 0)
+	_, err = readDataset(config, categories, &data)
+	return data, err
+}
+
+func TestReadDatasetFormats(t *testing.T) {
+	allDays := map[string][]int{"0": {900, 1800}, "1": {900, 1800}, "2": {900, 1800}, "3": {900, 1800},
+		"4": {900, 1800}, "5": {900, 1800}, "6": {900, 1800}}
+	tests := []struct {
+		name   string
+		file   string
+		text   string
+		fields string
+		want   []BaseLocation
+	}{
+		{"json", "city.json",
+			`[{"id": "a", "title": "Museum", "lat": 59.9, "lng": 30.3, "duration": 60, "category": ["Museums"]}]`, "",
+			[]BaseLocation{{ID: "a", Title: "Museum", Lat: 59.9, Lng: 30.3, Duration: 60, Category: []string{"Museums"}}}},
+		{"csv with lists and hours", "city.csv",
+			"\ufeffid,title,lat,lng,duration,category,open_hours\n" +
+				"a,Museum,59.9,30.3,59.6,Museums;Sights,09:00-18:00\n" +
+				"b,\"Park, big\",59.8,30.2,,\"[\"\"Parks\"\"]\",\"{\"\"1\"\": [800, 2000]}\"\n", "",
+			[]BaseLocation{
+				{ID: "a", Title: "Museum", Lat: 59.9, Lng: 30.3, Duration: 60, Category: []string{"Museums", "Sights"},
+					OpenHours: allDays},
+				{ID: "b", Title: "Park, big", Lat: 59.8, Lng: 30.2, Category: []string{"Parks"},
+					OpenHours: map[string][]int{"1": {800, 2000}}},
+			}},
+		{"csv separated by semicolon", "city.csv",
+			"title;lat;lng;category\nMuseum;59.9;30.3;Museums,Sights\n", "",
+			[]BaseLocation{{Title: "Museum", Lat: 59.9, Lng: 30.3, Category: []string{"Museums", "Sights"}}}},
+		{"csv with field mapping", "city.csv",
+			"name,latitude,longitude,minutes\nMuseum,59.9,30.3,45\n", "title=name,lat=latitude,lng=longitude,duration=minutes",
+			[]BaseLocation{{Title: "Museum", Lat: 59.9, Lng: 30.3, Duration: 45}}},
+		{"geojson", "city.geojson", `{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "id": 17, "geometry": {"type": "Point", "coordinates": [30.3, 59.9]},
+				"properties": {"title": "Museum", "duration": "60", "category": "Museums;Sights"}},
+			{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[30, 59], [31, 59], [31, 60], [30, 60]]]},
+				"properties": {"name": "Park", "category": ["Parks"], "open_hours": {"0": [900, 1800]}}}]}`,
+			"title=name",
+			[]BaseLocation{
+				{ID: "17", Lat: 59.9, Lng: 30.3, Duration: 60, Category: []string{"Museums", "Sights"}},
+				{Title: "Park", Lat: 59.5, Lng: 30.5, Category: []string{"Parks"},
+					OpenHours: map[string][]int{"0": {900, 1800}}},
+			}},
+	}
+	for _, test := range tests {
+		got, err := readText(t, test.file, test.text, misc.DataConfig{DataFields: test.fields})
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got\n%+v\nwant\n%+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadDatasetErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		text   string
+		fields string
+		err    string
+	}{
+		{"json is not array", "city.json", `{"title": "Museum"}`, "", "JSON array expected"},
+		{"invalid number", "city.csv", "title,lat\nMuseum,north\n", "", `line 2: lat: invalid number "north"`},
+		{"invalid open hours", "city.csv", "title,open_hours\nMuseum,9-25:00\n", "", "invalid open hours"},
+		{"invalid minutes", "city.csv", "title,open_hours\nMuseum,09:75-18:00\n", "", "invalid open hours"},
+		{"invalid list", "city.csv", "title,category\nMuseum,\"[1, 2\"\n", "", "invalid list"},
+		{"missing mapped column", "city.csv", "title\nMuseum\n", "lat=latitude", `column "latitude" of field lat is missing`},
+		{"unknown mapped field", "city.csv", "title\nMuseum\n", "height=h", `unknown field "height" in DataFields`},
+		{"not a collection", "city.geojson", `{"type": "Feature"}`, "", "FeatureCollection expected"},
+		{"geometry without positions", "city.geojson",
+			`{"type": "FeatureCollection", "features": [{"geometry": {"type": "Point", "coordinates": []}}]}`, "",
+			"feature 0: Point geometry: no positions"},
+		{"invalid coordinates", "city.geojson",
+			`{"type": "FeatureCollection", "features": [{"geometry": {"type": "Point", "coordinates": 5}}]}`, "",
+			"invalid coordinates"},
+	}
+	for _, test := range tests {
+		_, err := readText(t, test.file, test.text, misc.DataConfig{DataFields: test.fields})
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
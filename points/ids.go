@@ -0,0 +1,24 @@
+package points
+
+import (
+	"fmt"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+// identify sets positions as IDs of locations without external ID and maps IDs to positions.
+func identify(ids []*generic.LocationID) (map[generic.LocationID]int, error) {
+	for i, id := range ids {
+		if *id == "" {
+			*id = generic.PositionID(i)
+		}
+	}
+	positions := make(map[generic.LocationID]int, len(ids))
+	for i, id := range ids {
+		if first, ok := positions[*id]; ok {
+			return nil, fmt.Errorf("locations %d and %d have the same id %q", first, i, string(*id))
+		}
+		positions[*id] = i
+	}
+	return positions, nil
+}
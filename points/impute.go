@@ -0,0 +1,231 @@
+package points
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// minFitLocations is a minimum number of locations with both values to fit a predictor.
+const minFitLocations = 3
+
+// imputeDataset fills missing values of loaded locations by DataImputeDurations and DataImpute and
+// records imputed fields of every location. It returns numbers of imputed values by field.
+// Missing numbers are zeros, as datasets write unknown values, and only dataset values are used
+// to impute others.
+func imputeDataset(config misc.DataConfig, data interface{}) (map[string]int, error) {
+	counts := make(map[string]int)
+	imputations, err := config.Imputations()
+	if err != nil {
+		return nil, err
+	}
+	if !config.DataImputeDurations && len(imputations) == 0 {
+		return counts, nil
+	}
+
+	slice := reflect.ValueOf(data).Elem()
+	fields := numericFields(slice.Type().Elem())
+	for _, imputation := range imputations {
+		for _, field := range append([]string{imputation.Field}, imputation.Predictors...) {
+			if _, ok := fields[field]; !ok {
+				return nil, fmt.Errorf("unknown numeric field %q in DataImpute", field)
+			}
+		}
+	}
+
+	if config.DataImputeDurations {
+		counts["duration"] = imputeDurations(slice, fields["duration"])
+	}
+	for _, imputation := range imputations {
+		counts[imputation.Field] = imputeField(slice, fields, imputation)
+	}
+	return counts, nil
+}
+
+// numericFields returns indexes of numeric fields of location structure by JSON name.
+func numericFields(location reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < location.NumField(); i++ {
+		field := location.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if kind := field.Type.Kind(); name != "" && (kind == reflect.Float64 || kind == reflect.Int) {
+			fields[name] = i
+		}
+	}
+	return fields
+}
+
+// imputeDurations sets zero durations to median duration of the first category of location which
+// has known durations, or of its nearest ancestor in taxonomy, or to median of all known durations.
+func imputeDurations(slice reflect.Value, index int) int {
+	byCategory := make(map[string][]float64)
+	all := make([]float64, 0)
+	for i := 0; i < slice.Len(); i++ {
+		location := slice.Index(i)
+		if duration := numberOf(location.Field(index)); duration > 0 {
+			all = append(all, duration)
+			for _, category := range location.Addr().Interface().(generic.Point).CategoryNames() {
+				for _, level := range append([]string{category}, taxonomy.Ancestors(category)...) {
+					byCategory[level] = append(byCategory[level], duration)
+				}
+			}
+		}
+	}
+	if len(all) == 0 {
+		return 0
+	}
+
+	count := 0
+	for i := 0; i < slice.Len(); i++ {
+		location := slice.Index(i)
+		if numberOf(location.Field(index)) != 0 {
+			continue
+		}
+		durations := all
+	categories:
+		for _, category := range location.Addr().Interface().(generic.Point).CategoryNames() {
+			for _, level := range append([]string{category}, taxonomy.Ancestors(category)...) {
+				if known, ok := byCategory[level]; ok {
+					durations = known
+					break categories
+				}
+			}
+		}
+		setNumber(location.Field(index), median(durations))
+		markImputed(location, "duration")
+		count++
+	}
+	return count
+}
+
+// imputeField sets zero values of field by the first predictor with a non-zero value. Every predictor
+// is fitted as log(1 + value) = a + b log(1 + predictor) on locations with both values, as popularity
+// metrics of different sources grow together by orders of magnitude. Predictors with negative slope
+// are not used and predictions are limited by fitted values of the field.
+func imputeField(slice reflect.Value, fields map[string]int, imputation misc.Imputation) int {
+	// fit predicts values in range of values it was fitted on
+	type fit struct {
+		a, b   float64
+		lo, hi float64
+		ok     bool
+	}
+	target := fields[imputation.Field]
+	fits := make([]fit, len(imputation.Predictors))
+	for p, predictor := range imputation.Predictors {
+		xs := make([]float64, 0)
+		ys := make([]float64, 0)
+		for i := 0; i < slice.Len(); i++ {
+			location := slice.Index(i)
+			if isImputed(location, imputation.Field) || isImputed(location, predictor) {
+				continue
+			}
+			x, y := numberOf(location.Field(fields[predictor])), numberOf(location.Field(target))
+			if x > 0 && y > 0 {
+				xs = append(xs, math.Log1p(x))
+				ys = append(ys, math.Log1p(y))
+			}
+		}
+		fits[p].a, fits[p].b, fits[p].ok = linearFit(xs, ys)
+		// predictor which is not growing with the field is not correlated with it
+		fits[p].ok = fits[p].ok && fits[p].b > 0
+		if fits[p].ok {
+			fits[p].lo, fits[p].hi = ys[0], ys[0]
+			for _, y := range ys {
+				fits[p].lo = math.Min(fits[p].lo, y)
+				fits[p].hi = math.Max(fits[p].hi, y)
+			}
+		}
+	}
+
+	count := 0
+	for i := 0; i < slice.Len(); i++ {
+		location := slice.Index(i)
+		if numberOf(location.Field(target)) != 0 {
+			continue
+		}
+		for p, predictor := range imputation.Predictors {
+			x := numberOf(location.Field(fields[predictor]))
+			if !fits[p].ok || x <= 0 || isImputed(location, predictor) {
+				continue
+			}
+			y := fits[p].a + fits[p].b*math.Log1p(x)
+			setNumber(location.Field(target), math.Expm1(math.Max(fits[p].lo, math.Min(fits[p].hi, y))))
+			markImputed(location, imputation.Field)
+			count++
+			break
+		}
+	}
+	return count
+}
+
+// linearFit returns least squares line y = a + b x, ok is false if there are too few distinct x.
+func linearFit(xs []float64, ys []float64) (a float64, b float64, ok bool) {
+	if len(xs) < minFitLocations {
+		return 0, 0, false
+	}
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	variance := n*sxx - sx*sx
+	if variance <= 1e-9*n*sxx {
+		return 0, 0, false
+	}
+	b = (n*sxy - sx*sy) / variance
+	a = (sy - b*sx) / n
+	return a, b, true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func numberOf(field reflect.Value) float64 {
+	if field.Kind() == reflect.Int {
+		return float64(field.Int())
+	}
+	return field.Float()
+}
+
+func setNumber(field reflect.Value, value float64) {
+	if field.Kind() == reflect.Int {
+		field.SetInt(int64(math.Round(value)))
+		return
+	}
+	field.SetFloat(value)
+}
+
+func isImputed(location reflect.Value, field string) bool {
+	for _, imputed := range imputedFields(location).Interface().([]string) {
+		if imputed == field {
+			return true
+		}
+	}
+	return false
+}
+
+func markImputed(location reflect.Value, field string) {
+	imputed := imputedFields(location)
+	imputed.Set(reflect.Append(imputed, reflect.ValueOf(field)))
+}
+
+// imputedFields returns Imputed field of location structure.
+func imputedFields(location reflect.Value) reflect.Value {
+	return location.FieldByName("Imputed")
+}
@@ -0,0 +1,138 @@
+package points
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/misc"
+)
+
+func TestImputeDurations(t *testing.T) {
+	locations := []BaseLocation{
+		{Category: []string{"culture/museums"}, Duration: 60},
+		{Category: []string{"culture/museums"}, Duration: 120},
+		{Category: []string{"culture/galleries"}, Duration: 30},
+		{Category: []string{"food/cafes"}, Duration: 20},
+		{Category: []string{"culture/museums"}},
+		{Category: []string{"culture/theatres"}},
+		{Category: []string{"nature/parks", "culture/galleries"}},
+		{Category: []string{"nature/parks"}},
+		{},
+	}
+	tests := []struct {
+		position int
+		duration int
+		imputed  []string
+	}{
+		{0, 60, nil},
+		{3, 20, nil},
+		// median of the category
+		{4, 90, []string{"duration"}},
+		// median of the ancestor "culture"
+		{5, 60, []string{"duration"}},
+		// the first category with durations
+		{6, 30, []string{"duration"}},
+		// median of all durations
+		{7, 45, []string{"duration"}},
+		{8, 45, []string{"duration"}},
+	}
+
+	counts, err := imputeDataset(misc.DataConfig{DataImputeDurations: true}, &locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["duration"] != 5 {
+		t.Errorf("imputed %d durations, want 5", counts["duration"])
+	}
+	for _, test := range tests {
+		location := locations[test.position]
+		if location.Duration != test.duration || !reflect.DeepEqual(location.Imputed, test.imputed) {
+			t.Errorf("location %d: duration %d imputed %v, want %d %v",
+				test.position, location.Duration, location.Imputed, test.duration, test.imputed)
+		}
+	}
+}
+
+func TestImputeFields(t *testing.T) {
+	// fitted locations have equal values of all fields, so predictions equal predictors within the range
+	fitted := []BaseLocation{
+		{FoursquareCheckinsCount: 10, FoursquareUserCount: 10, InstagramVisitorsNumber: 10},
+		{FoursquareCheckinsCount: 100, FoursquareUserCount: 100, InstagramVisitorsNumber: 100},
+		{FoursquareCheckinsCount: 1000, FoursquareUserCount: 1000, InstagramVisitorsNumber: 1000},
+	}
+	tests := []struct {
+		name     string
+		location BaseLocation
+		want     float64
+		count    int
+		imputed  []string
+	}{
+		{"known", BaseLocation{FoursquareCheckinsCount: 50, InstagramVisitorsNumber: 7}, 7, 0, nil},
+		{"first predictor", BaseLocation{FoursquareCheckinsCount: 300, FoursquareUserCount: 20}, 300, 1,
+			[]string{"instagram_visitorsNumber"}},
+		{"second predictor", BaseLocation{FoursquareUserCount: 20}, 20, 1, []string{"instagram_visitorsNumber"}},
+		{"above range", BaseLocation{FoursquareCheckinsCount: 1e6}, 1000, 1, []string{"instagram_visitorsNumber"}},
+		{"below range", BaseLocation{FoursquareCheckinsCount: 1}, 10, 1, []string{"instagram_visitorsNumber"}},
+		{"no predictors", BaseLocation{}, 0, 0, nil},
+		{"imputed predictor", BaseLocation{FoursquareUserCount: 20, Imputed: []string{"foursquare_userCount"}}, 0, 0,
+			[]string{"foursquare_userCount"}},
+	}
+	config := misc.DataConfig{DataImpute: "instagram_visitorsNumber=foursquare_checkinsCount|foursquare_userCount"}
+	for _, test := range tests {
+		locations := append(append([]BaseLocation{}, fitted...), test.location)
+		counts, err := imputeDataset(config, &locations)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		got := locations[len(fitted)]
+		if math.Abs(got.InstagramVisitorsNumber-test.want) > 1e-6*math.Max(1, test.want) ||
+			!reflect.DeepEqual(got.Imputed, test.imputed) {
+			t.Errorf("%s: got %v imputed %v, want %v %v",
+				test.name, got.InstagramVisitorsNumber, got.Imputed, test.want, test.imputed)
+		}
+		if counts["instagram_visitorsNumber"] != test.count {
+			t.Errorf("%s: imputed %d values, want %d", test.name, counts["instagram_visitorsNumber"], test.count)
+		}
+	}
+}
+
+// TestImputeNegativeSlope checks that fields are not predicted by fields which fall when they grow.
+func TestImputeNegativeSlope(t *testing.T) {
+	locations := []BaseLocation{
+		{FoursquareRating: 5, TripAdvisorReviewsNumber: 10},
+		{FoursquareRating: 7, TripAdvisorReviewsNumber: 100},
+		{FoursquareRating: 9, TripAdvisorReviewsNumber: 10},
+		{FoursquareRating: 9.5, TripAdvisorReviewsNumber: 5},
+		{FoursquareRating: 8},
+	}
+	counts, err := imputeDataset(misc.DataConfig{DataImpute: "tripAdvisor_reviewsNumber=foursquare_rating"}, &locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["tripAdvisor_reviewsNumber"] != 0 || locations[4].TripAdvisorReviewsNumber != 0 {
+		t.Errorf("imputed %d values, last value %v, want none",
+			counts["tripAdvisor_reviewsNumber"], locations[4].TripAdvisorReviewsNumber)
+	}
+}
+
+func TestImputeErrors(t *testing.T) {
+	tests := []struct {
+		impute string
+		err    string
+	}{
+		{"instagram_visitorsNumber=likes", `unknown numeric field "likes"`},
+		{"title=foursquare_rating", `unknown numeric field "title"`},
+		{"instagram_visitorsNumber", "field=predictor|predictor expected"},
+		{"instagram_visitorsNumber=instagram_visitorsNumber", "field=predictor|predictor expected"},
+	}
+	for _, test := range tests {
+		locations := []BaseLocation{{}}
+		_, err := imputeDataset(misc.DataConfig{DataImpute: test.impute}, &locations)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("DataImpute %q: error = %v, want %q", test.impute, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package points
+
+import (
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// borderTolerance widens index queries, so that locations on the border are not lost because of rounding errors,
+// exact distances are checked after the query.
+const borderTolerance = 1e-9
+
+// pointsInArea returns locations which are not farther from start and from end than distance between them.
+func pointsInArea(index *spatial.KDTree, location func(idx int) generic.Point, start generic.Point, end generic.Point) map[int]generic.Point {
+	distance := EuclidianDistance(start, end)
+	x, y := start.Coordinates()
+
+	currentLocations := make(map[int]generic.Point)
+	for _, idx := range index.Radius(x, y, distance*(1+borderTolerance)) {
+		l := location(idx)
+		if EuclidianDistance(l, start) <= distance && EuclidianDistance(l, end) <= distance {
+			currentLocations[idx] = l
+		}
+	}
+	return currentLocations
+}
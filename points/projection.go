@@ -0,0 +1,58 @@
+package points
+
+import (
+	"fmt"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+// projectable locations have geographic and projected coordinates.
+type projectable interface {
+	LatLng() (float64, float64)
+	Coordinates() (float64, float64)
+	setCoordinates(x float64, y float64)
+	setLatLng(lat float64, lng float64)
+}
+
+// projectLocations fills missing x and y of locations from lat and lng and returns projection of the dataset.
+// If no location has x and y, dataset is projected with local projection, which keeps true distances.
+// Otherwise missing coordinates are projected with Web Mercator like coordinates of the published datasets,
+// so that all distances of a dataset are measured in one projection. Locations with x and y but without
+// lat and lng get lat and lng of their Web Mercator coordinates.
+//
+// If local is true, all locations are projected with local projection, published x and y are replaced.
+func projectLocations(locations []projectable, local bool) (geo.Projection, error) {
+	missing := make([]int, 0)
+	lats := make([]float64, len(locations))
+	lngs := make([]float64, len(locations))
+	for i, location := range locations {
+		lats[i], lngs[i] = location.LatLng()
+		x, y := location.Coordinates()
+		switch {
+		case x == 0 && y == 0 && lats[i] == 0 && lngs[i] == 0:
+			return nil, fmt.Errorf("location %d has neither x and y nor lat and lng", i)
+		case x == 0 && y == 0:
+			missing = append(missing, i)
+		case lats[i] == 0 && lngs[i] == 0:
+			lats[i], lngs[i] = geo.WebMercator{}.Unproject(x, y)
+			location.setLatLng(lats[i], lngs[i])
+		}
+	}
+
+	if local {
+		projection := geo.LocalFor(lats, lngs)
+		for i, location := range locations {
+			location.setCoordinates(projection.Project(lats[i], lngs[i]))
+		}
+		return projection, nil
+	}
+
+	var projection geo.Projection = geo.WebMercator{}
+	if len(missing) == len(locations) {
+		projection = geo.LocalFor(lats, lngs)
+	}
+	for _, i := range missing {
+		locations[i].setCoordinates(projection.Project(lats[i], lngs[i]))
+	}
+	return projection, nil
+}
@@ -0,0 +1,95 @@
+package points
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+var projectionTests = []struct {
+	name     string
+	location func(lat float64, lng float64) *BaseLocation
+}{
+	{"lat and lng", func(lat float64, lng float64) *BaseLocation {
+		return &BaseLocation{Lat: lat, Lng: lng}
+	}},
+	{"published x and y with lat and lng", func(lat float64, lng float64) *BaseLocation {
+		x, y := geo.WebMercator{}.Project(lat, lng)
+		return &BaseLocation{Lat: lat, Lng: lng, X: x, Y: y}
+	}},
+	{"x and y without lat and lng", func(lat float64, lng float64) *BaseLocation {
+		x, y := geo.WebMercator{}.Project(lat, lng)
+		return &BaseLocation{X: x, Y: y}
+	}},
+}
+
+var projectionCoordinates = [][2]float64{{59.9390, 30.3158}, {59.9343, 30.3061}, {59.9500, 30.3167}}
+
+func TestProjectLocations(t *testing.T) {
+	mercator := geo.WebMercator{}
+	for _, test := range projectionTests {
+		locations := make([]projectable, len(projectionCoordinates))
+		for i, c := range projectionCoordinates {
+			locations[i] = test.location(c[0], c[1])
+		}
+		projection, err := projectLocations(locations, false)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		_, local := projection.(geo.Local)
+		if wantLocal := test.name == "lat and lng"; local != wantLocal {
+			t.Errorf("%s: projection %#v", test.name, projection)
+		}
+		for i, c := range projectionCoordinates {
+			location := locations[i].(*BaseLocation)
+			// published coordinates are kept as they are
+			if x, y := mercator.Project(c[0], c[1]); !local && (location.X != x || location.Y != y) {
+				t.Errorf("%s: location %d has x and y %v, %v, want %v, %v", test.name, i, location.X, location.Y, x, y)
+			}
+			if math.Abs(location.Lat-c[0]) > 1e-9 || math.Abs(location.Lng-c[1]) > 1e-9 {
+				t.Errorf("%s: location %d has lat and lng %v, %v, want %v", test.name, i, location.Lat, location.Lng, c)
+			}
+		}
+	}
+
+	// missing coordinates are projected like the published ones
+	x, y := mercator.Project(59.93, 30.31)
+	mixed := []projectable{&BaseLocation{Lat: 59.93, Lng: 30.31, X: x, Y: y}, &BaseLocation{Lat: 59.94, Lng: 30.32}}
+	if _, err := projectLocations(mixed, false); err != nil {
+		t.Fatal(err)
+	}
+	if x, y := mercator.Project(59.94, 30.32); mixed[1].(*BaseLocation).X != x || mixed[1].(*BaseLocation).Y != y {
+		t.Errorf("missing x and y are not projected with Web Mercator")
+	}
+
+	for _, local := range []bool{false, true} {
+		locations := []projectable{&BaseLocation{Lat: 59.93, Lng: 30.31}, &BaseLocation{}}
+		if _, err := projectLocations(locations, local); err == nil {
+			t.Errorf("location without coordinates is accepted")
+		}
+	}
+}
+
+func TestProjectLocationsLocal(t *testing.T) {
+	for _, test := range projectionTests {
+		locations := make([]projectable, len(projectionCoordinates))
+		for i, c := range projectionCoordinates {
+			locations[i] = test.location(c[0], c[1])
+		}
+		if _, err := projectLocations(locations, true); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		// all sources of the same locations have the same true distances
+		for i := range projectionCoordinates {
+			for j := i + 1; j < len(projectionCoordinates); j++ {
+				a, b := projectionCoordinates[i], projectionCoordinates[j]
+				want := geo.Haversine(a[0], a[1], b[0], b[1])
+				got := EuclidianDistance(locations[i].(*BaseLocation), locations[j].(*BaseLocation))
+				if math.Abs(got-want) > 0.01*want {
+					t.Errorf("%s: distance between %d and %d is %.0f meters, want %.0f", test.name, i, j, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package points
+
+import (
+	"github.com/mukhinaks/fops/generic"
+)
+
+func init() {
+	generic.RegisterPoints("base", func(task generic.Task) (generic.Points, error) {
+		return BaseLocations{}, nil
+	})
+	generic.RegisterPoints("citybrand", func(task generic.Task) (generic.Points, error) {
+		return CityBrandLocations{}, nil
+	})
+}
@@ -0,0 +1,41 @@
+package points
+
+import (
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// readTaxonomy reads taxonomy of DataTaxonomy, it is nil if categories of dataset are kept as they are.
+func readTaxonomy(config misc.DataConfig) (*taxonomy.Taxonomy, error) {
+	if config.DataTaxonomy == "" {
+		return nil, nil
+	}
+	return taxonomy.Read(config.DataTaxonomy)
+}
+
+// categorized locations have categories of dataset sources, which are normalized while dataset is loaded.
+type categorized interface {
+	// normalizeCategories replaces categories by canonical categories of taxonomy and returns
+	// categories which are not in the taxonomy.
+	normalizeCategories(t *taxonomy.Taxonomy) []string
+}
+
+func (l *BaseLocation) normalizeCategories(t *taxonomy.Taxonomy) []string {
+	// normalized datasets, e.g. cleaned by validate-data, keep the first source categories
+	if len(l.SourceCategories) == 0 {
+		l.SourceCategories = l.Category
+	}
+	var unmapped []string
+	l.Category, unmapped = t.Normalize(l.Category)
+	return unmapped
+}
+
+func (l *CityBrandLocation) normalizeCategories(t *taxonomy.Taxonomy) []string {
+	if len(l.SourceCategories) == 0 {
+		l.SourceCategories = append(append([]string{}, l.Categories...), l.AdditionalCategories...)
+	}
+	var unmapped, additional []string
+	l.Categories, unmapped = t.Normalize(l.Categories)
+	l.AdditionalCategories, additional = t.Normalize(l.AdditionalCategories)
+	return append(unmapped, additional...)
+}
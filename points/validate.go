@@ -0,0 +1,271 @@
+package points
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+)
+
+// Severity of dataset issues.
+const (
+	// Error means that location can not be used, it is dropped from cleaned dataset.
+	Error = "error"
+	// Warning means that location is kept, invalid open hours are removed from cleaned dataset.
+	Warning = "warning"
+)
+
+// Issue is a problem of dataset location.
+type Issue struct {
+	// Location is a position in dataset after load-time filters.
+	Location int
+	Title    string
+	Field    string
+	Severity string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: location %d %q: %s: %s", i.Severity, i.Location, i.Title, i.Field, i.Message)
+}
+
+// Schema describes fields which problem needs in addition to coordinates, title and duration.
+type Schema struct {
+	// OpenHours requires open hours on DayOfWeek or on any day if DayOfWeek is empty.
+	OpenHours bool
+	DayOfWeek string
+	// Categories requires non-empty categories.
+	Categories bool
+}
+
+// Report is a result of dataset validation.
+type Report struct {
+	Locations int
+	Issues    []Issue
+	// Imputed are numbers of imputed values by field, see DataImpute.
+	Imputed map[string]int
+	// Unmapped are numbers of categories which are not in taxonomy by category, see DataTaxonomy.
+	Unmapped map[string]int
+	// cleaned is a slice of dataset locations without errors and duplicates.
+	cleaned reflect.Value
+}
+
+// Count returns number of issues of severity.
+func (r *Report) Count(severity string) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Cleaned returns number of locations of cleaned dataset.
+func (r *Report) Cleaned() int {
+	return r.cleaned.Len()
+}
+
+// WriteCleaned writes cleaned dataset as JSON array in schema of the dataset.
+func (r *Report) WriteCleaned(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.cleaned.Interface())
+}
+
+// record gives validation access to raw fields of dataset locations.
+type record interface {
+	generic.Point
+	openHoursTable() map[string][]int
+	externalID() generic.LocationID
+}
+
+func (l BaseLocation) openHoursTable() map[string][]int {
+	return l.OpenHours
+}
+
+func (l BaseLocation) externalID() generic.LocationID {
+	return l.ID
+}
+
+func (l CityBrandLocation) openHoursTable() map[string][]int {
+	return l.OpenHours
+}
+
+func (l CityBrandLocation) externalID() generic.LocationID {
+	return l.ID
+}
+
+// ValidateDataset reads dataset of configuration with load-time filters and checks locations against
+// schema, city brand datasets are read as CityBrandLocation. Missing values are imputed as in Init before
+// the check. Unlike Init it does not stop at the first location without coordinates, so all problems
+// are reported.
+func ValidateDataset(config misc.DataConfig, cityBrand bool, schema Schema) (*Report, error) {
+	var data interface{}
+	if cityBrand {
+		data = &[]CityBrandLocation{}
+	} else {
+		data = &[]BaseLocation{}
+	}
+	categories, err := readTaxonomy(config)
+	if err != nil {
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	unmapped, err := readDataset(config, categories, data)
+	if err != nil {
+		return nil, fmt.Errorf("points: %v", err)
+	}
+	imputed, err := imputeDataset(config, data)
+	if err != nil {
+		return nil, fmt.Errorf("points: %s: %v", config.DataPath, err)
+	}
+
+	slice := reflect.ValueOf(data).Elem()
+	report := &Report{Locations: slice.Len(), Issues: make([]Issue, 0), Imputed: imputed, Unmapped: unmapped}
+	cleaned := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	titles := make(map[string]int)
+	ids := make(map[generic.LocationID]int)
+	for i := 0; i < slice.Len(); i++ {
+		location := slice.Index(i).Addr().Interface().(record)
+		issues := validateLocation(location, schema)
+		for k := range issues {
+			issues[k].Location = i
+		}
+
+		// locations without ID are identified by position, see identify
+		id := location.externalID()
+		if id == "" {
+			id = generic.PositionID(i)
+		}
+		if first, ok := ids[id]; ok {
+			issues = append(issues, Issue{Location: i, Title: location.Name(), Field: "id", Severity: Error,
+				Message: fmt.Sprintf("same id %q as location %d", string(id), first)})
+		} else {
+			ids[id] = i
+		}
+
+		duplicate := false
+		if first, ok := titles[location.Name()]; ok && location.Name() != "" {
+			issue := Issue{Location: i, Title: location.Name(), Field: "title", Severity: Warning}
+			if sameCoordinates(location, slice.Index(first).Addr().Interface().(record)) {
+				duplicate = true
+				issue.Message = fmt.Sprintf("duplicate of location %d, removed from cleaned dataset", first)
+			} else {
+				issue.Message = fmt.Sprintf("same title as location %d", first)
+			}
+			issues = append(issues, issue)
+		} else {
+			titles[location.Name()] = i
+		}
+
+		report.Issues = append(report.Issues, issues...)
+		if !duplicate && !hasErrors(issues) {
+			cleaned = reflect.Append(cleaned, slice.Index(i))
+		}
+	}
+	report.cleaned = cleaned
+	return report, nil
+}
+
+// validateLocation checks one location, invalid open hours are removed from the location.
+func validateLocation(location record, schema Schema) []Issue {
+	issues := make([]Issue, 0)
+	add := func(field string, severity string, format string, args ...interface{}) {
+		issues = append(issues, Issue{
+			Title:    location.Name(),
+			Field:    field,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if location.Name() == "" {
+		add("title", Warning, "empty title")
+	}
+
+	x, y := location.Coordinates()
+	lat, lng := location.LatLng()
+	switch {
+	case x == 0 && y == 0 && lat == 0 && lng == 0:
+		add("lat", Error, "neither x and y nor lat and lng")
+	case math.Abs(lat) > 90:
+		add("lat", Error, "latitude %v is out of [-90, 90]", lat)
+	case math.Abs(lng) > 180:
+		add("lng", Error, "longitude %v is out of [-180, 180]", lng)
+	}
+
+	if duration := location.VisitDuration(); duration < 0 {
+		add("duration", Error, "negative duration %d", duration)
+	} else if duration == 0 {
+		add("duration", Warning, "zero duration")
+	}
+
+	hours := location.openHoursTable()
+	days := make([]string, 0, len(hours))
+	for day := range hours {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	for _, day := range days {
+		if message := checkOpenHours(day, hours[day]); message != "" {
+			add("open_hours", Warning, "day %s: %s, removed from cleaned dataset", day, message)
+			delete(hours, day)
+		}
+	}
+	if schema.OpenHours {
+		if schema.DayOfWeek != "" {
+			if _, ok := hours[schema.DayOfWeek]; !ok {
+				add("open_hours", Warning, "no open hours on day %s, location is closed", schema.DayOfWeek)
+			}
+		} else if len(hours) == 0 {
+			add("open_hours", Warning, "no open hours, location is closed on all days")
+		}
+	}
+
+	if schema.Categories && len(location.CategoryNames()) == 0 {
+		add("categories", Warning, "no categories")
+	}
+	return issues
+}
+
+// checkOpenHours returns problem of open hours of the day or empty string.
+func checkOpenHours(day string, interval []int) string {
+	if d, err := strconv.Atoi(day); err != nil || d < 0 || d > 6 {
+		return "day of week should be from 0 (Sunday) to 6"
+	}
+	if len(interval) != 2 {
+		return fmt.Sprintf("expected [open, close], got %v", interval)
+	}
+	for _, t := range interval {
+		if t < 0 || t > 2400 || t%100 >= 60 {
+			return fmt.Sprintf("%d is not a time in HHMM format", t)
+		}
+	}
+	if interval[0] >= interval[1] {
+		return fmt.Sprintf("open time %d is not before close time %d", interval[0], interval[1])
+	}
+	return ""
+}
+
+func sameCoordinates(a generic.Point, b generic.Point) bool {
+	ax, ay := a.Coordinates()
+	bx, by := b.Coordinates()
+	alat, alng := a.LatLng()
+	blat, blng := b.LatLng()
+	return ax == bx && ay == by && alat == blat && alng == blng
+}
+
+func hasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package points
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mukhinaks/fops/misc"
+)
+
+// validationDataset has one problem or valid location per line.
+const validationDataset = `[
+	{"id": "a", "title": "Museum", "lat": 55.7, "lng": 37.6, "duration": 60, "open_hours": {"1": [900, 1800]}},
+	{"id": "a", "title": "Park", "lat": 55.8, "lng": 37.5, "duration": 30},
+	{"id": "b", "title": "Nowhere", "duration": 30},
+	{"id": "c", "title": "North", "lat": 95, "lng": 37, "duration": 30},
+	{"id": "d", "title": "East", "lat": 55, "lng": 190, "duration": 30},
+	{"id": "e", "title": "Cafe", "lat": 55.7, "lng": 37.61, "duration": -5},
+	{"id": "f", "title": "Shop", "lat": 55.7, "lng": 37.62, "open_hours": {"1": [900], "2": [1800, 900],
+		"3": [960, 1800], "4": [900, 2500], "5": [1000, 1900], "7": [900, 1800]}},
+	{"id": "g", "title": "Museum", "lat": 55.7, "lng": 37.6, "duration": 60},
+	{"id": "h", "title": "Park", "lat": 55.9, "lng": 37.5, "duration": 30},
+	{"title": "", "lat": 1, "lng": 1, "duration": 10}
+]`
+
+func validate(t *testing.T, text string, schema Schema) *Report {
+	dir, err := ioutil.TempDir("", "fops-points")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := misc.DataConfig{DataPath: filepath.Join(dir, "city.json")}
+	if err := ioutil.WriteFile(config.DataPath, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := ValidateDataset(config, false, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestValidateDataset(t *testing.T) {
+	report := validate(t, validationDataset, Schema{})
+	want := []Issue{
+		{1, "Park", "id", Error, `same id "a" as location 0`},
+		{2, "Nowhere", "lat", Error, "neither x and y nor lat and lng"},
+		{3, "North", "lat", Error, "latitude 95 is out of [-90, 90]"},
+		{4, "East", "lng", Error, "longitude 190 is out of [-180, 180]"},
+		{5, "Cafe", "duration", Error, "negative duration -5"},
+		{6, "Shop", "duration", Warning, "zero duration"},
+		{6, "Shop", "open_hours", Warning, "day 1: expected [open, close], got [900], removed from cleaned dataset"},
+		{6, "Shop", "open_hours", Warning,
+			"day 2: open time 1800 is not before close time 900, removed from cleaned dataset"},
+		{6, "Shop", "open_hours", Warning, "day 3: 960 is not a time in HHMM format, removed from cleaned dataset"},
+		{6, "Shop", "open_hours", Warning, "day 4: 2500 is not a time in HHMM format, removed from cleaned dataset"},
+		{6, "Shop", "open_hours", Warning,
+			"day 7: day of week should be from 0 (Sunday) to 6, removed from cleaned dataset"},
+		{7, "Museum", "title", Warning, "duplicate of location 0, removed from cleaned dataset"},
+		{8, "Park", "title", Warning, "same title as location 1"},
+		{9, "", "title", Warning, "empty title"},
+	}
+	if !reflect.DeepEqual(report.Issues, want) {
+		t.Errorf("issues:\n%v\nwant:\n%v", report.Issues, want)
+	}
+	if report.Locations != 10 || report.Count(Error) != 5 || report.Count(Warning) != 9 {
+		t.Errorf("%d locations, %d errors, %d warnings, want 10, 5, 9",
+			report.Locations, report.Count(Error), report.Count(Warning))
+	}
+
+	// locations with errors and duplicates are dropped, invalid open hours are removed
+	if report.Cleaned() != 4 {
+		t.Fatalf("cleaned dataset has %d locations, want 4", report.Cleaned())
+	}
+	var buffer bytes.Buffer
+	if err := report.WriteCleaned(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	var cleaned []BaseLocation
+	if err := json.Unmarshal(buffer.Bytes(), &cleaned); err != nil {
+		t.Fatal(err)
+	}
+	titles := make([]string, len(cleaned))
+	for i, location := range cleaned {
+		titles[i] = location.Title
+	}
+	if !reflect.DeepEqual(titles, []string{"Museum", "Shop", "Park", ""}) {
+		t.Errorf("cleaned dataset has %q", titles)
+	}
+	if hours := cleaned[1].OpenHours; !reflect.DeepEqual(hours, map[string][]int{"5": {1000, 1900}}) {
+		t.Errorf("cleaned open hours %v, want only day 5", hours)
+	}
+}
+
+func TestValidateDatasetSchema(t *testing.T) {
+	text := `[
+		{"title": "Museum", "lat": 55.7, "lng": 37.6, "duration": 60, "category": ["Museums"],
+			"open_hours": {"1": [900, 1800]}},
+		{"title": "Park", "lat": 55.8, "lng": 37.5, "duration": 30, "category": []},
+		{"title": "Cafe", "lat": 55.9, "lng": 37.5, "duration": 30, "open_hours": {"1": [900, 900]}}
+	]`
+	tests := []struct {
+		name   string
+		schema Schema
+		want   []Issue
+	}{
+		{"no requirements", Schema{}, []Issue{
+			{2, "Cafe", "open_hours", Warning,
+				"day 1: open time 900 is not before close time 900, removed from cleaned dataset"},
+		}},
+		{"open hours of day", Schema{OpenHours: true, DayOfWeek: "2"}, []Issue{
+			{0, "Museum", "open_hours", Warning, "no open hours on day 2, location is closed"},
+			{1, "Park", "open_hours", Warning, "no open hours on day 2, location is closed"},
+			{2, "Cafe", "open_hours", Warning,
+				"day 1: open time 900 is not before close time 900, removed from cleaned dataset"},
+			{2, "Cafe", "open_hours", Warning, "no open hours on day 2, location is closed"},
+		}},
+		{"open hours of any day", Schema{OpenHours: true}, []Issue{
+			{1, "Park", "open_hours", Warning, "no open hours, location is closed on all days"},
+			{2, "Cafe", "open_hours", Warning,
+				"day 1: open time 900 is not before close time 900, removed from cleaned dataset"},
+			{2, "Cafe", "open_hours", Warning, "no open hours, location is closed on all days"},
+		}},
+		{"categories", Schema{Categories: true}, []Issue{
+			{1, "Park", "categories", Warning, "no categories"},
+			{2, "Cafe", "open_hours", Warning,
+				"day 1: open time 900 is not before close time 900, removed from cleaned dataset"},
+			{2, "Cafe", "categories", Warning, "no categories"},
+		}},
+	}
+	for _, test := range tests {
+		report := validate(t, text, test.schema)
+		if !reflect.DeepEqual(report.Issues, test.want) {
+			t.Errorf("%s: issues:\n%v\nwant:\n%v", test.name, report.Issues, test.want)
+		}
+		// warnings do not remove locations
+		if report.Cleaned() != 3 {
+			t.Errorf("%s: cleaned dataset has %d locations, want 3", test.name, report.Cleaned())
+		}
+	}
+}
+
+func TestCheckOpenHours(t *testing.T) {
+	tests := []struct {
+		day      string
+		interval []int
+		valid    bool
+	}{
+		{"0", []int{0, 2400}, true},
+		{"6", []int{1030, 1759}, true},
+		{"-1", []int{900, 1800}, false},
+		{"Monday", []int{900, 1800}, false},
+		{"1", []int{}, false},
+		{"1", []int{900, 1200, 1300, 1800}, false},
+		{"1", []int{-100, 1800}, false},
+		{"1", []int{900, 1875}, false},
+		{"1", []int{1800, 1000}, false},
+	}
+	for _, test := range tests {
+		message := checkOpenHours(test.day, test.interval)
+		if (message == "") != test.valid {
+			t.Errorf("checkOpenHours(%q, %v) = %q, valid %v", test.day, test.interval, message, test.valid)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Package query is a filter expression language over attributes of locations, e.g.
+//
+//	category in ["Museums & Libraries"] and tripAdvisor_rating >= 4 and duration <= 120
+//
+// Comparisons are joined by and, or, not and parentheses. Fields are numeric attributes of locations
+// (generic.Point.Attribute), title and category (categories of location). Numbers are compared by
+// ==, !=, <, <=, >, >=, in and not in. Title is compared with strings by ==, !=, in and not in.
+// Category == "X" tells that location has category X or its subcategory, e.g. "culture" matches
+// canonical "culture/museums" of taxonomy, category in [...] tells that it has one of them.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// Expression is a parsed filter.
+type Expression struct {
+	text string
+	root node
+}
+
+// Parse parses filter expression.
+func Parse(text string) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("query: %q: %v", text, err)
+	}
+	p := parser{tokens: tokens}
+	root, err := p.or()
+	if err == nil && p.peek().kind != endToken {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query: %q: %v", text, err)
+	}
+	return &Expression{text: text, root: root}, nil
+}
+
+// MustParse is like Parse but panics if the expression is invalid, it is used for filters of package variables.
+func MustParse(text string) *Expression {
+	expression, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return expression
+}
+
+// Match tells if location satisfies the filter.
+func (e *Expression) Match(location generic.Point) bool {
+	return e.root.match(location)
+}
+
+func (e *Expression) String() string {
+	return e.text
+}
+
+type node interface {
+	match(location generic.Point) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(location generic.Point) bool {
+	return n.left.match(location) && n.right.match(location)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(location generic.Point) bool {
+	return n.left.match(location) || n.right.match(location)
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) match(location generic.Point) bool {
+	return !n.operand.match(location)
+}
+
+// Fields which are not numeric attributes.
+const (
+	titleField    = "title"
+	categoryField = "category"
+)
+
+// comparison compares field with numbers or strings, in and not in compare with every value of the list.
+type comparison struct {
+	field   string
+	op      string
+	numbers []float64
+	strings []string
+}
+
+func (c comparison) match(location generic.Point) bool {
+	switch c.field {
+	case titleField:
+		return c.compareText([]string{location.Name()}, equal)
+	case categoryField:
+		return c.compareText(location.CategoryNames(), taxonomy.Matches)
+	}
+	value := location.Attribute(c.field)
+	switch c.op {
+	case "==", "in":
+		return containsNumber(c.numbers, value)
+	case "!=", "not in":
+		return !containsNumber(c.numbers, value)
+	case "<":
+		return value < c.numbers[0]
+	case "<=":
+		return value <= c.numbers[0]
+	case ">":
+		return value > c.numbers[0]
+	case ">=":
+		return value >= c.numbers[0]
+	}
+	return false
+}
+
+// compareText tells if one of values matches one of strings, != and not in negate it.
+func (c comparison) compareText(values []string, matches func(value string, s string) bool) bool {
+	found := false
+	for _, value := range values {
+		for _, s := range c.strings {
+			found = found || matches(value, s)
+		}
+	}
+	if c.op == "!=" || c.op == "not in" {
+		return !found
+	}
+	return found
+}
+
+func equal(value string, s string) bool {
+	return value == s
+}
+
+func containsNumber(numbers []float64, value float64) bool {
+	for _, n := range numbers {
+		if n == value {
+			return true
+		}
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identToken
+	numberToken
+	stringToken
+	operatorToken
+	punctuationToken
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	number   float64
+	position int
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at %d", t.text, t.position)
+}
+
+// keyword tells if the token is a case insensitive keyword, e.g. and.
+func (t token) keyword(word string) bool {
+	return t.kind == identToken && strings.EqualFold(t.text, word)
+}
+
+func tokenize(text string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: identToken, text: string(runes[start:i]), position: start})
+		case unicode.IsDigit(r) || r == '.' || (r == '-' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			number, err := strconv.ParseFloat(string(runes[start:i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", string(runes[start:i]), start)
+			}
+			tokens = append(tokens, token{kind: numberToken, text: string(runes[start:i]), number: number, position: start})
+		case r == '"' || r == '\'':
+			var value strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: stringToken, text: value.String(), position: start})
+		case strings.ContainsRune("=!<>", r):
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "=" {
+				op = "=="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected \"!\" at %d, use not or !=", start)
+			}
+			tokens = append(tokens, token{kind: operatorToken, text: op, position: start})
+		case strings.ContainsRune("()[],", r):
+			i++
+			tokens = append(tokens, token{kind: punctuationToken, text: string(r), position: start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", string(r), start)
+		}
+	}
+	return append(tokens, token{kind: endToken, position: len(runes)}), nil
+}
+
+// parser is a recursive descent parser, not binds tighter than and, and binds tighter than or.
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != endToken {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("or") {
+		p.take()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("and") {
+		p.take()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.peek().keyword("not") {
+		p.take()
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if t := p.peek(); t.kind == punctuationToken && t.text == "(" {
+		p.take()
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.take(); t.kind != punctuationToken || t.text != ")" {
+			return nil, fmt.Errorf("expected \")\", got %s", t)
+		}
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	field := p.take()
+	if field.kind != identToken || field.keyword("and") || field.keyword("or") || field.keyword("in") {
+		return nil, fmt.Errorf("expected field, got %s", field)
+	}
+	c := comparison{field: field.text}
+	if strings.EqualFold(c.field, "categories") || strings.EqualFold(c.field, categoryField) {
+		c.field = categoryField
+	}
+	if strings.EqualFold(c.field, titleField) {
+		c.field = titleField
+	}
+	text := c.field == titleField || c.field == categoryField
+
+	op := p.take()
+	switch {
+	case op.kind == operatorToken:
+		c.op = op.text
+	case op.keyword("in"):
+		c.op = "in"
+	case op.keyword("not") && p.peek().keyword("in"):
+		p.take()
+		c.op = "not in"
+	default:
+		return nil, fmt.Errorf("expected comparison of %s, got %s", field.text, op)
+	}
+	if text && c.op != "==" && c.op != "!=" && c.op != "in" && c.op != "not in" {
+		return nil, fmt.Errorf("%s is compared by ==, !=, in and not in, got %s", field.text, op)
+	}
+
+	values := make([]token, 0)
+	if c.op == "in" || c.op == "not in" {
+		if t := p.take(); t.kind != punctuationToken || t.text != "[" {
+			return nil, fmt.Errorf("expected \"[\" after %s, got %s", c.op, t)
+		}
+		// empty list matches nothing
+		closed := p.peek().kind == punctuationToken && p.peek().text == "]"
+		if closed {
+			p.take()
+		}
+		for !closed {
+			values = append(values, p.take())
+			t := p.take()
+			closed = t.kind == punctuationToken && t.text == "]"
+			if !closed && (t.kind != punctuationToken || t.text != ",") {
+				return nil, fmt.Errorf("expected \",\" or \"]\", got %s", t)
+			}
+		}
+	} else {
+		values = append(values, p.take())
+	}
+
+	for _, value := range values {
+		switch {
+		case text && value.kind == stringToken:
+			c.strings = append(c.strings, value.text)
+		case !text && value.kind == numberToken:
+			c.numbers = append(c.numbers, value.number)
+		case text:
+			return nil, fmt.Errorf("%s is compared with strings, got %s", field.text, value)
+		default:
+			return nil, fmt.Errorf("%s is compared with numbers, got %s", field.text, value)
+		}
+	}
+	return c, nil
+}
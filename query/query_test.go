@@ -0,0 +1,113 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+// location has title, categories and numeric attributes, other methods of generic.Point are not used.
+type location struct {
+	generic.Point
+	title      string
+	categories []string
+	attributes map[string]float64
+}
+
+func (l location) Name() string {
+	return l.title
+}
+
+func (l location) CategoryNames() []string {
+	return l.categories
+}
+
+func (l location) Attribute(name string) float64 {
+	return l.attributes[name]
+}
+
+func TestMatch(t *testing.T) {
+	museum := location{
+		title:      "Hermitage",
+		categories: []string{"culture/museums", "sights/landmarks"},
+		attributes: map[string]float64{"duration": 120, "tripAdvisor_rating": 4.5},
+	}
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"duration == 120", true},
+		{"duration = 120", true},
+		{"duration != 120", false},
+		{"duration < 120", false},
+		{"duration <= 120", true},
+		{"duration > 60 and tripAdvisor_rating >= 4.5", true},
+		{"tripAdvisor_rating > 4.5", false},
+		{"duration in [60, 120]", true},
+		{"duration not in [60, 120]", false},
+		{"duration in []", false},
+		{"wikipedia_page == 0", true},
+		{"duration >= -1.5e1", true},
+		{`title == "Hermitage"`, true},
+		{`TITLE == 'Hermitage'`, true},
+		{`title in ["Kremlin", "Hermitage"]`, true},
+		{`title != "Hermitage"`, false},
+		{`category == "culture"`, true},
+		{`category == "culture/museums"`, true},
+		{`category == "cult"`, false},
+		{`category == "culture/museums/art"`, false},
+		{`categories in ["food", "sights"]`, true},
+		{`category not in ["food", "nature"]`, true},
+		{`category != "sights/landmarks"`, false},
+		{"not duration < 60", true},
+		{"not not duration < 60", false},
+		{"duration < 60 or tripAdvisor_rating > 4", true},
+		{"duration < 60 or duration > 100 and tripAdvisor_rating > 5", false},
+		{"(duration < 60 or duration > 100) and tripAdvisor_rating > 4", true},
+		{"duration < 60 OR NOT (duration > 100 AND tripAdvisor_rating > 5)", true},
+		{`title == "Hermitage \"State\""`, false},
+	}
+	for _, test := range tests {
+		expression, err := Parse(test.text)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", test.text, err)
+			continue
+		}
+		if got := expression.Match(museum); got != test.want {
+			t.Errorf("%q matches %v, want %v", test.text, got, test.want)
+		}
+		if expression.String() != test.text {
+			t.Errorf("String() = %q, want %q", expression.String(), test.text)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		err  string
+	}{
+		{"", "expected field, got end of expression"},
+		{"duration", "expected comparison of duration, got end of expression"},
+		{"duration ~ 1", `unexpected "~" at 9`},
+		{"duration ! 1", `unexpected "!" at 9, use not or !=`},
+		{"duration == 1.2.3", `invalid number "1.2.3" at 12`},
+		{`title == "Hermitage`, "unterminated string at 9"},
+		{"duration == 1 and", "expected field, got end of expression"},
+		{"and == 1", `expected field, got "and" at 0`},
+		{"(duration == 1", `expected ")", got end of expression`},
+		{"duration == 1)", `unexpected ")" at 13`},
+		{"duration in 1", `expected "[" after in, got "1" at 12`},
+		{"duration in [1 2]", `expected "," or "]", got "2" at 15`},
+		{`duration == "long"`, `duration is compared with numbers, got "long" at 12`},
+		{"title == 1", `title is compared with strings, got "1" at 9`},
+		{`category < "food"`, `category is compared by ==, !=, in and not in, got "<" at 9`},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.text)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Parse(%q) error = %v, want %q", test.text, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package roads
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadEdgeList reads graph in edge list format. Each line is one of:
+//
+//	node <id> <lat> <lng>
+//	edge <from id> <to id> [length]
+//
+// Empty lines and lines starting with # are skipped. Nodes should be declared before edges,
+// if length in meters is omitted, great-circle distance between nodes is used.
+func ReadEdgeList(r io.Reader) (*Graph, error) {
+	g := NewGraph()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if err := readEdgeListRecord(g, fields); err != nil {
+			return nil, fmt.Errorf("roads: line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("roads: %v", err)
+	}
+	return g, nil
+}
+
+func readEdgeListRecord(g *Graph, fields []string) error {
+	switch fields[0] {
+	case "node":
+		if len(fields) != 4 {
+			return fmt.Errorf("expected node <id> <lat> <lng>")
+		}
+		id, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid node id %q", fields[1])
+		}
+		lat, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || lat < -90 || lat > 90 {
+			return fmt.Errorf("invalid latitude %q", fields[2])
+		}
+		lng, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil || lng < -180 || lng > 180 {
+			return fmt.Errorf("invalid longitude %q", fields[3])
+		}
+		g.AddNode(id, lat, lng)
+	case "edge":
+		if len(fields) != 3 && len(fields) != 4 {
+			return fmt.Errorf("expected edge <from> <to> [length]")
+		}
+		from, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid node id %q", fields[1])
+		}
+		to, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid node id %q", fields[2])
+		}
+		length := 0.0
+		if len(fields) == 4 {
+			length, err = strconv.ParseFloat(fields[3], 64)
+			if err != nil || length < 0 {
+				return fmt.Errorf("invalid length %q", fields[3])
+			}
+		}
+		if err := g.addEdge(from, to, length); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown record %q", fields[0])
+	}
+	return nil
+}
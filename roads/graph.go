@@ -0,0 +1,132 @@
+// Package roads loads street networks from offline files and computes shortest path distances
+// between locations along the streets.
+package roads
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+// Graph is an undirected street network with nodes in geographic coordinates and edge lengths in meters.
+type Graph struct {
+	nodes []node
+	// index maps external node ID to position in nodes.
+	index map[int64]int
+	edges [][]edge
+}
+
+type node struct {
+	id       int64
+	lat, lng float64
+}
+
+type edge struct {
+	to     int
+	length float64
+}
+
+// NewGraph returns empty graph.
+func NewGraph() *Graph {
+	return &Graph{index: make(map[int64]int)}
+}
+
+// AddNode adds node with coordinates in degrees, coordinates are replaced if node already exists.
+func (g *Graph) AddNode(id int64, lat float64, lng float64) {
+	if i, ok := g.index[id]; ok {
+		g.nodes[i].lat, g.nodes[i].lng = lat, lng
+		return
+	}
+	g.index[id] = len(g.nodes)
+	g.nodes = append(g.nodes, node{id: id, lat: lat, lng: lng})
+	g.edges = append(g.edges, nil)
+}
+
+// AddEdge connects two existing nodes in both directions. If length in meters is not positive,
+// great-circle distance between nodes is used.
+func (g *Graph) AddEdge(from int64, to int64, length float64) error {
+	if err := g.addEdge(from, to, length); err != nil {
+		return fmt.Errorf("roads: %v", err)
+	}
+	return nil
+}
+
+func (g *Graph) addEdge(from int64, to int64, length float64) error {
+	i, ok := g.index[from]
+	if !ok {
+		return fmt.Errorf("unknown node %d", from)
+	}
+	j, ok := g.index[to]
+	if !ok {
+		return fmt.Errorf("unknown node %d", to)
+	}
+	if i == j {
+		return nil
+	}
+	if length <= 0 {
+		length = geo.Haversine(g.nodes[i].lat, g.nodes[i].lng, g.nodes[j].lat, g.nodes[j].lng)
+	}
+	g.edges[i] = append(g.edges[i], edge{j, length})
+	g.edges[j] = append(g.edges[j], edge{i, length})
+	return nil
+}
+
+// NodesNumber returns number of nodes.
+func (g *Graph) NodesNumber() int {
+	return len(g.nodes)
+}
+
+// EdgesNumber returns number of undirected edges.
+func (g *Graph) EdgesNumber() int {
+	number := 0
+	for _, edges := range g.edges {
+		number += len(edges)
+	}
+	return number / 2
+}
+
+// ShortestPath returns length of the shortest path between nodes in meters,
+// ok is false if nodes are unknown or not connected.
+func (g *Graph) ShortestPath(from int64, to int64) (length float64, ok bool) {
+	i, ok := g.index[from]
+	if !ok {
+		return 0, false
+	}
+	j, ok := g.index[to]
+	if !ok {
+		return 0, false
+	}
+	length = shortestPaths(g.edges, i, j, func(int, float64) {})
+	return length, !math.IsInf(length, 1)
+}
+
+// largestComponent returns nodes of the largest connected component,
+// locations are snapped only to them, so that any two locations are connected.
+func (g *Graph) largestComponent() []int {
+	component := make([]int, len(g.nodes))
+	for i := range component {
+		component[i] = -1
+	}
+
+	largest := make([]int, 0)
+	for start := range g.nodes {
+		if component[start] != -1 {
+			continue
+		}
+		members := []int{start}
+		component[start] = start
+		for k := 0; k < len(members); k++ {
+			for _, e := range g.edges[members[k]] {
+				if component[e.to] == -1 {
+					component[e.to] = start
+					members = append(members, e.to)
+				}
+			}
+		}
+		if len(members) > len(largest) {
+			largest = members
+		}
+	}
+	return largest
+}
@@ -0,0 +1,101 @@
+package roads
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testGraph has component 1-2-3-4 with the shortest way 1-2-3-4 and separate component 5-6.
+const testGraph = `# test network
+node 1 0 0
+node 2 0 0.001
+node 3 0 0.002
+node 4 0.001 0.002
+node 5 0.0005 0.0005
+node 6 0.0005 0.0006
+
+edge 1 2 10
+edge 2 3 10
+edge 1 3 25
+edge 3 4 5
+edge 1 4 30
+edge 5 6
+`
+
+func readTestGraph(t *testing.T) *Graph {
+	g, err := ReadEdgeList(strings.NewReader(testGraph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestShortestPath(t *testing.T) {
+	g := readTestGraph(t)
+	tests := []struct {
+		from, to int64
+		length   float64
+		ok       bool
+	}{
+		{1, 1, 0, true},
+		{1, 2, 10, true},
+		{1, 3, 20, true},
+		{3, 1, 20, true},
+		{1, 4, 25, true},
+		{2, 4, 15, true},
+		{5, 6, 11.1, true},
+		{1, 5, 0, false},
+		{1, 7, 0, false},
+		{7, 1, 0, false},
+	}
+	for _, test := range tests {
+		length, ok := g.ShortestPath(test.from, test.to)
+		if ok != test.ok || (ok && math.Abs(length-test.length) > 0.1) {
+			t.Errorf("ShortestPath(%d, %d) = %v, %v, want %v, %v", test.from, test.to, length, ok, test.length, test.ok)
+		}
+	}
+}
+
+func TestGraphSize(t *testing.T) {
+	g := readTestGraph(t)
+	if g.NodesNumber() != 6 || g.EdgesNumber() != 6 {
+		t.Errorf("graph has %d nodes and %d edges, want 6 and 6", g.NodesNumber(), g.EdgesNumber())
+	}
+	if err := g.AddEdge(1, 1, 5); err != nil || g.EdgesNumber() != 6 {
+		t.Errorf("loop is added: %v", err)
+	}
+	if err := g.AddEdge(1, 9, 5); err == nil || err.Error() != "roads: unknown node 9" {
+		t.Errorf("AddEdge of unknown node error %v", err)
+	}
+}
+
+func TestLargestComponent(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph string
+		want  []int64
+	}{
+		{"test graph", testGraph, []int64{1, 2, 3, 4}},
+		{"isolated nodes", "node 1 0 0\nnode 2 0 1\n", []int64{1}},
+		{"second component is larger", "node 1 0 0\nnode 2 0 1\nnode 3 1 0\nnode 4 1 1\nnode 5 2 2\n" +
+			"edge 1 2\nedge 3 4\nedge 4 5\n", []int64{3, 4, 5}},
+		{"empty", "", []int64{}},
+	}
+	for _, test := range tests {
+		g, err := ReadEdgeList(strings.NewReader(test.graph))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int64, 0)
+		for _, i := range g.largestComponent() {
+			ids = append(ids, g.nodes[i].id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%s: largestComponent = %v, want %v", test.name, ids, test.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package roads
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+// Load reads graph from file, files with .osm or .xml extension are read as OpenStreetMap XML,
+// other files as edge list.
+func Load(path string) (*Graph, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("roads: %v", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".osm", ".xml":
+		return ReadOSM(file)
+	default:
+		return ReadEdgeList(file)
+	}
+}
+
+var cache = struct {
+	sync.Mutex
+	graphs   map[string]*Graph
+	networks map[networkKey]*Network
+}{graphs: make(map[string]*Graph), networks: make(map[networkKey]*Network)}
+
+type networkKey struct {
+	path       string
+	projection geo.Projection
+}
+
+// LoadGraph returns graph of the file, graphs are loaded once per process.
+func LoadGraph(path string) (*Graph, error) {
+	cache.Lock()
+	defer cache.Unlock()
+	return loadGraph(path)
+}
+
+// loadGraph should be called with cache lock held.
+func loadGraph(path string) (*Graph, error) {
+	if g, ok := cache.graphs[path]; ok {
+		return g, nil
+	}
+	g, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	cache.graphs[path] = g
+	return g, nil
+}
+
+// LoadNetwork returns network of the graph file for locations in the projection. Networks are created once
+// and shared by all solvers of the process together with their distance cache.
+func LoadNetwork(path string, projection geo.Projection) (*Network, error) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	key := networkKey{path, projection}
+	if network, ok := cache.networks[key]; ok {
+		return network, nil
+	}
+	g, err := loadGraph(path)
+	if err != nil {
+		return nil, err
+	}
+	network, err := NewNetwork(g, projection)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, path)
+	}
+	cache.networks[key] = network
+	return network, nil
+}
@@ -0,0 +1,193 @@
+package roads
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+func TestReadEdgeListErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph string
+		err   string
+	}{
+		{"node fields", "node 1 0\n", "roads: line 1: expected node <id> <lat> <lng>"},
+		{"node id", "node a 0 0\n", `roads: line 1: invalid node id "a"`},
+		{"latitude", "# nodes\nnode 1 91 0\n", `roads: line 2: invalid latitude "91"`},
+		{"longitude", "node 1 0 x\n", `roads: line 1: invalid longitude "x"`},
+		{"edge fields", "edge 1\n", "roads: line 1: expected edge <from> <to> [length]"},
+		{"edge length", "node 1 0 0\nnode 2 0 1\nedge 1 2 -5\n", `roads: line 3: invalid length "-5"`},
+		{"edge before node", "node 1 0 0\nedge 1 2\nnode 2 0 1\n", "roads: line 2: unknown node 2"},
+		{"unknown record", "\nway 1 2\n", `roads: line 2: unknown record "way"`},
+	}
+	for _, test := range tests {
+		_, err := ReadEdgeList(strings.NewReader(test.graph))
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+const testOSM = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" lat="0" lon="0"/>
+  <node id="2" lat="0" lon="0.001"/>
+  <node id="3" lat="0" lon="0.002"/>
+  <node id="4" lat="0.001" lon="0.002"/>
+  <node id="5" lat="0.001" lon="0"/>
+  <node id="6" lat="0.002" lon="0"/>
+  <way id="10">
+    <nd ref="1"/><nd ref="2"/><nd ref="3"/>
+    <tag k="highway" v="footway"/>
+  </way>
+  <way id="11">
+    <nd ref="3"/><nd ref="4"/><nd ref="99"/><nd ref="5"/>
+    <tag k="highway" v="residential"/><tag k="oneway" v="yes"/>
+  </way>
+  <way id="12">
+    <nd ref="1"/><nd ref="5"/>
+    <tag k="highway" v="motorway"/>
+  </way>
+  <way id="13">
+    <nd ref="1"/><nd ref="6"/>
+    <tag k="highway" v="path"/><tag k="foot" v="no"/>
+  </way>
+  <way id="14">
+    <nd ref="5"/><nd ref="6"/>
+    <tag k="highway" v="service"/><tag k="access" v="private"/>
+  </way>
+  <way id="15">
+    <nd ref="2"/><nd ref="6"/>
+    <tag k="building" v="yes"/>
+  </way>
+  <way id="16">
+    <nd ref="2"/><nd ref="5"/>
+    <tag k="highway" v="service"/><tag k="access" v="private"/><tag k="foot" v="yes"/>
+  </way>
+</osm>
+`
+
+func TestReadOSM(t *testing.T) {
+	g, err := ReadOSM(strings.NewReader(testOSM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// node 6 is used only by excluded ways, node 99 is outside of the extract
+	if g.NodesNumber() != 5 || g.EdgesNumber() != 4 {
+		t.Errorf("graph has %d nodes and %d edges, want 5 and 4", g.NodesNumber(), g.EdgesNumber())
+	}
+	tests := []struct {
+		from, to int64
+		ok       bool
+	}{
+		{1, 3, true},
+		{3, 1, true},
+		{1, 4, true},
+		// way 11 is broken at missing node, but way 16 is walkable
+		{4, 5, true},
+		{1, 6, false},
+	}
+	for _, test := range tests {
+		if _, ok := g.ShortestPath(test.from, test.to); ok != test.ok {
+			t.Errorf("ShortestPath(%d, %d) found %v, want %v", test.from, test.to, ok, test.ok)
+		}
+	}
+	length, _ := g.ShortestPath(1, 3)
+	if want := geo.Haversine(0, 0, 0, 0.002); length < want-1e-6 || length > want+1e-6 {
+		t.Errorf("ShortestPath(1, 3) = %v, want %v", length, want)
+	}
+
+	if _, err := ReadOSM(strings.NewReader("<osm><node id=\"x\"/></osm>")); err == nil ||
+		!strings.HasPrefix(err.Error(), "roads: ") {
+		t.Errorf("invalid OSM error %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "roads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{"streets.osm": testOSM, "streets.XML": testOSM, "streets.txt": testGraph}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		nodes int
+	}{
+		{"streets.osm", 5},
+		{"streets.XML", 5},
+		{"streets.txt", 6},
+	}
+	for _, test := range tests {
+		g, err := Load(filepath.Join(dir, test.name))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if g.NodesNumber() != test.nodes {
+			t.Errorf("%s: %d nodes, want %d", test.name, g.NodesNumber(), test.nodes)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.osm")); err == nil || !strings.HasPrefix(err.Error(), "roads: ") {
+		t.Errorf("missing file error %v", err)
+	}
+}
+
+func TestLoadNetworkCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "roads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "streets.txt")
+	if err := ioutil.WriteFile(path, []byte(testGraph), 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.txt")
+	if err := ioutil.WriteFile(empty, []byte("node 1 0 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	graph, err := LoadGraph(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := LoadNetwork(path, geo.Local{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := LoadGraph(path); again != graph {
+		t.Errorf("graph is loaded twice")
+	}
+	if again, _ := LoadNetwork(path, geo.Local{}); again != local {
+		t.Errorf("network is created twice for the same projection")
+	}
+	mercator, err := LoadNetwork(path, geo.WebMercator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mercator == local || mercator.graph != graph {
+		t.Errorf("network of other projection should share only the graph")
+	}
+	if other, _ := LoadNetwork(path, geo.Local{CenterLat: 1}); other == local {
+		t.Errorf("network of other center is shared")
+	}
+
+	if _, err := LoadNetwork(empty, geo.Local{}); err == nil || err.Error() != ErrEmptyNetwork.Error()+": "+empty {
+		t.Errorf("empty network error %v", err)
+	}
+	if _, err := LoadNetwork(filepath.Join(dir, "missing.txt"), geo.Local{}); err == nil {
+		t.Errorf("missing network is loaded")
+	}
+}
@@ -0,0 +1,141 @@
+package roads
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// ErrEmptyNetwork is returned when street network has no edges.
+var ErrEmptyNetwork = errors.New("roads: street network is empty")
+
+// Network computes distances between locations along streets in units of dataset projection,
+// so that they are consistent with straight line distances between dataset coordinates.
+// Locations are snapped to the nearest node of the largest connected component of the graph,
+// distances between snapped nodes are cached. Network is safe for concurrent use.
+type Network struct {
+	graph *Graph
+	// x and y are projected coordinates of nodes.
+	x, y []float64
+	// edges are graph edges with lengths scaled to the projection.
+	edges [][]edge
+	// candidates index nodes of the largest connected component.
+	candidates *spatial.KDTree
+
+	mu sync.RWMutex
+	// snapped are nodes of locations keyed by location coordinates.
+	snapped map[[2]float64]snap
+	// targets are snapped nodes, distances are cached only between them.
+	targets   map[int]bool
+	distances map[[2]int]float64
+}
+
+type snap struct {
+	node     int
+	distance float64
+}
+
+// NewNetwork prepares graph for distance queries between locations with coordinates in the projection.
+func NewNetwork(g *Graph, projection geo.Projection) (*Network, error) {
+	if g.EdgesNumber() == 0 {
+		return nil, ErrEmptyNetwork
+	}
+	n := &Network{
+		graph:     g,
+		x:         make([]float64, len(g.nodes)),
+		y:         make([]float64, len(g.nodes)),
+		edges:     make([][]edge, len(g.nodes)),
+		snapped:   make(map[[2]float64]snap),
+		targets:   make(map[int]bool),
+		distances: make(map[[2]int]float64),
+	}
+	for i, node := range g.nodes {
+		n.x[i], n.y[i] = projection.Project(node.lat, node.lng)
+	}
+	component := g.largestComponent()
+	items := make([]spatial.Item, len(component))
+	for k, i := range component {
+		items[k] = spatial.Item{ID: i, X: n.x[i], Y: n.y[i]}
+	}
+	n.candidates = spatial.NewKDTree(items)
+	for i, edges := range g.edges {
+		n.edges[i] = make([]edge, len(edges))
+		for k, e := range edges {
+			lat := (g.nodes[i].lat + g.nodes[e.to].lat) / 2
+			lng := (g.nodes[i].lng + g.nodes[e.to].lng) / 2
+			n.edges[i][k] = edge{e.to, e.length * geo.Scale(projection, lat, lng)}
+		}
+	}
+	return n, nil
+}
+
+// Snap returns ID of the nearest network node to the projected location and distance to it.
+func (n *Network) Snap(x float64, y float64) (nodeID int64, distance float64) {
+	s := n.snap(x, y)
+	return n.graph.nodes[s.node].id, s.distance
+}
+
+func (n *Network) snap(x float64, y float64) snap {
+	key := [2]float64{x, y}
+	n.mu.RLock()
+	s, ok := n.snapped[key]
+	n.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	// network has edges, so the largest component has at least two nodes
+	i := n.candidates.Nearest(x, y, 1)[0]
+	s = snap{i, math.Hypot(n.x[i]-x, n.y[i]-y)}
+
+	n.mu.Lock()
+	n.snapped[key] = s
+	n.targets[s.node] = true
+	n.mu.Unlock()
+	return s
+}
+
+// Distance returns length of the way between projected locations: from the first location
+// to its nearest node, along the streets and from the node nearest to the second location.
+func (n *Network) Distance(fromX float64, fromY float64, toX float64, toY float64) float64 {
+	if fromX == toX && fromY == toY {
+		return 0
+	}
+	from := n.snap(fromX, fromY)
+	to := n.snap(toX, toY)
+	if from.node == to.node {
+		// locations near the same crossing are reached directly
+		return math.Hypot(fromX-toX, fromY-toY)
+	}
+	return from.distance + n.nodeDistance(from.node, to.node) + to.distance
+}
+
+func (n *Network) nodeDistance(from int, to int) float64 {
+	n.mu.RLock()
+	distance, ok := n.distances[[2]int{from, to}]
+	n.mu.RUnlock()
+	if ok {
+		return distance
+	}
+
+	settled := make(map[int]float64)
+	n.mu.RLock()
+	distance = shortestPaths(n.edges, from, to, func(node int, d float64) {
+		if n.targets[node] {
+			settled[node] = d
+		}
+	})
+	n.mu.RUnlock()
+
+	n.mu.Lock()
+	// graph is undirected, so distances are cached in both directions
+	for node, d := range settled {
+		n.distances[[2]int{from, node}] = d
+		n.distances[[2]int{node, from}] = d
+	}
+	n.mu.Unlock()
+	return distance
+}
@@ -0,0 +1,124 @@
+package roads
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+// testNetwork is testGraph without lengths: ways are great-circle distances between nodes.
+func testNetwork(t *testing.T) *Network {
+	g, err := ReadEdgeList(strings.NewReader(testGraph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range g.edges {
+		for k := range g.edges[i] {
+			from, to := g.nodes[i], g.nodes[g.edges[i][k].to]
+			g.edges[i][k].length = geo.Haversine(from.lat, from.lng, to.lat, to.lng)
+		}
+	}
+	n, err := NewNetwork(g, geo.Local{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSnap(t *testing.T) {
+	n := testNetwork(t)
+	tests := []struct {
+		name     string
+		lat, lng float64
+		node     int64
+	}{
+		{"node", 0, 0.001, 2},
+		{"near node", 0.0001, 0.0019, 3},
+		{"far away", 1, 1, 4},
+		// nodes 5 and 6 are closer, but they are not connected to the largest component
+		{"separate component", 0.0005, 0.00055, 2},
+	}
+	for _, test := range tests {
+		x, y := geo.Local{}.Project(test.lat, test.lng)
+		node, distance := n.Snap(x, y)
+		i := n.graph.index[test.node]
+		if want := math.Hypot(n.x[i]-x, n.y[i]-y); node != test.node || math.Abs(distance-want) > 1e-9 {
+			t.Errorf("%s: Snap = %d, %v, want %d, %v", test.name, node, distance, test.node, want)
+		}
+	}
+}
+
+// TestSnapNearest compares snapping with linear scan of nodes.
+func TestSnapNearest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	var graph strings.Builder
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&graph, "node %d %f %f\n", i, random.Float64()*0.1, random.Float64()*0.1)
+		if i > 0 {
+			fmt.Fprintf(&graph, "edge %d %d\n", i-1, i)
+		}
+	}
+	g, err := ReadEdgeList(strings.NewReader(graph.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := NewNetwork(g, geo.WebMercator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k := 0; k < 200; k++ {
+		x, y := geo.WebMercator{}.Project(random.Float64()*0.12-0.01, random.Float64()*0.12-0.01)
+		want := math.Inf(1)
+		for i := range n.x {
+			want = math.Min(want, math.Hypot(n.x[i]-x, n.y[i]-y))
+		}
+		if _, distance := n.Snap(x, y); distance != want {
+			t.Errorf("Snap(%v, %v) distance %v, want %v", x, y, distance, want)
+		}
+	}
+}
+
+func TestNetworkDistance(t *testing.T) {
+	n := testNetwork(t)
+	project := func(lat float64, lng float64) [2]float64 {
+		x, y := geo.Local{}.Project(lat, lng)
+		return [2]float64{x, y}
+	}
+	node := func(id int64) [2]float64 {
+		i := n.graph.index[id]
+		return [2]float64{n.x[i], n.y[i]}
+	}
+	degree := geo.Haversine(0, 0, 0, 0.001)
+
+	tests := []struct {
+		name     string
+		from, to [2]float64
+		want     float64
+	}{
+		{"same location", node(1), node(1), 0},
+		{"direct street", node(1), node(4), geo.Haversine(0, 0, 0.001, 0.002)},
+		{"back", node(4), node(1), geo.Haversine(0, 0, 0.001, 0.002)},
+		{"along streets", node(2), node(4), 2 * degree},
+		{"snapped", project(-0.0001, 0), node(3), 0.1*degree + 2*degree},
+		{"same node", project(-0.0001, 0), project(0.0001, 0), 0.2 * degree},
+	}
+	for _, test := range tests {
+		got := n.Distance(test.from[0], test.from[1], test.to[0], test.to[1])
+		if math.Abs(got-test.want) > 1e-3*test.want+1e-6 {
+			t.Errorf("%s: Distance = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestEmptyNetwork(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(1, 0, 0)
+	if _, err := NewNetwork(g, geo.Local{}); err != ErrEmptyNetwork {
+		t.Errorf("NewNetwork error %v, want %v", err, ErrEmptyNetwork)
+	}
+}
@@ -0,0 +1,114 @@
+package roads
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// excludedHighways are not walkable.
+var excludedHighways = map[string]bool{
+	"motorway":      true,
+	"motorway_link": true,
+	"trunk":         true,
+	"trunk_link":    true,
+	"construction":  true,
+	"proposed":      true,
+}
+
+type osmNode struct {
+	ID  int64   `xml:"id,attr"`
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+type osmWay struct {
+	Nodes []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []struct {
+		Key   string `xml:"k,attr"`
+		Value string `xml:"v,attr"`
+	} `xml:"tag"`
+}
+
+func (w osmWay) tag(key string) string {
+	for _, t := range w.Tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// walkable reports whether pedestrians can use the way, one-way streets are walkable in both directions.
+func (w osmWay) walkable() bool {
+	highway := w.tag("highway")
+	if highway == "" || excludedHighways[highway] {
+		return false
+	}
+	access := w.tag("foot")
+	if access == "" {
+		access = w.tag("access")
+	}
+	return access != "no" && access != "private"
+}
+
+// ReadOSM reads walkable street network from OpenStreetMap XML, e.g. exported from openstreetmap.org
+// or converted from PBF with osmium. Only ways with highway tag are used and only their nodes are kept.
+func ReadOSM(r io.Reader) (*Graph, error) {
+	decoder := xml.NewDecoder(r)
+	coordinates := make(map[int64]osmNode)
+	ways := make([]osmWay, 0)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("roads: %v", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch element.Name.Local {
+		case "node":
+			var n osmNode
+			if err := decoder.DecodeElement(&n, &element); err != nil {
+				return nil, fmt.Errorf("roads: %v", err)
+			}
+			coordinates[n.ID] = n
+		case "way":
+			var w osmWay
+			if err := decoder.DecodeElement(&w, &element); err != nil {
+				return nil, fmt.Errorf("roads: %v", err)
+			}
+			if w.walkable() {
+				ways = append(ways, w)
+			}
+		}
+	}
+
+	g := NewGraph()
+	for _, w := range ways {
+		for i, nd := range w.Nodes {
+			n, ok := coordinates[nd.Ref]
+			if !ok {
+				// ways of extracts may reference nodes outside of the bounding box
+				continue
+			}
+			g.AddNode(n.ID, n.Lat, n.Lon)
+			if i == 0 {
+				continue
+			}
+			if _, ok := coordinates[w.Nodes[i-1].Ref]; ok {
+				if err := g.AddEdge(w.Nodes[i-1].Ref, nd.Ref, 0); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,52 @@
+package roads
+
+import (
+	"container/heap"
+	"math"
+)
+
+type queueItem struct {
+	node     int
+	distance float64
+}
+
+type queue []queueItem
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// shortestPaths runs Dijkstra algorithm from source until target is reached and calls visit
+// for every settled node. It returns distance to target or +Inf if target is not reachable.
+func shortestPaths(edges [][]edge, source int, target int, visit func(node int, distance float64)) float64 {
+	distances := map[int]float64{source: 0}
+	settled := make(map[int]bool)
+	q := &queue{{source, 0}}
+	for q.Len() > 0 {
+		item := heap.Pop(q).(queueItem)
+		if settled[item.node] {
+			continue
+		}
+		settled[item.node] = true
+		visit(item.node, item.distance)
+		if item.node == target {
+			return item.distance
+		}
+
+		for _, e := range edges[item.node] {
+			distance := item.distance + e.length
+			if d, ok := distances[e.to]; !ok || distance < d {
+				distances[e.to] = distance
+				heap.Push(q, queueItem{e.to, distance})
+			}
+		}
+	}
+	return math.Inf(1)
+}
@@ -4,6 +4,7 @@ import (
 	//	"math"
 	"github.com/mukhinaks/fops/generic"
 	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/query"
 )
 
 type CityBrandScore struct {
@@ -14,44 +15,49 @@ type CityBrandScore struct {
 	FacebookRatingMax        float64
 	FoursquareRatingVotesMax float64
 	StartLocation            generic.Point
+	// Travel is a travel model of the solver, walking time is used if it is nil.
+	Travel generic.TravelModel
+	// Restaurants are not scored, see points.RestaurantsOf.
+	Restaurants *query.Expression
 	//MaximumDistanceToStart float64
 }
 
-func (f CityBrandScore) Init(locs []generic.Point) generic.Score {
+func (f CityBrandScore) Init(solver *generic.Solver) (generic.Score, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckPointIndex("start", f.StartID, locs); err != nil {
+		return nil, err
+	}
 	instagramVisitorsMax := 0.0
 	tripAdvisorVisitorsMax := 0.0
 	facebookRatingMax := 0.0
 	foursquareRatingVotesMax := 0.0
 
-	start := locs[f.StartID].(points.CityBrandLocation)
+	start := locs[f.StartID]
 	//maxDistance := 0.0
+	restaurants, err := points.RestaurantsOf(solver.Points)
+	if err != nil {
+		return nil, err
+	}
+	f.Restaurants = restaurants
 
-	for _, loc := range locs {
-		location := loc.(points.CityBrandLocation)
-		drop := false
-		for _, category := range location.Categories {
-			if category == "Restaurant" {
-				drop = true
-				break
-			}
-		}
-		if drop {
+	for _, location := range locs {
+		if restaurants.Match(location) {
 			continue
 		}
 
-		if location.InstagramVisitorsNumber >= instagramVisitorsMax {
-			instagramVisitorsMax = location.InstagramVisitorsNumber
+		if location.Attribute("instagram_visitorsNumber") >= instagramVisitorsMax {
+			instagramVisitorsMax = location.Attribute("instagram_visitorsNumber")
 		}
-		if location.FacebookRating >= facebookRatingMax {
-			facebookRatingMax = location.FacebookRating
+		if location.Attribute("facebook_rating") >= facebookRatingMax {
+			facebookRatingMax = location.Attribute("facebook_rating")
 		}
 
-		if location.TripAdvisorReviewsNumber >= tripAdvisorVisitorsMax {
-			tripAdvisorVisitorsMax = location.TripAdvisorReviewsNumber
+		if location.Attribute("tripAdvisor_reviewsNumber") >= tripAdvisorVisitorsMax {
+			tripAdvisorVisitorsMax = location.Attribute("tripAdvisor_reviewsNumber")
 		}
 
-		if location.FoursquareRatingVotes >= foursquareRatingVotesMax {
-			foursquareRatingVotesMax = location.FoursquareRatingVotes
+		if location.Attribute("foursquare_ratingVotes") >= foursquareRatingVotesMax {
+			foursquareRatingVotesMax = location.Attribute("foursquare_ratingVotes")
 		}
 
 		/*
@@ -88,8 +94,9 @@ func (f CityBrandScore) Init(locs []generic.Point) generic.Score {
 	}
 
 	f.StartLocation = start
+	f.Travel = solver.Travel
 	//f.MaximumDistanceToStart = maxDistance
-	return f
+	return f, nil
 }
 
 func (f CityBrandScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
@@ -98,13 +105,13 @@ func (f CityBrandScore) SinglePointScore(route map[int]generic.Point, orderOfLoc
 
 	if len(orderOfLocations) != 0 && id != f.StartID {
 		idLastLocation := orderOfLocations[len(orderOfLocations)-1]
-		loc1 := route[idLastLocation].(points.CityBrandLocation)
-		loc2 := location.(points.CityBrandLocation)
+		loc1 := route[idLastLocation]
+		loc2 := location
 		distance := points.EuclidianDistance(loc1, loc2)
 		distanceCoefficient = 1.0
 
 		for i := 0; i < len(orderOfLocations)-1; i++ {
-			if points.EuclidianDistance(loc2, route[orderOfLocations[i]].(points.CityBrandLocation)) <= distance {
+			if points.EuclidianDistance(loc2, route[orderOfLocations[i]]) <= distance {
 				distanceCoefficient = 0
 			}
 		}
@@ -112,11 +119,10 @@ func (f CityBrandScore) SinglePointScore(route map[int]generic.Point, orderOfLoc
 	} else {
 		distanceCoefficient = 1
 	}
-	loc := location.(points.CityBrandLocation)
-	score := float64(len(loc.CityBrand))/10.0 + ((loc.FoursquareRating/10.0)*loc.FoursquareRatingVotes/f.FoursquareRatingVotesMax+
-		(loc.TripAdvisorRating/5.0)*loc.TripAdvisorReviewsNumber/f.TripAdvisorVisitorsMax+
-		loc.InstagramVisitorsNumber/f.InstagramVisitorsMax+
-		loc.FacebookRating/f.FacebookRatingMax/2+
+	score := location.Attribute("city_brand")/10.0 + ((location.Attribute("foursquare_rating")/10.0)*location.Attribute("foursquare_ratingVotes")/f.FoursquareRatingVotesMax+
+		(location.Attribute("tripAdvisor_rating")/5.0)*location.Attribute("tripAdvisor_reviewsNumber")/f.TripAdvisorVisitorsMax+
+		location.Attribute("instagram_visitorsNumber")/f.InstagramVisitorsMax+
+		location.Attribute("facebook_rating")/f.FacebookRatingMax/2+
 		0)/1.0 +
 		distanceCoefficient/4
 
@@ -137,13 +143,19 @@ func (f CityBrandScore) RouteScore(route map[int]generic.Point, orderOfLocations
 }
 
 func (f CityBrandScore) ComputeRouteTimeFromSample(locationsID []int, allLocations []generic.Point) int {
-	lastLocation := allLocations[len(locationsID)-1].(points.CityBrandLocation)
-	time := lastLocation.Duration
+	lastLocation := allLocations[locationsID[len(locationsID)-1]]
+	time := lastLocation.VisitDuration()
 
 	for i := 0; i < len(locationsID)-1; i++ {
-		loc1 := allLocations[locationsID[i]].(points.CityBrandLocation)
-		loc2 := allLocations[locationsID[i+1]].(points.CityBrandLocation)
-		time += loc1.Duration + points.WalkingTime(loc1, loc2)
+		loc1 := allLocations[locationsID[i]]
+		loc2 := allLocations[locationsID[i+1]]
+		travelTime := 0
+		if f.Travel != nil {
+			travelTime = f.Travel.TravelTime(loc1, loc2)
+		} else {
+			travelTime = points.WalkingTime(loc1, loc2)
+		}
+		time += loc1.VisitDuration() + travelTime
 	}
 
 	return time
@@ -151,17 +163,14 @@ func (f CityBrandScore) ComputeRouteTimeFromSample(locationsID []int, allLocatio
 
 func (f CityBrandScore) SinglePointScoreWithoutPositionDependance(location generic.Point, id int) float64 {
 
-	loc := location.(points.CityBrandLocation)
-	for _, category := range loc.Categories {
-		if category == "Restaurant" {
-			return 0
-		}
+	if f.Restaurants.Match(location) {
+		return 0
 	}
-	score := float64(len(loc.CityBrand))/10.0 + ((loc.FoursquareRating/10.0)*loc.FoursquareRatingVotes/f.FoursquareRatingVotesMax+
-		(loc.TripAdvisorRating/5.0)*loc.TripAdvisorReviewsNumber/f.TripAdvisorVisitorsMax+
-		loc.InstagramVisitorsNumber/f.InstagramVisitorsMax+
-		loc.FacebookRating/f.FacebookRatingMax+
-		loc.WikipediaPage/100)/5.0
+	score := location.Attribute("city_brand")/10.0 + ((location.Attribute("foursquare_rating")/10.0)*location.Attribute("foursquare_ratingVotes")/f.FoursquareRatingVotesMax+
+		(location.Attribute("tripAdvisor_rating")/5.0)*location.Attribute("tripAdvisor_reviewsNumber")/f.TripAdvisorVisitorsMax+
+		location.Attribute("instagram_visitorsNumber")/f.InstagramVisitorsMax+
+		location.Attribute("facebook_rating")/f.FacebookRatingMax+
+		location.Attribute("wikipedia_page")/100)/5.0
 
 	return score
 }
@@ -0,0 +1,87 @@
+package score
+
+import (
+	"math"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/taxonomy"
+)
+
+// DiversityScore is a layer over score of any problem which penalizes locations of categories already
+// visited by the route. Categories are compared with their ancestors of taxonomy, so a museum after
+// a museum repeats "culture/museums" and "culture", a gallery after a museum repeats only "culture".
+type DiversityScore struct {
+	generic.Score
+	// Penalty is subtracted from score for every repeated category or ancestor category of location.
+	Penalty float64
+}
+
+func (f DiversityScore) Init(solver *generic.Solver) (generic.Score, error) {
+	score, err := f.Score.Init(solver)
+	if err != nil {
+		return nil, err
+	}
+	f.Score = score
+	return f, nil
+}
+
+// SinglePointScore is reduced by penalty for every category of location which is visited by the route,
+// it is not negative.
+func (f DiversityScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
+	location generic.Point, id int) float64 {
+	score := f.Score.SinglePointScore(route, orderOfLocations, location, id)
+	visited := make(map[string]bool)
+	for _, key := range orderOfLocations {
+		if key != id {
+			visit(visited, route[key])
+		}
+	}
+	return math.Max(0, score-f.Penalty*float64(repeated(visited, location)))
+}
+
+func (f DiversityScore) RouteScore(route map[int]generic.Point, orderOfLocations []int) float64 {
+	score := f.Score.RouteScore(route, orderOfLocations)
+	visited := make(map[string]bool)
+	for _, key := range orderOfLocations {
+		score -= f.Penalty * float64(repeated(visited, route[key]))
+		visit(visited, route[key])
+	}
+	return score
+}
+
+func (f DiversityScore) UpdateScore(route map[int]generic.Point, orderOfPoints []int, locations map[int]generic.Point) generic.Score {
+	f.Score = f.Score.UpdateScore(route, orderOfPoints, locations)
+	return f
+}
+
+// levels returns categories of location and their ancestors without repetitions.
+func levels(location generic.Point) []string {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, category := range location.CategoryNames() {
+		for _, level := range append([]string{category}, taxonomy.Ancestors(category)...) {
+			if !seen[level] {
+				seen[level] = true
+				result = append(result, level)
+			}
+		}
+	}
+	return result
+}
+
+func visit(visited map[string]bool, location generic.Point) {
+	for _, level := range levels(location) {
+		visited[level] = true
+	}
+}
+
+// repeated returns number of categories and ancestors of location which are visited.
+func repeated(visited map[string]bool, location generic.Point) int {
+	count := 0
+	for _, level := range levels(location) {
+		if visited[level] {
+			count++
+		}
+	}
+	return count
+}
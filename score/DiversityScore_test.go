@@ -0,0 +1,56 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+func TestDiversityScore(t *testing.T) {
+	locations := []generic.Point{
+		&points.BaseLocation{Category: []string{"culture/museums"}},
+		&points.BaseLocation{Category: []string{"culture/museums/history"}},
+		&points.BaseLocation{Category: []string{"culture/galleries", "food/cafes"}},
+		&points.BaseLocation{Category: []string{"nature/parks"}},
+		&points.BaseLocation{Category: []string{"Sights & Landmarks"}},
+		&points.BaseLocation{Category: []string{"Sights & Landmarks"}},
+		&points.BaseLocation{Category: []string{"culture/museums", "food/cafes"}},
+	}
+	route := make(map[int]generic.Point)
+	for i, location := range locations {
+		route[i] = location
+	}
+	f := DiversityScore{Score: constantScore{}, Penalty: 3}
+	sc, err := f.Init(&generic.Solver{Points: dataset{locations: locations}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		order  []int
+		next   int
+		single float64
+		route  float64
+	}{
+		{"empty route", []int{}, 0, 10, 100},
+		{"other category", []int{0}, 3, 10, 100},
+		{"same category and ancestor", []int{6}, 0, 10 - 2*3, 100},
+		// culture/museums/history repeats culture/museums and culture
+		{"ancestors of subcategory", []int{0, 3}, 1, 10 - 2*3, 100},
+		{"ancestor of sibling", []int{1}, 2, 10 - 3, 100},
+		{"location itself is not repeated", []int{0, 1}, 1, 10 - 2*3, 100 - 2*3},
+		{"penalty is larger than score", []int{0, 2}, 6, 0, 100 - 3},
+		{"unmapped categories", []int{4, 3}, 5, 10 - 3, 100},
+		{"every repeated location", []int{0, 1, 2, 3, 4, 5}, 5, 10 - 3, 100 - 2*3 - 3 - 3},
+	}
+	for _, test := range tests {
+		if got := sc.SinglePointScore(route, test.order, locations[test.next], test.next); got != test.single {
+			t.Errorf("%s: SinglePointScore = %v, want %v", test.name, got, test.single)
+		}
+		if got := sc.RouteScore(route, test.order); got != test.route {
+			t.Errorf("%s: RouteScore = %v, want %v", test.name, got, test.route)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package score
+
+import (
+	"math"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+// GeofenceScore is a layer over score of any problem which penalizes legs of route crossing forbidden areas.
+type GeofenceScore struct {
+	generic.Score
+	// StartID and EndID are positions of route ends, legs from start and to end are checked if
+	// the order of route does not contain them. EndID is -1 if route has no fixed end.
+	StartID int
+	EndID   int
+	// Crosses tells if the leg between locations crosses forbidden area.
+	Crosses func(from generic.Point, to generic.Point) bool
+	// Penalty is subtracted from route score for every crossing leg.
+	Penalty float64
+
+	StartLocation generic.Point
+	EndLocation   generic.Point
+}
+
+func (f GeofenceScore) Init(solver *generic.Solver) (generic.Score, error) {
+	score, err := f.Score.Init(solver)
+	if err != nil {
+		return nil, err
+	}
+	f.Score = score
+	locs := solver.Points.GetAllPoints()
+	f.StartLocation, f.EndLocation = nil, nil
+	if f.StartID >= 0 && f.StartID < len(locs) {
+		f.StartLocation = locs[f.StartID]
+	}
+	if f.EndID >= 0 && f.EndID < len(locs) {
+		f.EndLocation = locs[f.EndID]
+	}
+	return f, nil
+}
+
+// SinglePointScore is reduced by penalty if the leg from the last location of the route (or from start)
+// crosses forbidden area, it is not negative.
+func (f GeofenceScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
+	location generic.Point, id int) float64 {
+	score := f.Score.SinglePointScore(route, orderOfLocations, location, id)
+	from := f.StartLocation
+	if len(orderOfLocations) > 0 {
+		from = route[orderOfLocations[len(orderOfLocations)-1]]
+	}
+	if from != nil && f.Crosses(from, location) {
+		score = math.Max(0, score-f.Penalty)
+	}
+	return score
+}
+
+func (f GeofenceScore) RouteScore(route map[int]generic.Point, orderOfLocations []int) float64 {
+	score := f.Score.RouteScore(route, orderOfLocations)
+	legs := make([]generic.Point, 0, len(orderOfLocations)+2)
+	if f.StartLocation != nil && !contains(orderOfLocations, f.StartID) {
+		legs = append(legs, f.StartLocation)
+	}
+	for _, key := range orderOfLocations {
+		legs = append(legs, route[key])
+	}
+	if f.EndLocation != nil && !contains(orderOfLocations, f.EndID) {
+		legs = append(legs, f.EndLocation)
+	}
+	for i := 0; i < len(legs)-1; i++ {
+		if f.Crosses(legs[i], legs[i+1]) {
+			score -= f.Penalty
+		}
+	}
+	return score
+}
+
+func (f GeofenceScore) UpdateScore(route map[int]generic.Point, orderOfPoints []int, locations map[int]generic.Point) generic.Score {
+	f.Score = f.Score.UpdateScore(route, orderOfPoints, locations)
+	return f
+}
+
+func contains(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
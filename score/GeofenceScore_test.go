@@ -0,0 +1,83 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+// constantScore gives 10 to every location and 100 to every route.
+type constantScore struct {
+	generic.Score
+}
+
+func (s constantScore) Init(solver *generic.Solver) (generic.Score, error) {
+	return s, nil
+}
+
+func (s constantScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
+	location generic.Point, id int) float64 {
+	return 10
+}
+
+func (s constantScore) RouteScore(route map[int]generic.Point, orderOfLocations []int) float64 {
+	return 100
+}
+
+type dataset struct {
+	generic.Points
+	locations []generic.Point
+}
+
+func (d dataset) GetAllPoints() []generic.Point {
+	return d.locations
+}
+
+func TestGeofenceScore(t *testing.T) {
+	// start 0 and location 1 are west of the wall at longitude 0, location 2 and end 3 are east of it
+	locations := []generic.Point{
+		&points.BaseLocation{Lng: -1},
+		&points.BaseLocation{Lng: -2},
+		&points.BaseLocation{Lng: 2},
+		&points.BaseLocation{Lng: 1},
+	}
+	route := map[int]generic.Point{0: locations[0], 1: locations[1], 2: locations[2], 3: locations[3]}
+	crosses := func(from generic.Point, to generic.Point) bool {
+		_, lng1 := from.LatLng()
+		_, lng2 := to.LatLng()
+		return (lng1 < 0) != (lng2 < 0)
+	}
+	solver := &generic.Solver{Points: dataset{locations: locations}}
+
+	tests := []struct {
+		name    string
+		endID   int
+		penalty float64
+		order   []int
+		next    int
+		single  float64
+		route   float64
+	}{
+		{"leg from start", 3, 4, []int{}, 1, 10, 100 - 4},
+		{"leg from start crosses", 3, 4, []int{}, 2, 10 - 4, 100 - 4},
+		{"leg from last location", 3, 4, []int{1}, 2, 10 - 4, 100 - 4},
+		{"penalty is larger than score", 3, 15, []int{1}, 2, 0, 100 - 15},
+		{"route with ends", 3, 4, []int{0, 1, 2, 3}, 2, 10, 100 - 4},
+		{"every crossing leg", 3, 4, []int{2, 1}, 1, 10, 100 - 3*4},
+		{"route without end", -1, 4, []int{2, 1}, 3, 10 - 4, 100 - 2*4},
+	}
+	for _, test := range tests {
+		f := GeofenceScore{Score: constantScore{}, StartID: 0, EndID: test.endID, Penalty: test.penalty, Crosses: crosses}
+		sc, err := f.Init(solver)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := sc.SinglePointScore(route, test.order, locations[test.next], test.next); got != test.single {
+			t.Errorf("%s: SinglePointScore = %v, want %v", test.name, got, test.single)
+		}
+		if got := sc.RouteScore(route, test.order); got != test.route {
+			t.Errorf("%s: RouteScore = %v, want %v", test.name, got, test.route)
+		}
+	}
+}
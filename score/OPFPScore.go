@@ -21,26 +21,29 @@ type OPFPScore struct {
 	EndLocation              generic.Point
 }
 
-func (f OPFPScore) Init(locs []generic.Point) generic.Score {
+func (f OPFPScore) Init(solver *generic.Solver) (generic.Score, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
 	instagramVisitorsMax := 0.0
 	tripAdvisorVisitorsMax := 0.0
 	foursquareRatingVotesMax := 0.0
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+	start := locs[f.StartID]
+	end := locs[f.EndID]
 
 	f.StartLocationDistances = make(map[int]float64)
 	f.EndLocationDistance = make(map[int]float64)
 
-	for idx, loc := range locs {
-		location := loc.(points.BaseLocation)
-		if location.InstagramVisitorsNumber > instagramVisitorsMax {
-			instagramVisitorsMax = location.InstagramVisitorsNumber
+	for idx, location := range locs {
+		if location.Attribute("instagram_visitorsNumber") > instagramVisitorsMax {
+			instagramVisitorsMax = location.Attribute("instagram_visitorsNumber")
 		}
-		if location.TripAdvisorReviewsNumber > tripAdvisorVisitorsMax {
-			tripAdvisorVisitorsMax = location.TripAdvisorReviewsNumber
+		if location.Attribute("tripAdvisor_reviewsNumber") > tripAdvisorVisitorsMax {
+			tripAdvisorVisitorsMax = location.Attribute("tripAdvisor_reviewsNumber")
 		}
-		if location.FoursquareRatingVotes > foursquareRatingVotesMax {
-			foursquareRatingVotesMax = location.FoursquareRatingVotes
+		if location.Attribute("foursquare_ratingVotes") > foursquareRatingVotesMax {
+			foursquareRatingVotesMax = location.Attribute("foursquare_ratingVotes")
 		}
 		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location)
 		f.EndLocationDistance[idx] = points.EuclidianDistance(end, location)
@@ -67,7 +70,7 @@ func (f OPFPScore) Init(locs []generic.Point) generic.Score {
 
 	f.StartLocation = start
 	f.EndLocation = end
-	return f
+	return f, nil
 }
 
 func (f OPFPScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
@@ -84,12 +87,12 @@ func (f OPFPScore) SinglePointScore(route map[int]generic.Point, orderOfLocation
 
 		previousLocation := f.StartLocation
 		if positionInRoute > 0 {
-			previousLocation = route[orderOfLocations[positionInRoute-1]].(points.BaseLocation)
+			previousLocation = route[orderOfLocations[positionInRoute-1]]
 		}
 
 		nextLocation := f.EndLocation
 		if positionInRoute < len(orderOfLocations)-1 && positionInRoute != -1 {
-			nextLocation = route[orderOfLocations[positionInRoute+1]].(points.BaseLocation)
+			nextLocation = route[orderOfLocations[positionInRoute+1]]
 		}
 
 		distanceCoefficient = points.EuclidianDistance(previousLocation, nextLocation) / (points.EuclidianDistance(previousLocation, location) +
@@ -98,10 +101,9 @@ func (f OPFPScore) SinglePointScore(route map[int]generic.Point, orderOfLocation
 	} else {
 		distanceCoefficient = f.StartEndDistance / (f.StartLocationDistances[id] + f.EndLocationDistance[id])
 	}
-	loc := location.(points.BaseLocation)
-	score := loc.OfficialGuide + loc.FoursquareRating/10.0 +
-		(loc.TripAdvisorRating/5.0)*(loc.TripAdvisorReviewsNumber/f.TripAdvisorVisitorsMax) +
-		loc.InstagramVisitorsNumber/f.InstagramVisitorsMax +
+	score := location.Attribute("officialGuide") + location.Attribute("foursquare_rating")/10.0 +
+		(location.Attribute("tripAdvisor_rating")/5.0)*(location.Attribute("tripAdvisor_reviewsNumber")/f.TripAdvisorVisitorsMax) +
+		location.Attribute("instagram_visitorsNumber")/f.InstagramVisitorsMax +
 		distanceCoefficient
 
 	return score
@@ -131,16 +133,15 @@ func (f OPFPScore) UpdateScore(route map[int]generic.Point, orderOfPoints []int,
 	tripAdvisorVisitorsMax := 0.0
 	foursquareRatingVotesMax := 0.0
 
-	for _, loc := range locs {
-		location := loc.(points.BaseLocation)
-		if location.InstagramVisitorsNumber > instagramVisitorsMax {
-			instagramVisitorsMax = location.InstagramVisitorsNumber
+	for _, location := range locs {
+		if location.Attribute("instagram_visitorsNumber") > instagramVisitorsMax {
+			instagramVisitorsMax = location.Attribute("instagram_visitorsNumber")
 		}
-		if location.TripAdvisorReviewsNumber > tripAdvisorVisitorsMax {
-			tripAdvisorVisitorsMax = location.TripAdvisorReviewsNumber
+		if location.Attribute("tripAdvisor_reviewsNumber") > tripAdvisorVisitorsMax {
+			tripAdvisorVisitorsMax = location.Attribute("tripAdvisor_reviewsNumber")
 		}
-		if location.FoursquareRatingVotes > foursquareRatingVotesMax {
-			foursquareRatingVotesMax = location.FoursquareRatingVotes
+		if location.Attribute("foursquare_ratingVotes") > foursquareRatingVotesMax {
+			foursquareRatingVotesMax = location.Attribute("foursquare_ratingVotes")
 		}
 	}
 
@@ -19,19 +19,22 @@ type SimpleScore struct {
 	EndLocation            generic.Point
 }
 
-func (f SimpleScore) Init(locs []generic.Point) generic.Score {
+func (f SimpleScore) Init(solver *generic.Solver) (generic.Score, error) {
+	locs := solver.Points.GetAllPoints()
+	if err := generic.CheckStartEnd(f.StartID, f.EndID, locs); err != nil {
+		return nil, err
+	}
 	instagramVisitorsMax := 0.0
 
-	start := locs[f.StartID].(points.BaseLocation)
-	end := locs[f.EndID].(points.BaseLocation)
+	start := locs[f.StartID]
+	end := locs[f.EndID]
 
 	f.StartLocationDistances = make(map[int]float64)
 	f.EndLocationDistance = make(map[int]float64)
 
-	for idx, loc := range locs {
-		location := loc.(points.BaseLocation)
-		if location.InstagramVisitorsNumber > instagramVisitorsMax {
-			instagramVisitorsMax = location.InstagramVisitorsNumber
+	for idx, location := range locs {
+		if location.Attribute("instagram_visitorsNumber") > instagramVisitorsMax {
+			instagramVisitorsMax = location.Attribute("instagram_visitorsNumber")
 		}
 
 		f.StartLocationDistances[idx] = points.EuclidianDistance(start, location)
@@ -43,15 +46,13 @@ func (f SimpleScore) Init(locs []generic.Point) generic.Score {
 
 	f.StartLocation = start
 	f.EndLocation = end
-	return f
+	return f, nil
 }
 
 func (f SimpleScore) SinglePointScore(route map[int]generic.Point, orderOfLocations []int,
 	location generic.Point, id int) float64 {
 
-	loc := location.(points.BaseLocation)
-
-	return loc.InstagramVisitorsNumber
+	return location.Attribute("instagram_visitorsNumber")
 }
 
 func (f SimpleScore) RouteScore(route map[int]generic.Point, orderOfLocations []int) float64 {
@@ -0,0 +1,17 @@
+package score
+
+import (
+	"github.com/mukhinaks/fops/generic"
+)
+
+func init() {
+	generic.RegisterScore("simple", func(task generic.Task) (generic.Score, error) {
+		return SimpleScore{StartID: task.StartID, EndID: task.EndID}, nil
+	})
+	generic.RegisterScore("opfp", func(task generic.Task) (generic.Score, error) {
+		return OPFPScore{StartID: task.StartID, EndID: task.EndID}, nil
+	})
+	generic.RegisterScore("citybrand", func(task generic.Task) (generic.Score, error) {
+		return CityBrandScore{StartID: task.StartID}, nil
+	})
+}
@@ -0,0 +1,593 @@
+package fops
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	// built-in path algorithms are registered by their packages
+	_ "github.com/mukhinaks/fops/algorithm/aco"
+	_ "github.com/mukhinaks/fops/algorithm/rga"
+	"github.com/mukhinaks/fops/constraints"
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+	"github.com/mukhinaks/fops/score"
+	// built-in travel models are registered by their package
+	_ "github.com/mukhinaks/fops/travel"
+)
+
+// eatTime is a time reserved for meals in city brand routes.
+const eatTime = 90
+
+type routeTimer interface {
+	FinalRouteTime(route map[int]generic.Point, orderOfLocations []int) int
+}
+
+type routeWriter interface {
+	WriteLocationsToJSON(route map[int]generic.Point, order []int, filePath string) error
+}
+
+// initialRouteAlgorithm is a path algorithm which improves initial route, e.g. RGA.
+type initialRouteAlgorithm interface {
+	SetInitialRoute(route map[int]generic.Point, keys []int) generic.PathAlgorithm
+}
+
+// components are names of registered points, score and constraints.
+type components struct {
+	points      string
+	score       string
+	constraints string
+}
+
+// singleIntervalComponents are default components of problems with a single route interval.
+// Problems without kind are composed from components of the problem specification only.
+var singleIntervalComponents = map[string]components{
+	"":   {points: "base", score: "simple"},
+	OP:   {points: "base", score: "simple", constraints: "op"},
+	OPTW: {points: "base", score: "simple", constraints: "optw"},
+	TDOP: {points: "base", score: "simple", constraints: "tdop"},
+	OPFP: {points: "base", score: "opfp", constraints: "opfp"},
+}
+
+// Solve constructs an itinerary for the problem.
+// If problem.OutputPath is set, resulting route is written there as JSON.
+func Solve(ctx context.Context, problem Problem) (Itinerary, error) {
+	if err := ctx.Err(); err != nil {
+		return Itinerary{}, err
+	}
+
+	kind := strings.ToLower(problem.Kind)
+	customComponents := problem.Points != "" || problem.Score != "" || problem.Constraints != ""
+	if names, ok := singleIntervalComponents[kind]; ok && (kind != "" || customComponents) &&
+		len(problem.ReferencePath) == 0 {
+		return solveSingleInterval(ctx, problem, names.override(problem))
+	}
+	if customComponents {
+		return Itinerary{}, fmt.Errorf("fops: problem kind %q does not support custom components", problem.Kind)
+	}
+
+	switch kind {
+	case OP:
+		return solveOP(ctx, problem)
+	case OPCV:
+		if problem.Days > 1 {
+			return solveOPCVForMultipleDays(ctx, problem)
+		}
+		return solveOPCV(ctx, problem)
+	case CityBrand:
+		return solveCityBrand(ctx, problem)
+	default:
+		return Itinerary{}, fmt.Errorf("fops: unknown problem kind %q", problem.Kind)
+	}
+}
+
+// override replaces default components with components from the problem specification.
+func (names components) override(problem Problem) components {
+	if problem.Points != "" {
+		names.points = problem.Points
+	}
+	if problem.Score != "" {
+		names.score = problem.Score
+	}
+	if problem.Constraints != "" {
+		names.constraints = problem.Constraints
+	}
+	return names
+}
+
+// solveOP solves classic Orienteering Problem with reference path.
+// The route is constructed between first and last locations of the reference path
+// with respect of the reference path's time budget.
+func solveOP(ctx context.Context, problem Problem) (Itinerary, error) {
+	if len(problem.ReferencePath) < 2 {
+		return Itinerary{}, fmt.Errorf("fops: reference path should contain at least 2 locations")
+	}
+
+	locs := points.BaseLocations{}
+	solver, p, err := loadSolver(problem, locs)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	p.start = p.reference[0]
+	p.end = p.reference[len(p.reference)-1]
+	c := &constraints.OPConstraints{StartID: p.start, EndID: p.end}
+	sc := score.SimpleScore{StartID: p.start, EndID: p.end}
+	if err := startSolver(solver, p, sc, c); err != nil {
+		return Itinerary{}, err
+	}
+	c.TimeLimit = c.ComputeRouteTimeFromSample(p.reference, solver.Points.GetAllPoints())
+
+	return solveRoute(ctx, solver, problem, p, c, locs)
+}
+
+// solveSingleInterval solves problems with a single route interval composed from registered components.
+func solveSingleInterval(ctx context.Context, problem Problem, names components) (Itinerary, error) {
+	if names.constraints == "" {
+		return Itinerary{}, fmt.Errorf("fops: constraints are required for problem without kind")
+	}
+	if problem.StartID == "" {
+		return Itinerary{}, fmt.Errorf("fops: %w", generic.UnknownLocationError{Role: "start"})
+	}
+	if problem.EndID == "" {
+		return Itinerary{}, fmt.Errorf("fops: %w", generic.UnknownLocationError{Role: "end"})
+	}
+
+	// points are created before locations of the problem are resolved
+	locs, err := generic.NewPoints(names.points, problem.task(positions{}))
+	if err != nil {
+		return Itinerary{}, fmt.Errorf("fops: %w", err)
+	}
+	solver, p, err := loadSolver(problem, locs)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	task := problem.task(p)
+	sc, err := generic.NewScore(names.score, task)
+	if err != nil {
+		return Itinerary{}, fmt.Errorf("fops: %w", err)
+	}
+	c, err := generic.NewConstraints(names.constraints, task)
+	if err != nil {
+		return Itinerary{}, fmt.Errorf("fops: %w", err)
+	}
+
+	if err := startSolver(solver, p, sc, c); err != nil {
+		return Itinerary{}, err
+	}
+
+	// route time and output are optional capabilities of the components
+	timer, _ := solver.Constraints.(routeTimer)
+	writer, _ := solver.Points.(routeWriter)
+	return solveRoute(ctx, solver, problem, p, timer, writer)
+}
+
+func solveRoute(ctx context.Context, solver *generic.Solver, problem Problem, p positions, timer routeTimer,
+	writer routeWriter) (Itinerary, error) {
+	if err := ctx.Err(); err != nil {
+		return Itinerary{}, err
+	}
+
+	interval, err := solveInterval(solver, p.start, p.end)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	order := []int{p.start}
+	order = append(order, interval...)
+	order = append(order, p.end)
+
+	return finish(solver, routeOf(solver, order), order, timer, writer, problem.OutputPath)
+}
+
+// solveOPCV solves Orienteering Problem with Compulsory Vertices.
+// Resulting path consists all locations from the set of compulsory locations.
+func solveOPCV(ctx context.Context, problem Problem) (Itinerary, error) {
+	if len(problem.CompulsoryLocations) < 2 {
+		return Itinerary{}, fmt.Errorf("fops: OPCV requires at least 2 compulsory locations")
+	}
+
+	locs := points.BaseLocations{}
+	solver, p, err := loadSolver(problem, locs)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	compulsoryLocations := p.compulsory
+	sc := score.SimpleScore{}
+	c := constraints.EnrichmentConstraints{
+		CompulsoryLocations: compulsoryLocations,
+		RouteTimeLimit:      problem.TimeLimit,
+	}
+	c.ForbiddenLocations = append(c.ForbiddenLocations, p.forbidden...)
+	c.ForbiddenLocations = append(c.ForbiddenLocations, compulsoryLocations...)
+
+	if err := startSolver(solver, p, sc, c); err != nil {
+		return Itinerary{}, err
+	}
+
+	order := []int{compulsoryLocations[0]}
+	for i := 0; i < len(compulsoryLocations)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return Itinerary{}, err
+		}
+
+		sc.StartID = compulsoryLocations[i]
+		sc.EndID = compulsoryLocations[i+1]
+		solver.Score = p.penalized(sc, sc.StartID, sc.EndID)
+
+		c.NumberOfInterval = i
+		solver.Constraints = p.restricted(c)
+
+		interval, err := solveInterval(solver, sc.StartID, sc.EndID)
+		if err != nil {
+			return Itinerary{}, err
+		}
+		order = append(order, interval...)
+		order = append(order, sc.EndID)
+		c.ForbiddenLocations = append(c.ForbiddenLocations, interval...)
+	}
+
+	return finish(solver, routeOf(solver, order), order, c, locs, problem.OutputPath)
+}
+
+// solveOPCVForMultipleDays solves Orienteering Problem with Compulsory Vertices.
+// The set of compulsory locations is split in predefined number of days,
+// Day of each location in resulting route is a number of the day.
+// WARNING: if all compulsory locations cannot be visited in defined time budget time budget will be expanded!
+// WARNING: if compulsory locations can be visited in less number of days the shorter route will be created.
+func solveOPCVForMultipleDays(ctx context.Context, problem Problem) (Itinerary, error) {
+	if len(problem.CompulsoryLocations) < 2 {
+		return Itinerary{}, fmt.Errorf("fops: OPCV requires at least 2 compulsory locations")
+	}
+
+	locs := points.BaseLocations{}
+	solver, p, err := loadSolver(problem, locs)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	sc := score.SimpleScore{}
+	c := constraints.MultidaysConstraints{
+		CompulsoryLocations: p.compulsory,
+		DayTimeLimit:        problem.TimeLimit,
+		DaysNumber:          problem.Days,
+	}
+	c.ForbiddenLocations = append(c.ForbiddenLocations, p.forbidden...)
+	c.ForbiddenLocations = append(c.ForbiddenLocations, p.compulsory...)
+
+	if err := startSolver(solver, p, sc, c); err != nil {
+		return Itinerary{}, err
+	}
+
+	allPoints := solver.Points.GetAllPoints()
+	route := make(map[int]generic.Point)
+	order := make([]int, 0)
+	routeTime := 0
+
+	// days are split before constraints initialization, so they need travel model of the solver
+	c.Travel = solver.Travel
+	days, times := c.SplitForDays(c.CompulsoryLocations, allPoints)
+	for day := 1; day <= c.DaysNumber; day++ {
+		if len(days[day]) == 0 {
+			continue
+		}
+		c.CurrentDay = day
+		c.TimeLimit = times[day]
+		c.CompulsoryLocations = days[day]
+
+		dayOrder := []int{days[day][0]}
+		for j := 0; j < len(days[day])-1; j++ {
+			if err := ctx.Err(); err != nil {
+				return Itinerary{}, err
+			}
+
+			sc.StartID = days[day][j]
+			sc.EndID = days[day][j+1]
+			solver.Score = p.penalized(sc, sc.StartID, sc.EndID)
+
+			c.NumberOfInterval = j
+			solver.Constraints = p.restricted(c)
+
+			interval, err := solveInterval(solver, sc.StartID, sc.EndID)
+			if err != nil {
+				return Itinerary{}, err
+			}
+			dayOrder = append(dayOrder, interval...)
+			dayOrder = append(dayOrder, sc.EndID)
+			c.ForbiddenLocations = append(c.ForbiddenLocations, interval...)
+		}
+
+		dayRoute := routeOf(solver, dayOrder)
+		routeTime += c.FinalRouteTime(dayRoute, dayOrder)
+		for key, location := range dayRoute {
+			l := *location.(*points.BaseLocation)
+			l.Day = day
+			route[key] = &l
+		}
+		order = append(order, dayOrder...)
+	}
+
+	itinerary, err := finish(solver, route, order, c, locs, problem.OutputPath)
+	itinerary.RouteTime = routeTime
+	return itinerary, err
+}
+
+// solveCityBrand solves Orienteering Problem with Functional Profits.
+// Resulting path contains locations which represent city brand, restaurants are added
+// to the end of the route and marked with interval number -1.
+func solveCityBrand(ctx context.Context, problem Problem) (Itinerary, error) {
+	dayOfWeek, err := strconv.Atoi(string(problem.DayOfWeek))
+	if err != nil {
+		return Itinerary{}, fmt.Errorf("fops: invalid day of week %q: %v", problem.DayOfWeek, err)
+	}
+
+	locs := points.CityBrandLocations{}
+	solver, p, err := loadSolver(problem, locs)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	sc := score.CityBrandScore{}
+	c := &constraints.CityBrandConstraints{
+		TimeLimit:          problem.TimeLimit - eatTime,
+		DayOfWeek:          dayOfWeek,
+		StartTime:          problem.StartTime,
+		ForbiddenLocations: p.forbidden,
+	}
+	if err := startSolver(solver, p, sc, c); err != nil {
+		return Itinerary{}, err
+	}
+
+	allPoints := solver.Points.GetAllPoints()
+	initialScore, err := sc.Init(solver)
+	if err != nil {
+		return Itinerary{}, err
+	}
+	tmpSC := initialScore.(score.CityBrandScore)
+
+	maxScore := 0.0
+	startID := 0
+	for i, location := range allPoints {
+		if contains(p.forbidden, i) || p.excluded[i] {
+			continue
+		}
+		locationScore := tmpSC.SinglePointScoreWithoutPositionDependance(location, i)
+		if locationScore >= maxScore {
+			maxScore = locationScore
+			startID = i
+		}
+	}
+
+	sc.StartID = startID
+	solver.Score = p.penalized(sc, startID, -1)
+	c.StartID = startID
+	solver.Constraints = p.restricted(c)
+
+	if err := ctx.Err(); err != nil {
+		return Itinerary{}, err
+	}
+	_, interval, _, err := solver.NextInterval()
+	if err != nil {
+		return Itinerary{}, err
+	}
+	order := []int{startID}
+	for _, k := range interval {
+		if k != startID {
+			order = append(order, k)
+		}
+	}
+	route := routeOf(solver, order)
+
+	itinerary := Itinerary{
+		Score:     solver.Score.RouteScore(route, order),
+		RouteTime: c.FinalRouteTime(route, order),
+	}
+
+	eatConstraints := &constraints.RestarauntsConstraints{
+		DayOfWeek:          dayOfWeek,
+		ForbiddenLocations: p.forbidden,
+	}
+	addRestaraunts := func(startID int, endID int, timeLimit int, startTime int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		sc.StartID = startID
+		solver.Score = p.penalized(sc, startID, endID)
+
+		eatConstraints.StartID = startID
+		eatConstraints.EndID = endID
+		eatConstraints.TimeLimit = timeLimit
+		eatConstraints.StartTime = startTime
+		solver.Constraints = p.fenced(eatConstraints)
+
+		result, restaraunts, _, err := solver.NextInterval()
+		if err != nil {
+			return err
+		}
+		for _, k := range restaraunts {
+			l := *result[k].(*points.CityBrandLocation)
+			l.IntervalNumber = -1
+			route[k] = &l
+			order = append(order, k)
+			eatConstraints.ForbiddenLocations = append(eatConstraints.ForbiddenLocations, k)
+		}
+		return nil
+	}
+
+	locationsNumber := len(order)
+	for i := 0; i < locationsNumber-1; i++ {
+		start := order[i]
+		end := order[i+1]
+		walkedTime := c.FinalRouteTime(route, order[:i+1])
+		timeLimit := eatTime + route[start].VisitDuration() + route[end].VisitDuration() + 30
+
+		err := addRestaraunts(start, end, timeLimit, eatConstraints.TimeUpdate(problem.StartTime, walkedTime))
+		if err != nil {
+			return Itinerary{}, err
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		err := addRestaraunts(order[0], order[locationsNumber-1], eatTime, problem.StartTime)
+		if err != nil {
+			return Itinerary{}, err
+		}
+	}
+
+	itinerary.Order = order
+	for _, k := range order {
+		itinerary.IDs = append(itinerary.IDs, generic.IDOf(solver.Points, k))
+		itinerary.Locations = append(itinerary.Locations, route[k])
+	}
+	if problem.OutputPath != "" {
+		if err := locs.WriteLocationsToJSON(route, order, problem.OutputPath); err != nil {
+			return itinerary, fmt.Errorf("fops: %v", err)
+		}
+	}
+	return itinerary, nil
+}
+
+// solveInterval constructs the route between startID and endID and returns
+// locations visited in between in order of visit.
+func solveInterval(solver *generic.Solver, startID int, endID int) ([]int, error) {
+	if algorithm, ok := solver.Algorithm.(initialRouteAlgorithm); ok {
+		locations := solver.Points.GetAllPoints()
+		intervalRoute := make(map[int]generic.Point)
+		intervalRoute[startID] = locations[startID]
+		intervalRoute[endID] = locations[endID]
+		solver.Algorithm = algorithm.SetInitialRoute(intervalRoute, []int{startID, endID})
+	}
+
+	_, order, _, err := solver.NextInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := make([]int, 0, len(order))
+	for _, k := range order {
+		if k != startID && k != endID {
+			interval = append(interval, k)
+		}
+	}
+	return interval, nil
+}
+
+// loadSolver loads configuration and points of the problem, resolves IDs of problem locations
+// and creates path algorithm of the resolved task. Components keep the returned solver,
+// so score and constraints are replaced in it by startSolver and solving functions.
+func loadSolver(problem Problem, locations generic.Points) (*generic.Solver, positions, error) {
+	config, err := problem.Config()
+	if err != nil {
+		return nil, positions{}, err
+	}
+	solver := &generic.Solver{Points: locations}
+	if err := solver.Load(config); err != nil {
+		return nil, positions{}, fmt.Errorf("fops: %w", err)
+	}
+	p, err := problem.resolve(solver.Points)
+	if err != nil {
+		return nil, p, fmt.Errorf("fops: %w", err)
+	}
+
+	algorithmName := problem.Algorithm
+	if algorithmName == "" {
+		algorithmName = ACO
+	}
+	if solver.Algorithm, err = generic.NewAlgorithm(algorithmName, problem.task(p)); err != nil {
+		return nil, p, fmt.Errorf("fops: %w", err)
+	}
+	return solver, p, nil
+}
+
+// startSolver starts loaded solver with score and constraints, geofences of resolved problem are
+// applied on top of them.
+func startSolver(solver *generic.Solver, p positions, sc generic.Score, c generic.Constraints) error {
+	solver.Score = p.penalized(sc, p.start, p.end)
+	solver.Constraints = p.restricted(c)
+	if err := solver.Start(solver.Configuration); err != nil {
+		return fmt.Errorf("fops: %w", err)
+	}
+	return nil
+}
+
+// restricted returns constraints which do not visit locations excluded by geofences and filters.
+func (p positions) restricted(c generic.Constraints) generic.Constraints {
+	return excluding(c, p.excluded)
+}
+
+// fenced returns constraints which do not visit locations outside of geofences, it is used for
+// restaurants which filters of sights do not apply to.
+func (p positions) fenced(c generic.Constraints) generic.Constraints {
+	return excluding(c, p.outside)
+}
+
+func excluding(c generic.Constraints, excluded map[int]bool) generic.Constraints {
+	if excluded == nil {
+		return c
+	}
+	return constraints.CandidateConstraints{Constraints: c, Excluded: excluded}
+}
+
+// penalized returns score which penalizes repeated categories and legs crossing forbidden areas on the
+// route from start to end, end is -1 for routes without fixed end.
+func (p positions) penalized(sc generic.Score, start int, end int) generic.Score {
+	if p.diversity > 0 {
+		sc = score.DiversityScore{Score: sc, Penalty: p.diversity}
+	}
+	if p.penalty == 0 {
+		return sc
+	}
+	fence := p.fence
+	return score.GeofenceScore{
+		Score:   sc,
+		StartID: start,
+		EndID:   end,
+		Penalty: p.penalty,
+		Crosses: func(from generic.Point, to generic.Point) bool {
+			lat1, lng1 := from.LatLng()
+			lat2, lng2 := to.LatLng()
+			return fence.Crosses(lat1, lng1, lat2, lng2)
+		},
+	}
+}
+
+func contains(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func routeOf(solver *generic.Solver, order []int) map[int]generic.Point {
+	allPoints := solver.Points.GetAllPoints()
+	route := make(map[int]generic.Point)
+	for _, k := range order {
+		route[k] = allPoints[k]
+	}
+	return route
+}
+
+func finish(solver *generic.Solver, route map[int]generic.Point, order []int, timer routeTimer,
+	writer routeWriter, outputPath string) (Itinerary, error) {
+	itinerary := Itinerary{
+		Order: order,
+		Score: solver.Score.RouteScore(route, order),
+	}
+	if timer != nil {
+		itinerary.RouteTime = timer.FinalRouteTime(route, order)
+	}
+	for _, k := range order {
+		itinerary.IDs = append(itinerary.IDs, generic.IDOf(solver.Points, k))
+		itinerary.Locations = append(itinerary.Locations, route[k])
+	}
+
+	if outputPath != "" {
+		if writer == nil {
+			return itinerary, fmt.Errorf("fops: points %T cannot write route", solver.Points)
+		}
+		if err := writer.WriteLocationsToJSON(route, order, outputPath); err != nil {
+			return itinerary, fmt.Errorf("fops: %v", err)
+		}
+	}
+	return itinerary, nil
+}
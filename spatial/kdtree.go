@@ -0,0 +1,221 @@
+// Package spatial provides k-d tree index of planar points for area and nearest neighbour queries.
+package spatial
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Item is an indexed point, ID is usually a position of location in dataset.
+type Item struct {
+	ID int
+	X  float64
+	Y  float64
+}
+
+// KDTree is a static 2-d tree, it is safe for concurrent queries.
+type KDTree struct {
+	nodes []kdNode
+	root  int
+}
+
+type kdNode struct {
+	item Item
+	// axis is 0 for x and 1 for y.
+	axis        int
+	left, right int
+}
+
+const none = -1
+
+// NewKDTree builds balanced tree of items.
+func NewKDTree(items []Item) *KDTree {
+	sorted := make([]Item, len(items))
+	copy(sorted, items)
+	tree := &KDTree{nodes: make([]kdNode, 0, len(items))}
+	tree.root = tree.build(sorted, 0)
+	return tree
+}
+
+func (t *KDTree) build(items []Item, depth int) int {
+	if len(items) == 0 {
+		return none
+	}
+	axis := depth % 2
+	sort.Slice(items, func(i, j int) bool {
+		return coordinate(items[i], axis) < coordinate(items[j], axis)
+	})
+	median := len(items) / 2
+
+	index := len(t.nodes)
+	t.nodes = append(t.nodes, kdNode{item: items[median], axis: axis})
+	left := t.build(items[:median], depth+1)
+	right := t.build(items[median+1:], depth+1)
+	t.nodes[index].left = left
+	t.nodes[index].right = right
+	return index
+}
+
+func coordinate(item Item, axis int) float64 {
+	if axis == 0 {
+		return item.X
+	}
+	return item.Y
+}
+
+// Len returns number of indexed items.
+func (t *KDTree) Len() int {
+	return len(t.nodes)
+}
+
+// Box returns IDs of items in the rectangle including its border, IDs are sorted.
+func (t *KDTree) Box(minX float64, minY float64, maxX float64, maxY float64) []int {
+	return t.filter(minX, minY, maxX, maxY, func(Item) bool { return true })
+}
+
+// Radius returns IDs of items within distance r from the point, IDs are sorted.
+func (t *KDTree) Radius(x float64, y float64, r float64) []int {
+	return t.filter(x-r, y-r, x+r, y+r, func(item Item) bool {
+		return math.Hypot(item.X-x, item.Y-y) <= r
+	})
+}
+
+// Ellipse returns IDs of items with sum of distances to foci not greater than sum, IDs are sorted.
+func (t *KDTree) Ellipse(x1 float64, y1 float64, x2 float64, y2 float64, sum float64) []int {
+	// ellipse lies inside of circle around center with radius of semi-major axis
+	centerX, centerY := (x1+x2)/2, (y1+y2)/2
+	semiMajor := sum / 2
+	return t.filter(centerX-semiMajor, centerY-semiMajor, centerX+semiMajor, centerY+semiMajor, func(item Item) bool {
+		return math.Hypot(item.X-x1, item.Y-y1)+math.Hypot(item.X-x2, item.Y-y2) <= sum
+	})
+}
+
+// Polygon returns IDs of items inside of polygon given by vertices, IDs are sorted.
+// Polygon is closed automatically, points on the border may be included or not.
+func (t *KDTree) Polygon(vertices [][2]float64) []int {
+	if len(vertices) < 3 {
+		return []int{}
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, v := range vertices {
+		minX, maxX = math.Min(minX, v[0]), math.Max(maxX, v[0])
+		minY, maxY = math.Min(minY, v[1]), math.Max(maxY, v[1])
+	}
+	return t.filter(minX, minY, maxX, maxY, func(item Item) bool {
+		return InsidePolygon(vertices, item.X, item.Y)
+	})
+}
+
+// InsidePolygon uses ray casting: ray from the point crosses border odd number of times if point is inside.
+func InsidePolygon(vertices [][2]float64, x float64, y float64) bool {
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		xi, yi := vertices[i][0], vertices[i][1]
+		xj, yj := vertices[j][0], vertices[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// filter returns sorted IDs of items in the rectangle which satisfy condition.
+func (t *KDTree) filter(minX float64, minY float64, maxX float64, maxY float64, condition func(Item) bool) []int {
+	ids := make([]int, 0)
+	var visit func(index int)
+	visit = func(index int) {
+		if index == none {
+			return
+		}
+		node := t.nodes[index]
+		item := node.item
+		if item.X >= minX && item.X <= maxX && item.Y >= minY && item.Y <= maxY && condition(item) {
+			ids = append(ids, item.ID)
+		}
+		low, high := minX, maxX
+		if node.axis == 1 {
+			low, high = minY, maxY
+		}
+		if low <= coordinate(item, node.axis) {
+			visit(node.left)
+		}
+		if high >= coordinate(item, node.axis) {
+			visit(node.right)
+		}
+	}
+	visit(t.root)
+	sort.Ints(ids)
+	return ids
+}
+
+// Nearest returns IDs of k items closest to the point, the closest is first.
+// Items with equal distance are ordered by ID.
+func (t *KDTree) Nearest(x float64, y float64, k int) []int {
+	if k <= 0 {
+		return []int{}
+	}
+	found := &neighbours{}
+	var visit func(index int)
+	visit = func(index int) {
+		if index == none {
+			return
+		}
+		node := t.nodes[index]
+		candidate := neighbour{node.item.ID, math.Hypot(node.item.X-x, node.item.Y-y)}
+		if found.Len() < k {
+			heap.Push(found, candidate)
+		} else if candidate.closer((*found)[0]) {
+			(*found)[0] = candidate
+			heap.Fix(found, 0)
+		}
+
+		delta := coordinate(Item{X: x, Y: y}, node.axis) - coordinate(node.item, node.axis)
+		near, far := node.left, node.right
+		if delta > 0 {
+			near, far = far, near
+		}
+		visit(near)
+		// the other side may contain closer items only if splitting line is closer than the farthest found item
+		if found.Len() < k || math.Abs(delta) <= (*found)[0].distance {
+			visit(far)
+		}
+	}
+	visit(t.root)
+
+	result := make([]neighbour, found.Len())
+	copy(result, *found)
+	sort.Slice(result, func(i, j int) bool { return result[i].closer(result[j]) })
+	ids := make([]int, len(result))
+	for i, n := range result {
+		ids[i] = n.id
+	}
+	return ids
+}
+
+type neighbour struct {
+	id       int
+	distance float64
+}
+
+func (n neighbour) closer(other neighbour) bool {
+	if n.distance != other.distance {
+		return n.distance < other.distance
+	}
+	return n.id < other.id
+}
+
+// neighbours is a max-heap by distance, the farthest found item is on top.
+type neighbours []neighbour
+
+func (h neighbours) Len() int            { return len(h) }
+func (h neighbours) Less(i, j int) bool  { return h[j].closer(h[i]) }
+func (h neighbours) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighbours) Push(x interface{}) { *h = append(*h, x.(neighbour)) }
+func (h *neighbours) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,96 @@
+package spatial
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// randomItems returns items on a grid of 10 meters, so some items share coordinates and distances.
+func randomItems(n int, seed int64) []Item {
+	random := rand.New(rand.NewSource(seed))
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = Item{ID: i, X: float64(random.Intn(100)) * 10, Y: float64(random.Intn(100)) * 10}
+	}
+	return items
+}
+
+// bruteForce returns sorted IDs of items which satisfy condition.
+func bruteForce(items []Item, condition func(Item) bool) []int {
+	ids := make([]int, 0)
+	for _, item := range items {
+		if condition(item) {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func TestKDTreeQueries(t *testing.T) {
+	items := randomItems(500, 1)
+	tree := NewKDTree(items)
+	if tree.Len() != len(items) {
+		t.Fatalf("Len = %d, want %d", tree.Len(), len(items))
+	}
+	triangle := [][2]float64{{100, 100}, {900, 150}, {400, 800}}
+	tests := []struct {
+		name string
+		got  []int
+		want func(Item) bool
+	}{
+		{"box", tree.Box(200, 300, 450, 700), func(i Item) bool {
+			return i.X >= 200 && i.X <= 450 && i.Y >= 300 && i.Y <= 700
+		}},
+		{"empty box", tree.Box(2000, 2000, 3000, 3000), func(i Item) bool { return false }},
+		{"radius", tree.Radius(500, 500, 120), func(i Item) bool { return math.Hypot(i.X-500, i.Y-500) <= 120 }},
+		{"ellipse", tree.Ellipse(200, 200, 700, 600, 800), func(i Item) bool {
+			return math.Hypot(i.X-200, i.Y-200)+math.Hypot(i.X-700, i.Y-600) <= 800
+		}},
+		{"polygon", tree.Polygon(triangle), func(i Item) bool { return InsidePolygon(triangle, i.X, i.Y) }},
+	}
+	for _, test := range tests {
+		if want := bruteForce(items, test.want); !reflect.DeepEqual(test.got, want) {
+			t.Errorf("%s: got %d items %v, want %d items %v", test.name, len(test.got), test.got, len(want), want)
+		}
+	}
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	items := randomItems(300, 2)
+	tree := NewKDTree(items)
+	tests := []struct {
+		x, y float64
+		k    int
+	}{
+		{500, 500, 1}, {0, 0, 5}, {995, 5, 20}, {-100, 2000, 3}, {333, 333, 300}, {333, 333, 1000}, {1, 1, 0},
+	}
+	for _, test := range tests {
+		want := append([]Item(nil), items...)
+		sort.SliceStable(want, func(a, b int) bool {
+			da := math.Hypot(want[a].X-test.x, want[a].Y-test.y)
+			db := math.Hypot(want[b].X-test.x, want[b].Y-test.y)
+			return da < db || (da == db && want[a].ID < want[b].ID)
+		})
+		k := test.k
+		if k > len(want) {
+			k = len(want)
+		}
+		wantIDs := make([]int, 0, k)
+		for _, item := range want[:k] {
+			wantIDs = append(wantIDs, item.ID)
+		}
+		if got := tree.Nearest(test.x, test.y, test.k); !reflect.DeepEqual(got, wantIDs) && !(len(got) == 0 && k == 0) {
+			t.Errorf("Nearest(%v, %v, %d) = %v, want %v", test.x, test.y, test.k, got, wantIDs)
+		}
+	}
+}
+
+func TestEmptyKDTree(t *testing.T) {
+	tree := NewKDTree(nil)
+	if tree.Len() != 0 || len(tree.Radius(0, 0, 100)) != 0 || len(tree.Nearest(0, 0, 3)) != 0 {
+		t.Errorf("empty tree returns items")
+	}
+}
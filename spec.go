@@ -0,0 +1,95 @@
+package fops
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mukhinaks/fops/misc"
+)
+
+// Weekday is a key of location open hours. It can be written both as a string and as a number in specification files.
+type Weekday string
+
+// UnmarshalJSON accepts both "0" and 0.
+func (day *Weekday) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		*day = Weekday(v)
+	case float64:
+		*day = Weekday(strconv.Itoa(int(v)))
+	case nil:
+		*day = ""
+	default:
+		return fmt.Errorf("fops: invalid day of week %s", data)
+	}
+	return nil
+}
+
+// LoadProblem reads problem specification from JSON or YAML file.
+// The format is chosen by file extension: .yaml and .yml files are read as YAML, others as JSON.
+// Paths inside specification are relative to the working directory.
+func LoadProblem(path string) (Problem, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	format := "json"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+
+	problem, err := ParseProblem(data, format)
+	if err != nil {
+		return Problem{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return problem, nil
+}
+
+// ParseProblem decodes problem specification in "json" or "yaml" format.
+func ParseProblem(data []byte, format string) (Problem, error) {
+	switch format {
+	case "json":
+	case "yaml":
+		values, err := misc.ReadYAML(data)
+		if err != nil {
+			return Problem{}, err
+		}
+		// settings are text of configuration values, YAML writes them as numbers and booleans
+		if settings, ok := values["settings"].(map[string]interface{}); ok {
+			for key, value := range settings {
+				if value != nil {
+					settings[key] = fmt.Sprint(value)
+				}
+			}
+		}
+		data, err = json.Marshal(values)
+		if err != nil {
+			return Problem{}, err
+		}
+	default:
+		return Problem{}, fmt.Errorf("fops: unknown specification format %q", format)
+	}
+
+	problem := Problem{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&problem); err != nil {
+		return Problem{}, err
+	}
+	if problem.Kind == "" && problem.Constraints == "" {
+		return Problem{}, fmt.Errorf("fops: problem kind or constraints are not specified")
+	}
+	return problem, nil
+}
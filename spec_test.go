@@ -0,0 +1,55 @@
+package fops
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+func TestParseProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		text   string
+		want   Problem
+	}{
+		{"json", "json", `{"kind": "op", "start_id": 9007199254740993, "settings": {"Iterations": "2"}}`,
+			Problem{Kind: OP, StartID: "9007199254740993", Settings: map[string]string{"Iterations": "2"}}},
+		{"yaml", "yaml", "kind: op\nstart_id: 9007199254740993\nend_id: museum-1\n" +
+			"compulsory_locations: [1, 2]\nsettings:\n  Iterations: 2\n  Fadeness: 0.5\n  DataImputeDurations: true\n",
+			Problem{Kind: OP, StartID: "9007199254740993", EndID: "museum-1",
+				CompulsoryLocations: []generic.LocationID{"1", "2"},
+				Settings:            map[string]string{"Iterations": "2", "Fadeness": "0.5", "DataImputeDurations": "true"}}},
+	}
+	for _, test := range tests {
+		got, err := ParseProblem([]byte(test.text), test.format)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ParseProblem = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseProblemErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		text   string
+		err    string
+	}{
+		{"yaml", "start_id: 1\n", "problem kind or constraints are not specified"},
+		{"yaml", "kind: op\nsettings:\n  Iterations:\n    Value: 2\n", "nested deeper than one level"},
+		{"yaml", "kind: op\nunknown: 1\n", `unknown field "unknown"`},
+		{"xml", "<problem/>", `unknown specification format "xml"`},
+	}
+	for _, test := range tests {
+		_, err := ParseProblem([]byte(test.text), test.format)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseProblem(%q) error = %v, want %q", test.text, err, test.err)
+		}
+	}
+}
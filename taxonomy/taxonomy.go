@@ -0,0 +1,165 @@
+// Package taxonomy maps categories of data sources to canonical categories, so category based logic works
+// the same for datasets of different cities and providers. Canonical categories are hierarchical paths
+// separated by "/", e.g. "culture/museums" is a subcategory of "culture".
+//
+// Taxonomy file maps every canonical category to source categories, in JSON
+//
+//	{"culture/museums": ["Museums & Libraries", "tourism=museum"], "food/restaurants": ["Restaurant"]}
+//
+// or in YAML with the same keys and lists.
+package taxonomy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mukhinaks/fops/misc"
+)
+
+// Separator separates levels of canonical categories.
+const Separator = "/"
+
+// Taxonomy maps source categories to canonical categories.
+type Taxonomy struct {
+	// canonical maps source categories and canonical categories with their ancestors in lower case
+	// to canonical categories.
+	canonical map[string]string
+}
+
+// New returns taxonomy of mapping from canonical categories to source categories.
+func New(mapping map[string][]string) (*Taxonomy, error) {
+	t := &Taxonomy{canonical: make(map[string]string)}
+	categories := make([]string, 0, len(mapping))
+	for category := range mapping {
+		categories = append(categories, category)
+	}
+	// categories are sorted, so errors do not depend on order of map
+	sort.Strings(categories)
+	for _, category := range categories {
+		if !valid(category) {
+			return nil, fmt.Errorf("invalid canonical category %q", category)
+		}
+		for _, level := range append([]string{category}, Ancestors(category)...) {
+			t.canonical[key(level)] = level
+		}
+	}
+	for _, category := range categories {
+		for _, source := range mapping[category] {
+			if strings.TrimSpace(source) == "" {
+				return nil, fmt.Errorf("empty source category of %q", category)
+			}
+			if mapped, ok := t.canonical[key(source)]; ok && mapped != category {
+				return nil, fmt.Errorf("source category %q is mapped to %q and %q", source, mapped, category)
+			}
+			t.canonical[key(source)] = category
+		}
+	}
+	return t, nil
+}
+
+// Read reads taxonomy file, files with .yaml and .yml extensions are YAML, others are JSON.
+func Read(path string) (*Taxonomy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("taxonomy: %v", err)
+	}
+	mapping := make(map[string][]string)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var document map[string]interface{}
+		if document, err = misc.ReadYAML(data); err == nil {
+			err = fromYAML(document, mapping)
+		}
+	default:
+		err = json.Unmarshal(data, &mapping)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("taxonomy: %s: %v", path, err)
+	}
+	t, err := New(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("taxonomy: %s: %v", path, err)
+	}
+	return t, nil
+}
+
+func fromYAML(document map[string]interface{}, mapping map[string][]string) error {
+	for category, value := range document {
+		switch value := value.(type) {
+		case []interface{}:
+			for _, source := range value {
+				text, ok := source.(string)
+				if !ok {
+					return fmt.Errorf("%s: source categories should be strings, got %v", category, source)
+				}
+				mapping[category] = append(mapping[category], text)
+			}
+		case string:
+			mapping[category] = []string{value}
+		case nil:
+			mapping[category] = nil
+		default:
+			return fmt.Errorf("%s: list of source categories expected, got %v", category, value)
+		}
+	}
+	return nil
+}
+
+// Canonical returns canonical category of source category, categories are compared ignoring case.
+// Canonical categories and their ancestors are mapped to themselves.
+func (t *Taxonomy) Canonical(category string) (string, bool) {
+	canonical, ok := t.canonical[key(category)]
+	return canonical, ok
+}
+
+// Normalize returns canonical categories without repetitions in order of source categories. Unmapped
+// categories are kept as they are and returned as unmapped.
+func (t *Taxonomy) Normalize(categories []string) (normalized []string, unmapped []string) {
+	normalized = make([]string, 0, len(categories))
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		canonical, ok := t.Canonical(category)
+		if !ok {
+			canonical = category
+			unmapped = append(unmapped, category)
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			normalized = append(normalized, canonical)
+		}
+	}
+	return normalized, unmapped
+}
+
+// Matches tells if category is ancestor or ancestor's subcategory, e.g. "culture/museums" matches "culture".
+func Matches(category string, ancestor string) bool {
+	return category == ancestor || strings.HasPrefix(category, ancestor+Separator)
+}
+
+// Ancestors returns ancestors of category from the parent to the root, e.g. "culture" of "culture/museums".
+func Ancestors(category string) []string {
+	ancestors := make([]string, 0)
+	for i := strings.LastIndex(category, Separator); i > 0; i = strings.LastIndex(category, Separator) {
+		category = category[:i]
+		ancestors = append(ancestors, category)
+	}
+	return ancestors
+}
+
+// valid tells if category has no empty levels.
+func valid(category string) bool {
+	for _, level := range strings.Split(category, Separator) {
+		if strings.TrimSpace(level) == "" || strings.TrimSpace(level) != level {
+			return false
+		}
+	}
+	return true
+}
+
+func key(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
@@ -0,0 +1,170 @@
+package taxonomy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testMapping = map[string][]string{
+	"culture/museums":  {"Museums & Libraries", "tourism=museum"},
+	"food/restaurants": {"Restaurant", "amenity=restaurant"},
+	"food/cafes":       {"amenity=cafe"},
+}
+
+func TestCanonical(t *testing.T) {
+	taxonomy, err := New(testMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		category  string
+		canonical string
+		ok        bool
+	}{
+		{"Restaurant", "food/restaurants", true},
+		{" restaurant ", "food/restaurants", true},
+		{"TOURISM=MUSEUM", "culture/museums", true},
+		{"food/cafes", "food/cafes", true},
+		{"Food", "food", true},
+		{"culture", "culture", true},
+		{"Bar", "", false},
+		{"food/bars", "", false},
+	}
+	for _, test := range tests {
+		canonical, ok := taxonomy.Canonical(test.category)
+		if canonical != test.canonical || ok != test.ok {
+			t.Errorf("Canonical(%q) = %q, %v, want %q, %v", test.category, canonical, ok, test.canonical, test.ok)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	taxonomy, err := New(testMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		categories []string
+		normalized []string
+		unmapped   []string
+	}{
+		{nil, []string{}, nil},
+		{[]string{"Restaurant", "amenity=cafe"}, []string{"food/restaurants", "food/cafes"}, nil},
+		{[]string{"Restaurant", "amenity=restaurant", "food/restaurants"}, []string{"food/restaurants"}, nil},
+		{[]string{"Bar", "tourism=museum", "Bar"}, []string{"Bar", "culture/museums"}, []string{"Bar", "Bar"}},
+	}
+	for _, test := range tests {
+		normalized, unmapped := taxonomy.Normalize(test.categories)
+		if !reflect.DeepEqual(normalized, test.normalized) || !reflect.DeepEqual(unmapped, test.unmapped) {
+			t.Errorf("Normalize(%q) = %q, %q, want %q, %q",
+				test.categories, normalized, unmapped, test.normalized, test.unmapped)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		category, ancestor string
+		want               bool
+	}{
+		{"food/restaurants", "food/restaurants", true},
+		{"food/restaurants", "food", true},
+		{"food/restaurants/sushi", "food/restaurants", true},
+		{"food/cafes", "food/restaurants", false},
+		{"food", "food/restaurants", false},
+		{"foodcourt", "food", false},
+		{"Restaurant", "Restaurant", true},
+		{"Restaurant", "restaurant", false},
+	}
+	for _, test := range tests {
+		if got := Matches(test.category, test.ancestor); got != test.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", test.category, test.ancestor, got, test.want)
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	tests := []struct {
+		category  string
+		ancestors []string
+	}{
+		{"food", []string{}},
+		{"food/restaurants", []string{"food"}},
+		{"sights/historic/castles", []string{"sights/historic", "sights"}},
+	}
+	for _, test := range tests {
+		if got := Ancestors(test.category); !reflect.DeepEqual(got, test.ancestors) {
+			t.Errorf("Ancestors(%q) = %q, want %q", test.category, got, test.ancestors)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		mapping map[string][]string
+		err     string
+	}{
+		{map[string][]string{"food//cafes": nil}, `invalid canonical category "food//cafes"`},
+		{map[string][]string{"/food": nil}, `invalid canonical category "/food"`},
+		{map[string][]string{"food/ cafes": nil}, `invalid canonical category "food/ cafes"`},
+		{map[string][]string{"food/cafes": {" "}}, `empty source category of "food/cafes"`},
+		{map[string][]string{"food/bars": {"Pub"}, "food/cafes": {"pub"}},
+			`source category "pub" is mapped to "food/bars" and "food/cafes"`},
+		{map[string][]string{"food/cafes": {"culture"}, "culture/museums": nil},
+			`source category "culture" is mapped to "culture" and "food/cafes"`},
+	}
+	for _, test := range tests {
+		_, err := New(test.mapping)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("New(%v) error = %v, want %q", test.mapping, err, test.err)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fops-taxonomy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{"taxonomy.json", `{"food/restaurants": ["Restaurant"], "food/cafes": ["amenity=cafe"]}`, ""},
+		{"taxonomy.yaml", "food/restaurants:\n  - Restaurant\nfood/cafes: amenity=cafe\nfood/bars:\n", ""},
+		{"taxonomy.YML", "food/restaurants: [Restaurant]\nfood/cafes: [amenity=cafe]\n", ""},
+		{"list.json", `["Restaurant"]`, "cannot unmarshal array"},
+		{"numbers.yaml", "food/restaurants:\n  - 1\n", "food/restaurants: source categories should be strings, got 1"},
+		{"number.yaml", "food/restaurants: 5\n", "food/restaurants: list of source categories expected, got 5"},
+		{"invalid.json", `{"food/": ["Restaurant"]}`, `invalid canonical category "food/"`},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		if err := ioutil.WriteFile(path, []byte(test.text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		taxonomy, err := Read(path)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) || !strings.HasPrefix(err.Error(), "taxonomy: "+path) {
+				t.Errorf("Read(%s) error = %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Read(%s): unexpected error %v", test.name, err)
+			continue
+		}
+		if canonical, _ := taxonomy.Canonical("amenity=cafe"); canonical != "food/cafes" {
+			t.Errorf("Read(%s): amenity=cafe is mapped to %q, want food/cafes", test.name, canonical)
+		}
+	}
+	if _, err := Read(filepath.Join(dir, "missing.json")); err == nil || !strings.HasPrefix(err.Error(), "taxonomy: ") {
+		t.Errorf("Read of missing file error = %v", err)
+	}
+}
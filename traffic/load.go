@@ -0,0 +1,141 @@
+package traffic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+// profileFile is a JSON format of speed profiles:
+//
+//	{
+//	  "slot_minutes": 60,
+//	  "zones": [{"name": "center", "polygon": [[59.93, 30.30], [59.95, 30.30], [59.95, 30.35]]}],
+//	  "profiles": [
+//	    {"speeds": [1, 1, ...]},
+//	    {"zone": "center", "weekday": "1", "speeds": [1, 0.9, ...]},
+//	    {"from": 3, "to": "node/17", "speeds": [1, 0.7, ...]}
+//	  ]
+//	}
+//
+// Every profile has a speed for each slot of the day, profile without zone and edge is used for the whole city,
+// profile without weekday is used for all days. Edges are directed and given by IDs of dataset locations,
+// locations without IDs are identified by their positions.
+type profileFile struct {
+	SlotMinutes int       `json:"slot_minutes"`
+	Zones       []Zone    `json:"zones"`
+	Profiles    []profile `json:"profiles"`
+}
+
+type profile struct {
+	Zone    string              `json:"zone"`
+	From    *generic.LocationID `json:"from"`
+	To      *generic.LocationID `json:"to"`
+	Weekday string              `json:"weekday"`
+	Speeds  []float64           `json:"speeds"`
+}
+
+// Load reads speed profiles from JSON file.
+func Load(path string) (*Profile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("traffic: %v", err)
+	}
+	defer file.Close()
+
+	p, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, path)
+	}
+	return p, nil
+}
+
+// Read reads speed profiles in JSON format and checks them.
+func Read(r io.Reader) (*Profile, error) {
+	var data profileFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("traffic: %v", err)
+	}
+	if data.SlotMinutes <= 0 || minutesPerDay%data.SlotMinutes != 0 {
+		return nil, fmt.Errorf("traffic: slot_minutes %d should divide a day", data.SlotMinutes)
+	}
+
+	p := &Profile{
+		SlotMinutes: data.SlotMinutes,
+		Zones:       data.Zones,
+		edges:       make(map[edgeKey][]float64),
+		zones:       make(map[zoneKey][]float64),
+		defaults:    make(map[string][]float64),
+	}
+	zones := make(map[string]int)
+	for i, zone := range data.Zones {
+		if _, ok := zones[zone.Name]; ok {
+			return nil, fmt.Errorf("traffic: zone %q is duplicated", zone.Name)
+		}
+		if len(zone.Polygon) < 3 {
+			return nil, fmt.Errorf("traffic: zone %q should have at least 3 vertices", zone.Name)
+		}
+		zones[zone.Name] = i
+	}
+
+	slots := minutesPerDay / data.SlotMinutes
+	for i, entry := range data.Profiles {
+		if err := entry.check(slots); err != nil {
+			return nil, fmt.Errorf("traffic: profile %d: %v", i, err)
+		}
+
+		duplicated := false
+		switch {
+		case entry.From != nil:
+			key := edgeKey{*entry.From, *entry.To, entry.Weekday}
+			_, duplicated = p.edges[key]
+			p.edges[key] = entry.Speeds
+		case entry.Zone != "":
+			zone, ok := zones[entry.Zone]
+			if !ok {
+				return nil, fmt.Errorf("traffic: profile %d: unknown zone %q", i, entry.Zone)
+			}
+			key := zoneKey{zone, entry.Weekday}
+			_, duplicated = p.zones[key]
+			p.zones[key] = entry.Speeds
+		default:
+			_, duplicated = p.defaults[entry.Weekday]
+			p.defaults[entry.Weekday] = entry.Speeds
+		}
+		if duplicated {
+			return nil, fmt.Errorf("traffic: profile %d: duplicates previous profile", i)
+		}
+	}
+	return p, nil
+}
+
+func (entry profile) check(slots int) error {
+	if (entry.From == nil) != (entry.To == nil) {
+		return fmt.Errorf("edge needs both from and to")
+	}
+	if entry.From != nil && entry.Zone != "" {
+		return fmt.Errorf("edge should not have zone")
+	}
+	if entry.Weekday != "" {
+		day, err := strconv.Atoi(entry.Weekday)
+		if err != nil || day < 0 || day > 6 {
+			return fmt.Errorf("invalid weekday %q, \"0\" is Sunday", entry.Weekday)
+		}
+	}
+	if len(entry.Speeds) != slots {
+		return fmt.Errorf("has %d speeds, %d slots expected", len(entry.Speeds), slots)
+	}
+	for _, speed := range entry.Speeds {
+		// positive speeds keep FIFO property
+		if !(speed > 0) {
+			return fmt.Errorf("speeds should be positive")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package traffic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// speeds of 4 slots of 6 hours.
+const speeds = `[1, 0.5, 1, 1]`
+
+func TestRead(t *testing.T) {
+	p, err := Read(strings.NewReader(`{"slot_minutes": 360,
+		"zones": [{"name": "center", "polygon": [[0, 0], [0, 1], [1, 1]]}],
+		"profiles": [{"speeds": ` + speeds + `}, {"zone": "center", "weekday": "3", "speeds": ` + speeds + `},
+			{"from": 3, "to": "node/17", "speeds": ` + speeds + `}, {"from": "3", "to": 4, "speeds": ` + speeds + `}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SlotMinutes != 360 || len(p.Zones) != 1 || len(p.defaults) != 1 || len(p.zones) != 1 {
+		t.Errorf("profile %+v", p)
+	}
+	// integer and string IDs are the same
+	for _, key := range []edgeKey{{"3", "node/17", ""}, {"3", "4", ""}} {
+		if _, ok := p.edges[key]; !ok {
+			t.Errorf("edge %v is missing in %v", key, p.edges)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		err     string
+	}{
+		{"malformed", `{"slot_minutes": 60,`, "traffic: unexpected EOF"},
+		{"unknown field", `{"slot_minutes": 360, "speed": []}`, `traffic: json: unknown field "speed"`},
+		{"slot does not divide day", `{"slot_minutes": 7, "profiles": []}`, "traffic: slot_minutes 7 should divide a day"},
+		{"no slot", `{"profiles": []}`, "traffic: slot_minutes 0 should divide a day"},
+		{"duplicated zone", `{"slot_minutes": 360, "zones": [{"name": "a", "polygon": [[0, 0], [0, 1], [1, 1]]},
+			{"name": "a", "polygon": [[0, 0], [0, 1], [1, 1]]}]}`, `traffic: zone "a" is duplicated`},
+		{"small zone", `{"slot_minutes": 360, "zones": [{"name": "a", "polygon": [[0, 0], [0, 1]]}]}`,
+			`traffic: zone "a" should have at least 3 vertices`},
+		{"speeds number", `{"slot_minutes": 360, "profiles": [{"speeds": [1, 1]}]}`,
+			"traffic: profile 0: has 2 speeds, 4 slots expected"},
+		{"zero speed", `{"slot_minutes": 360, "profiles": [{"speeds": [1, 0, 1, 1]}]}`,
+			"traffic: profile 0: speeds should be positive"},
+		{"negative speed", `{"slot_minutes": 360, "profiles": [{"speeds": [1, -1, 1, 1]}]}`,
+			"traffic: profile 0: speeds should be positive"},
+		{"invalid weekday", `{"slot_minutes": 360, "profiles": [{"weekday": "7", "speeds": ` + speeds + `}]}`,
+			`traffic: profile 0: invalid weekday "7", "0" is Sunday`},
+		{"edge without end", `{"slot_minutes": 360, "profiles": [{"from": 1, "speeds": ` + speeds + `}]}`,
+			"traffic: profile 0: edge needs both from and to"},
+		{"edge with zone", `{"slot_minutes": 360, "zones": [{"name": "a", "polygon": [[0, 0], [0, 1], [1, 1]]}],
+			"profiles": [{"from": 1, "to": 2, "zone": "a", "speeds": ` + speeds + `}]}`,
+			"traffic: profile 0: edge should not have zone"},
+		{"invalid ID", `{"slot_minutes": 360, "profiles": [{"from": 1.5, "to": 2, "speeds": ` + speeds + `}]}`,
+			"location id 1.5 should be an integer or a string"},
+		{"unknown zone", `{"slot_minutes": 360, "profiles": [{"zone": "b", "speeds": ` + speeds + `}]}`,
+			`traffic: profile 0: unknown zone "b"`},
+		{"duplicated city profile", `{"slot_minutes": 360, "profiles": [{"speeds": ` + speeds + `},
+			{"speeds": ` + speeds + `}]}`, "traffic: profile 1: duplicates previous profile"},
+		{"duplicated edge", `{"slot_minutes": 360, "profiles": [{"from": 1, "to": "a", "speeds": ` + speeds + `},
+			{"from": "1", "to": "a", "speeds": ` + speeds + `}]}`, "traffic: profile 1: duplicates previous profile"},
+	}
+	for _, test := range tests {
+		_, err := Read(strings.NewReader(test.profile))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traffic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profile.json")
+	if err := ioutil.WriteFile(path, []byte(`{"slot_minutes": 360, "profiles": [{"speeds": [1, 1]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil || err.Error() != "traffic: profile 0: has 2 speeds, 4 slots expected: "+path {
+		t.Errorf("Load error %v", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil || !strings.HasPrefix(err.Error(), "traffic: ") {
+		t.Errorf("Load error of missing file %v", err)
+	}
+}
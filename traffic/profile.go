@@ -0,0 +1,127 @@
+// Package traffic describes how travel speed changes during the day and computes time-dependent travel times.
+package traffic
+
+import (
+	"math"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+// minutesPerDay is a period of profiles, the same speeds repeat every day.
+const minutesPerDay = 24 * 60
+
+// Profile keeps relative speeds by time slots of the day. Speed 1 means travel time of the travel model,
+// speed 0.5 doubles it. Speeds are given for edges between two locations, for zones where locations
+// are and for the whole city, each of them for a day of week or for all days.
+type Profile struct {
+	// SlotMinutes is a length of time slot, e.g. 60 or 15.
+	SlotMinutes int
+	Zones       []Zone
+
+	edges    map[edgeKey][]float64
+	zones    map[zoneKey][]float64
+	defaults map[string][]float64
+}
+
+// Zone is an area given by polygon of geographic coordinates, vertices are [lat, lng].
+type Zone struct {
+	Name    string       `json:"name"`
+	Polygon [][2]float64 `json:"polygon"`
+}
+
+type edgeKey struct {
+	from, to generic.LocationID
+	weekday  string
+}
+
+type zoneKey struct {
+	zone    int
+	weekday string
+}
+
+// ZoneOf returns position of the first zone containing location or -1 if it is outside of all zones.
+func (p *Profile) ZoneOf(lat float64, lng float64) int {
+	for i, zone := range p.Zones {
+		if spatial.InsidePolygon(zone.Polygon, lat, lng) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Speeds returns speeds of the leg between locations with IDs, zone is a zone of destination.
+// The most specific profile is used: edge, zone, then the whole city, profile of the day of week
+// is preferred to the profile of all days. Nil is returned if speed does not change.
+func (p *Profile) Speeds(from generic.LocationID, to generic.LocationID, zone int, weekday string) []float64 {
+	for _, day := range []string{weekday, ""} {
+		if speeds, ok := p.edges[edgeKey{from, to, day}]; ok {
+			return speeds
+		}
+	}
+	if zone >= 0 {
+		for _, day := range []string{weekday, ""} {
+			if speeds, ok := p.zones[zoneKey{zone, day}]; ok {
+				return speeds
+			}
+		}
+	}
+	for _, day := range []string{weekday, ""} {
+		if speeds, ok := p.defaults[day]; ok {
+			return speeds
+		}
+	}
+	return nil
+}
+
+// TravelTime returns travel time in minutes for departure at minutes since midnight. Duration is
+// travel time with speed 1, to is a destination location in zone, it may be -1 if location has no zone.
+func (p *Profile) TravelTime(duration int, from generic.LocationID, to generic.LocationID, zone int, weekday string,
+	departure int) int {
+	speeds := p.Speeds(from, to, zone, weekday)
+	if speeds == nil || duration <= 0 {
+		return duration
+	}
+	arrival := Arrival(speeds, p.SlotMinutes, float64(departure), float64(duration))
+	// rounding is monotonic, so later departure still does not arrive earlier
+	return int(math.Round(arrival)) - departure
+}
+
+// Arrival returns arrival time for departure at minutes since midnight, duration is travel time with speed 1.
+// Speed is interpolated linearly between centers of slots and integrated along the way, so travel
+// which starts later always arrives later or at the same time (FIFO property) if all speeds are positive.
+func Arrival(speeds []float64, slotMinutes int, departure float64, duration float64) float64 {
+	slot := float64(slotMinutes)
+	remaining := duration
+	t := departure
+	for remaining > 0 {
+		// segment between centers of slots k and k+1 contains t
+		k := math.Floor((t - slot/2) / slot)
+		start := k*slot + slot/2
+		end := start + slot
+		first := speeds[wrap(int(k), len(speeds))]
+		second := speeds[wrap(int(k)+1, len(speeds))]
+
+		slope := (second - first) / slot
+		speed := first + slope*(t-start)
+		length := end - t
+		covered := speed*length + slope*length*length/2
+		if covered >= remaining {
+			// solution of slope/2*x^2 + speed*x = remaining in numerically stable form
+			discriminant := math.Max(speed*speed+2*slope*remaining, 0)
+			return t + 2*remaining/(speed+math.Sqrt(discriminant))
+		}
+		remaining -= covered
+		t = end
+	}
+	return t
+}
+
+// wrap returns position of slot of the periodic day.
+func wrap(i int, n int) int {
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
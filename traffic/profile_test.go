@@ -0,0 +1,119 @@
+package traffic
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mukhinaks/fops/generic"
+)
+
+func TestArrival(t *testing.T) {
+	tests := []struct {
+		name        string
+		speeds      []float64
+		slotMinutes int
+		departure   float64
+		duration    float64
+		want        float64
+	}{
+		{"constant speed", []float64{1, 1, 1, 1}, 360, 600, 30, 630},
+		{"half speed", []float64{0.5, 0.5}, 720, 100, 30, 160},
+		{"zero duration", []float64{0.5, 0.5}, 720, 100, 0, 100},
+		// speed grows from 1 at 30 to 2 at 90: covered distance is 60 + 60*60/120 = 90
+		{"across slot center", []float64{1, 2, 1, 1}, 60, 30, 90, 90},
+		// speed falls from 1 at 0:30 to 0.5 at 1:30 covering 45, then 60 is covered at speed 0.5 in 120 minutes
+		{"across several slots", append(append([]float64{1}, repeat(0.5, 22)...), 1), 60, 30, 45 + 60, 210},
+		// the day is periodic: speed falls from 3 at 23:30 to 1 at 0:30, -x*x/60 + 3*x = 60
+		{"across midnight", append(repeat(1, 23), 3), 60, 1410, 60, 1500 - math.Sqrt(4500)},
+		{"next day", []float64{2, 2}, 720, 1430, 60, 1460},
+	}
+	for _, test := range tests {
+		got := Arrival(test.speeds, test.slotMinutes, test.departure, test.duration)
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: Arrival = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func repeat(speed float64, n int) []float64 {
+	speeds := make([]float64, n)
+	for i := range speeds {
+		speeds[i] = speed
+	}
+	return speeds
+}
+
+// TestArrivalFIFO checks that later departure never arrives earlier and arrival is continuous.
+func TestArrivalFIFO(t *testing.T) {
+	profiles := [][]float64{
+		{1, 0.2, 3, 0.5, 1, 1},
+		{0.1, 5},
+		append(repeat(1, 20), 0.3, 0.3, 0.9, 4),
+	}
+	for _, speeds := range profiles {
+		slotMinutes := minutesPerDay / len(speeds)
+		minSpeed, maxSpeed := math.Inf(1), 0.0
+		for _, speed := range speeds {
+			minSpeed, maxSpeed = math.Min(minSpeed, speed), math.Max(maxSpeed, speed)
+		}
+		for _, duration := range []float64{1, 17, 90, 600} {
+			previous := math.Inf(-1)
+			for departure := 0.0; departure < 2*minutesPerDay; departure += 0.5 {
+				arrival := Arrival(speeds, slotMinutes, departure, duration)
+				if arrival < previous || arrival <= departure {
+					t.Errorf("speeds %v: departure %v for %v minutes arrives at %v, previous arrival is %v",
+						speeds, departure, duration, arrival, previous)
+				}
+				// arrival grows not faster than ratio of speeds at departure and at arrival
+				if previous > math.Inf(-1) && arrival-previous > 0.5*maxSpeed/minSpeed+1e-9 {
+					t.Errorf("speeds %v: arrival jumps from %v to %v", speeds, previous, arrival)
+				}
+				previous = arrival
+			}
+		}
+	}
+}
+
+func TestProfileTravelTime(t *testing.T) {
+	p, err := Read(strings.NewReader(`{
+		"slot_minutes": 720,
+		"zones": [{"name": "center", "polygon": [[0, 0], [0, 10], [10, 10], [10, 0]]}],
+		"profiles": [
+			{"speeds": [0.5, 0.5]},
+			{"weekday": "0", "speeds": [2, 2]},
+			{"zone": "center", "speeds": [0.25, 0.25]},
+			{"from": 3, "to": "node/17", "speeds": [1, 1]},
+			{"from": 3, "to": "node/17", "weekday": "6", "speeds": [4, 4]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	center := p.ZoneOf(5, 5)
+	if center != 0 || p.ZoneOf(20, 5) != -1 {
+		t.Fatalf("ZoneOf = %d, %d, want 0, -1", center, p.ZoneOf(20, 5))
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		zone     int
+		weekday  string
+		want     int
+	}{
+		{"city", "1", "2", -1, "1", 60},
+		{"city of weekday", "1", "2", -1, "0", 15},
+		{"zone", "1", "2", center, "1", 120},
+		{"zone is preferred to weekday of city", "1", "2", center, "0", 120},
+		{"edge", "3", "node/17", center, "1", 30},
+		{"edge of weekday", "3", "node/17", center, "6", 8},
+		{"edge is directed", "node/17", "3", -1, "1", 60},
+	}
+	for _, test := range tests {
+		got := p.TravelTime(30, generic.LocationID(test.from), generic.LocationID(test.to), test.zone, test.weekday, 600)
+		if got != test.want {
+			t.Errorf("%s: TravelTime = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
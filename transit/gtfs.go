@@ -0,0 +1,260 @@
+// Package transit loads public transport timetables in GTFS format and computes earliest arrival times
+// with walking and transit by connection scan algorithm.
+package transit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feed is a timetable of one service day.
+type Feed struct {
+	Stops []Stop
+	// Connections are sorted by departure.
+	Connections []Connection
+	// Trips is a number of trips, connections refer to them by position.
+	Trips int
+}
+
+// Stop is a transit stop in geographic coordinates.
+type Stop struct {
+	ID   string
+	Name string
+	Lat  float64
+	Lng  float64
+}
+
+// Connection is a vehicle ride between two consecutive stops of a trip.
+type Connection struct {
+	// From and To are positions of stops in Feed.Stops.
+	From int
+	To   int
+	// Departure and Arrival are minutes since midnight of the service day, they may exceed 24 hours.
+	Departure int
+	Arrival   int
+	// Trip is a position of the trip in the feed.
+	Trip int
+}
+
+// weekdays are calendar.txt columns, index is day of week, 0 is Sunday.
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// LoadGTFS reads stops.txt, trips.txt, stop_times.txt and optional calendar.txt from folder.
+// If dayOfWeek is set ("0" is Sunday), only trips of services running that day according
+// to calendar.txt are loaded, exceptions of calendar_dates.txt are not taken into account.
+func LoadGTFS(dir string, dayOfWeek string) (*Feed, error) {
+	feed := &Feed{}
+	stops := make(map[string]int)
+	err := readTable(filepath.Join(dir, "stops.txt"), []string{"stop_id", "stop_lat", "stop_lon"}, func(row record) error {
+		lat, err := row.float("stop_lat")
+		if err != nil {
+			return err
+		}
+		lng, err := row.float("stop_lon")
+		if err != nil {
+			return err
+		}
+		stops[row.get("stop_id")] = len(feed.Stops)
+		feed.Stops = append(feed.Stops, Stop{ID: row.get("stop_id"), Name: row.get("stop_name"), Lat: lat, Lng: lng})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := activeServices(dir, dayOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	trips := make(map[string]int)
+	err = readTable(filepath.Join(dir, "trips.txt"), []string{"trip_id", "service_id"}, func(row record) error {
+		if services == nil || services[row.get("service_id")] {
+			trips[row.get("trip_id")] = len(trips)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stopTimes := make(map[int][]stopTime)
+	err = readTable(filepath.Join(dir, "stop_times.txt"),
+		[]string{"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence"}, func(row record) error {
+			trip, ok := trips[row.get("trip_id")]
+			if !ok {
+				return nil
+			}
+			stop, ok := stops[row.get("stop_id")]
+			if !ok {
+				return fmt.Errorf("unknown stop %q", row.get("stop_id"))
+			}
+			sequence, err := strconv.Atoi(row.get("stop_sequence"))
+			if err != nil {
+				return fmt.Errorf("invalid stop_sequence %q", row.get("stop_sequence"))
+			}
+			arrivalTime, departureTime := row.get("arrival_time"), row.get("departure_time")
+			if arrivalTime == "" && departureTime == "" {
+				// times of stops which are not timepoints may be omitted, trip passes them
+				return nil
+			}
+			if arrivalTime == "" {
+				arrivalTime = departureTime
+			}
+			if departureTime == "" {
+				departureTime = arrivalTime
+			}
+			arrival, err := parseTime(arrivalTime)
+			if err != nil {
+				return err
+			}
+			departure, err := parseTime(departureTime)
+			if err != nil {
+				return err
+			}
+			stopTimes[trip] = append(stopTimes[trip], stopTime{stop, sequence, arrival, departure})
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// connections are sorted after, so order of trips does not matter
+	for trip, times := range stopTimes {
+		sort.Slice(times, func(i, j int) bool { return times[i].sequence < times[j].sequence })
+		for i := 0; i < len(times)-1; i++ {
+			feed.Connections = append(feed.Connections, Connection{
+				From:      times[i].stop,
+				To:        times[i+1].stop,
+				Departure: times[i].departure,
+				Arrival:   times[i+1].arrival,
+				Trip:      trip,
+			})
+		}
+	}
+	feed.Trips = len(trips)
+	sort.Slice(feed.Connections, func(i, j int) bool {
+		a, b := feed.Connections[i], feed.Connections[j]
+		if a.Departure != b.Departure {
+			return a.Departure < b.Departure
+		}
+		if a.Trip != b.Trip {
+			return a.Trip < b.Trip
+		}
+		return a.From < b.From
+	})
+	return feed, nil
+}
+
+type stopTime struct {
+	stop      int
+	sequence  int
+	arrival   int
+	departure int
+}
+
+// activeServices returns services running on day of week or nil if all services are used.
+func activeServices(dir string, dayOfWeek string) (map[string]bool, error) {
+	if dayOfWeek == "" {
+		return nil, nil
+	}
+	day, err := strconv.Atoi(dayOfWeek)
+	if err != nil || day < 0 || day >= len(weekdays) {
+		return nil, fmt.Errorf("transit: invalid day of week %q", dayOfWeek)
+	}
+	path := filepath.Join(dir, "calendar.txt")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	services := make(map[string]bool)
+	err = readTable(path, []string{"service_id", weekdays[day]}, func(row record) error {
+		if row.get(weekdays[day]) == "1" {
+			services[row.get("service_id")] = true
+		}
+		return nil
+	})
+	return services, err
+}
+
+// parseTime parses HH:MM:SS to minutes, seconds are truncated.
+func parseTime(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// record is a CSV row with access by column name.
+type record struct {
+	columns map[string]int
+	values  []string
+}
+
+func (r record) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+func (r record) float(column string) (float64, error) {
+	value, err := strconv.ParseFloat(r.get(column), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", column, r.get(column))
+	}
+	return value, nil
+}
+
+// readTable reads GTFS file with header and calls visit for every row.
+func readTable(path string, required []string, visit func(row record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("transit: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("transit: %s: %v", path, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("transit: %s: column %s is missing", path, name)
+		}
+	}
+
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("transit: %s: %v", path, err)
+		}
+		if err := visit(record{columns, values}); err != nil {
+			return fmt.Errorf("transit: %s: line %d: %v", path, line, err)
+		}
+	}
+}
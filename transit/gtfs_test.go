@@ -0,0 +1,160 @@
+package transit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFeed has stops on the equator 0.01 degree (1112 meters) apart and stop D 167 meters north of C:
+//
+//	A --1112-- B --1112-- C --3336-- E
+//	                      |
+//	                      D
+//
+// Weekday trips T1 and T2 run A-B-C, T3 runs D-E, weekend trip T4 runs A-E.
+var testFeed = map[string]string{
+	"stops.txt": "\ufeffstop_id,stop_name,stop_lat,stop_lon\n" +
+		"A,Alpha,0,0\nB,Beta,0,0.01\nC,Gamma,0,0.02\nD,Delta,0.0015,0.02\nE,Epsilon,0,0.05\n",
+	"trips.txt": "route_id,service_id,trip_id\n" +
+		"R1,WEEK,T1\nR1,WEEK,T2\nR2,WEEK,T3\nR3,WEEKEND,T4\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"T1,08:10:00,08:10:00,C,3\nT1,08:00:00,08:00:00,A,1\nT1,08:05:00,08:05:30,B,2\n" +
+		"T2,08:20:00,08:20:00,A,1\nT2,,,B,2\nT2,08:30:00,,C,3\n" +
+		"T3,,08:15:00,D,1\nT3,08:30:00,08:30:00,E,2\n" +
+		"T4,08:00:00,08:00:00,A,1\nT4,08:10:00,08:10:00,E,2\n",
+	"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+		"WEEK,1,1,1,1,1,0,0,20200101,20301231\nWEEKEND,0,0,0,0,0,1,1,20200101,20301231\n",
+}
+
+// writeFeed writes files of GTFS feed to temporary folder, files are replaced by changes.
+func writeFeed(t *testing.T, changes map[string]string) (string, func()) {
+	dir, err := ioutil.TempDir("", "gtfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range testFeed {
+		if changed, ok := changes[name]; ok {
+			content = changed
+		}
+		if content == "" {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadGTFS(t *testing.T) {
+	dir, remove := writeFeed(t, nil)
+	defer remove()
+
+	tests := []struct {
+		name        string
+		dayOfWeek   string
+		trips       int
+		connections []Connection
+	}{
+		{"monday", "1", 3, []Connection{
+			{From: 0, To: 1, Departure: 480, Arrival: 485},
+			{From: 1, To: 2, Departure: 485, Arrival: 490},
+			{From: 3, To: 4, Departure: 495, Arrival: 510},
+			// stop B without times is passed
+			{From: 0, To: 2, Departure: 500, Arrival: 510},
+		}},
+		{"sunday", "0", 1, []Connection{{From: 0, To: 4, Departure: 480, Arrival: 490}}},
+		{"all services", "", 4, []Connection{
+			{From: 0, To: 1, Departure: 480, Arrival: 485},
+			{From: 0, To: 4, Departure: 480, Arrival: 490},
+			{From: 1, To: 2, Departure: 485, Arrival: 490},
+			{From: 3, To: 4, Departure: 495, Arrival: 510},
+			{From: 0, To: 2, Departure: 500, Arrival: 510},
+		}},
+	}
+	for _, test := range tests {
+		feed, err := LoadGTFS(dir, test.dayOfWeek)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(feed.Stops) != 5 || feed.Stops[0] != (Stop{ID: "A", Name: "Alpha", Lat: 0, Lng: 0}) {
+			t.Errorf("%s: stops %v", test.name, feed.Stops)
+		}
+		if feed.Trips != test.trips {
+			t.Errorf("%s: %d trips, want %d", test.name, feed.Trips, test.trips)
+		}
+		if len(feed.Connections) != len(test.connections) {
+			t.Errorf("%s: connections %v, want %v", test.name, feed.Connections, test.connections)
+			continue
+		}
+		for i, c := range feed.Connections {
+			want := test.connections[i]
+			// positions of trips depend on order of trips.txt only, they are not compared
+			if c.From != want.From || c.To != want.To || c.Departure != want.Departure || c.Arrival != want.Arrival {
+				t.Errorf("%s: connection %d is %+v, want %+v", test.name, i, c, want)
+			}
+		}
+	}
+}
+
+func TestLoadGTFSWithoutCalendar(t *testing.T) {
+	dir, remove := writeFeed(t, map[string]string{"calendar.txt": ""})
+	defer remove()
+	feed, err := LoadGTFS(dir, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Trips != 4 {
+		t.Errorf("%d trips, want all 4 trips", feed.Trips)
+	}
+}
+
+func TestLoadGTFSErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		changes   map[string]string
+		dayOfWeek string
+		err       string
+	}{
+		{"invalid day", nil, "7", `transit: invalid day of week "7"`},
+		{"missing stops", map[string]string{"stops.txt": ""}, "", "stops.txt: no such file"},
+		{"missing column", map[string]string{"stops.txt": "stop_id,stop_lat\nA,0\n"}, "",
+			"stops.txt: column stop_lon is missing"},
+		{"invalid coordinate", map[string]string{"stops.txt": "stop_id,stop_lat,stop_lon\nA,0,east\n"}, "",
+			`stops.txt: line 2: invalid stop_lon "east"`},
+		{"unknown stop", map[string]string{"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+			"T1,08:00:00,08:00:00,A,1\nT1,08:10:00,08:10:00,Z,2\n"}, "", `stop_times.txt: line 3: unknown stop "Z"`},
+		{"invalid time", map[string]string{"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+			"T1,8:00,8:00,A,1\n"}, "", `stop_times.txt: line 2: invalid time "8:00"`},
+		{"invalid sequence", map[string]string{"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+			"T1,08:00:00,08:00:00,A,first\n"}, "", `stop_times.txt: line 2: invalid stop_sequence "first"`},
+		{"missing calendar column", map[string]string{"calendar.txt": "service_id,monday\nWEEK,1\n"}, "0",
+			"calendar.txt: column sunday is missing"},
+	}
+	for _, test := range tests {
+		dir, remove := writeFeed(t, test.changes)
+		_, err := LoadGTFS(dir, test.dayOfWeek)
+		remove()
+		if err == nil || !strings.HasPrefix(err.Error(), "transit: ") || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := map[string]int{"00:00:00": 0, "08:05:59": 485, " 23:59:00": 1439, "25:10:00": 1510}
+	for value, want := range tests {
+		if got, err := parseTime(value); err != nil || got != want {
+			t.Errorf("parseTime(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "08:00", "a:00:00", "08:b:00"} {
+		if _, err := parseTime(value); err == nil {
+			t.Errorf("parseTime(%q) accepts invalid time", value)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package transit
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mukhinaks/fops/geo"
+	"github.com/mukhinaks/fops/spatial"
+)
+
+const (
+	// departureStep rounds departures up to cache arrival profiles, later departure never arrives earlier,
+	// so travel times are overestimated by less than the step.
+	departureStep = 5
+	// horizon limits scanned connections to the given number of minutes after departure.
+	horizon     = 6 * 60
+	unreachable = math.MaxInt32
+)
+
+// ProfileCacheBytes limits memory of cached arrival profiles of each router, a profile takes
+// 8 bytes per stop. Cache is cleared when it is full.
+var ProfileCacheBytes = 64 << 20
+
+// Router computes earliest arrival between locations by walking and transit rides, it is safe for concurrent use.
+// Locations and stops are compared in coordinates of dataset projection.
+type Router struct {
+	feed *Feed
+	// x and y are projected coordinates of stops.
+	x, y  []float64
+	index *spatial.KDTree
+	// footpaths are walking transfers between stops.
+	footpaths       [][]footpath
+	metersPerMinute float64
+	// maxWalk is in units of projection.
+	maxWalk float64
+
+	mu sync.Mutex
+	// profiles are arrival times at stops keyed by origin and rounded departure.
+	profiles map[profileKey][]int
+	// maxProfiles is a number of profiles in ProfileCacheBytes.
+	maxProfiles int
+}
+
+type footpath struct {
+	to       int
+	duration int
+}
+
+type profileKey struct {
+	x, y      float64
+	departure int
+}
+
+// NewRouter prepares feed for queries. Walking to, from and between stops is limited by maxWalk meters,
+// metersPerMinute is a walking speed in units of projection like speeds of other travel models.
+func NewRouter(feed *Feed, projection geo.Projection, metersPerMinute float64, maxWalk float64) *Router {
+	lats := make([]float64, len(feed.Stops))
+	lngs := make([]float64, len(feed.Stops))
+	for i, stop := range feed.Stops {
+		lats[i], lngs[i] = stop.Lat, stop.Lng
+	}
+	center := geo.LocalFor(lats, lngs)
+
+	r := &Router{
+		feed:            feed,
+		x:               make([]float64, len(feed.Stops)),
+		y:               make([]float64, len(feed.Stops)),
+		footpaths:       make([][]footpath, len(feed.Stops)),
+		metersPerMinute: metersPerMinute,
+		maxWalk:         maxWalk * geo.Scale(projection, center.CenterLat, center.CenterLng),
+		profiles:        make(map[profileKey][]int),
+		maxProfiles:     ProfileCacheBytes,
+	}
+	if len(feed.Stops) > 0 {
+		r.maxProfiles /= 8 * len(feed.Stops)
+	}
+	items := make([]spatial.Item, len(feed.Stops))
+	for i, stop := range feed.Stops {
+		r.x[i], r.y[i] = projection.Project(stop.Lat, stop.Lng)
+		items[i] = spatial.Item{ID: i, X: r.x[i], Y: r.y[i]}
+	}
+	r.index = spatial.NewKDTree(items)
+
+	for i := range feed.Stops {
+		for _, j := range r.index.Radius(r.x[i], r.y[i], r.maxWalk) {
+			if i != j {
+				r.footpaths[i] = append(r.footpaths[i], footpath{j, r.walkTime(r.x[i], r.y[i], r.x[j], r.y[j])})
+			}
+		}
+	}
+	return r
+}
+
+func (r *Router) walkTime(fromX float64, fromY float64, toX float64, toY float64) int {
+	return int(math.Hypot(toX-fromX, toY-fromY) / r.metersPerMinute)
+}
+
+// EarliestArrival returns arrival time at the target in minutes since midnight for departure
+// from the source at departure minutes since midnight. Walking all the way is used if it is faster.
+func (r *Router) EarliestArrival(fromX float64, fromY float64, toX float64, toY float64, departure int) int {
+	best := departure + r.walkTime(fromX, fromY, toX, toY)
+
+	rounded := (departure + departureStep - 1) / departureStep * departureStep
+	arrivals := r.profile(fromX, fromY, rounded)
+	for _, stop := range r.index.Radius(toX, toY, r.maxWalk) {
+		if arrivals[stop] == unreachable {
+			continue
+		}
+		if arrival := arrivals[stop] + r.walkTime(r.x[stop], r.y[stop], toX, toY); arrival < best {
+			best = arrival
+		}
+	}
+	return best
+}
+
+// profile returns earliest arrival at all stops computed by connection scan algorithm.
+func (r *Router) profile(x float64, y float64, departure int) []int {
+	key := profileKey{x, y, departure}
+	r.mu.Lock()
+	arrivals, ok := r.profiles[key]
+	r.mu.Unlock()
+	if ok {
+		return arrivals
+	}
+
+	arrivals = make([]int, len(r.feed.Stops))
+	for i := range arrivals {
+		arrivals[i] = unreachable
+	}
+	for _, stop := range r.index.Radius(x, y, r.maxWalk) {
+		arrivals[stop] = departure + r.walkTime(x, y, r.x[stop], r.y[stop])
+	}
+
+	connections := r.feed.Connections
+	reached := make([]bool, r.feed.Trips)
+	first := sort.Search(len(connections), func(i int) bool { return connections[i].Departure >= departure })
+	for _, c := range connections[first:] {
+		if c.Departure > departure+horizon {
+			break
+		}
+		if !reached[c.Trip] && arrivals[c.From] > c.Departure {
+			continue
+		}
+		reached[c.Trip] = true
+		if c.Arrival >= arrivals[c.To] {
+			continue
+		}
+		arrivals[c.To] = c.Arrival
+		for _, f := range r.footpaths[c.To] {
+			if c.Arrival+f.duration < arrivals[f.to] {
+				arrivals[f.to] = c.Arrival + f.duration
+			}
+		}
+	}
+
+	r.mu.Lock()
+	if len(r.profiles) >= r.maxProfiles {
+		r.profiles = make(map[profileKey][]int)
+	}
+	if r.maxProfiles > 0 {
+		r.profiles[key] = arrivals
+	}
+	r.mu.Unlock()
+	return arrivals
+}
@@ -0,0 +1,129 @@
+package transit
+
+import (
+	"testing"
+
+	"github.com/mukhinaks/fops/geo"
+)
+
+// walkingSpeed is 60 meters per minute, so that walking times of the test feed are easy to count.
+const walkingSpeed = 60
+
+func testRouter(t *testing.T, dayOfWeek string) (*Router, func(stop string) (float64, float64)) {
+	dir, remove := writeFeed(t, nil)
+	defer remove()
+	feed, err := LoadGTFS(dir, dayOfWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouter(feed, geo.Local{}, walkingSpeed, 300)
+	stops := make(map[string]int)
+	for i, stop := range feed.Stops {
+		stops[stop.ID] = i
+	}
+	location := func(stop string) (float64, float64) {
+		return r.x[stops[stop]], r.y[stops[stop]]
+	}
+	return r, location
+}
+
+func TestEarliestArrival(t *testing.T) {
+	walk := func(from [2]float64, to [2]float64) int {
+		return int(geo.Haversine(from[0], from[1], to[0], to[1]) / walkingSpeed)
+	}
+	tests := []struct {
+		name      string
+		dayOfWeek string
+		from, to  string
+		departure int
+		want      int
+	}{
+		{"ride", "1", "A", "C", 480, 490},
+		// departures are rounded up to 5 minutes, so T1 from 08:00 is missed
+		{"rounded departure", "1", "A", "C", 481, 510},
+		{"later trip", "1", "A", "C", 490, 510},
+		{"intermediate stop", "1", "A", "B", 478, 485},
+		{"transfer", "1", "A", "E", 480, 510},
+		{"weekend trip", "0", "A", "E", 480, 490},
+		{"all services", "", "A", "E", 480, 490},
+		{"walk after the last trip", "1", "A", "C", 540, 540 + walk([2]float64{0, 0}, [2]float64{0, 0.02})},
+		{"walk against trips", "1", "C", "A", 480, 480 + walk([2]float64{0, 0.02}, [2]float64{0, 0})},
+		{"walk is faster", "1", "C", "D", 480, 480 + walk([2]float64{0, 0.02}, [2]float64{0.0015, 0.02})},
+		{"no weekend trip", "0", "A", "C", 480, 480 + walk([2]float64{0, 0}, [2]float64{0, 0.02})},
+	}
+	for _, test := range tests {
+		r, location := testRouter(t, test.dayOfWeek)
+		fromX, fromY := location(test.from)
+		toX, toY := location(test.to)
+		if got := r.EarliestArrival(fromX, fromY, toX, toY, test.departure); got != test.want {
+			t.Errorf("%s: EarliestArrival from %s to %s at %d = %d, want %d", test.name, test.from, test.to,
+				test.departure, got, test.want)
+		}
+	}
+}
+
+func TestEarliestArrivalWalkToStops(t *testing.T) {
+	r, location := testRouter(t, "1")
+	// location 111 meters south of A walks 1 minute to A, location 111 meters south of E walks 1 minute from E
+	x, y := geo.Local{}.Project(-0.001, 0)
+	toX, toY := geo.Local{}.Project(-0.001, 0.05)
+	if got := r.EarliestArrival(x, y, toX, toY, 475); got != 511 {
+		t.Errorf("EarliestArrival with walks to and from stops = %d, want 511", got)
+	}
+	// location farther than maximum walk from stops walks all the way
+	farX, farY := geo.Local{}.Project(-0.01, 0)
+	cX, cY := location("C")
+	want := 475 + int(geo.Haversine(-0.01, 0, 0, 0.02)/walkingSpeed)
+	if got := r.EarliestArrival(farX, farY, cX, cY, 475); got != want {
+		t.Errorf("EarliestArrival of location far from stops = %d, want %d", got, want)
+	}
+}
+
+// TestEarliestArrivalFIFO checks that later departure never arrives earlier.
+func TestEarliestArrivalFIFO(t *testing.T) {
+	for _, day := range []string{"0", "1", ""} {
+		r, location := testRouter(t, day)
+		for _, from := range []string{"A", "B", "D"} {
+			for _, to := range []string{"C", "E"} {
+				fromX, fromY := location(from)
+				toX, toY := location(to)
+				previous := 0
+				for departure := 450; departure <= 560; departure++ {
+					arrival := r.EarliestArrival(fromX, fromY, toX, toY, departure)
+					if arrival < previous || arrival < departure {
+						t.Errorf("day %q: departure from %s to %s at %d arrives at %d, previous departure at %d",
+							day, from, to, departure, arrival, previous)
+					}
+					previous = arrival
+				}
+			}
+		}
+	}
+}
+
+func TestProfileCacheBytes(t *testing.T) {
+	defer func(limit int) { ProfileCacheBytes = limit }(ProfileCacheBytes)
+	// a profile of 5 stops takes 40 bytes
+	ProfileCacheBytes = 100
+	r, location := testRouter(t, "1")
+	if r.maxProfiles != 2 {
+		t.Fatalf("router keeps %d profiles, want 2", r.maxProfiles)
+	}
+	fromX, fromY := location("A")
+	toX, toY := location("C")
+	for _, departure := range []int{480, 485, 490, 495, 480} {
+		r.EarliestArrival(fromX, fromY, toX, toY, departure)
+		if len(r.profiles) > 2 {
+			t.Errorf("router cached %d profiles, limit is 2", len(r.profiles))
+		}
+	}
+	if got := r.EarliestArrival(fromX, fromY, toX, toY, 480); got != 490 {
+		t.Errorf("EarliestArrival = %d, want 490", got)
+	}
+
+	ProfileCacheBytes = 0
+	r, _ = testRouter(t, "1")
+	if r.EarliestArrival(fromX, fromY, toX, toY, 480); len(r.profiles) != 0 {
+		t.Errorf("router cached %d profiles without memory", len(r.profiles))
+	}
+}
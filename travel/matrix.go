@@ -0,0 +1,305 @@
+package travel
+
+import (
+	"bufio"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/geo"
+)
+
+// matrixMagic starts cache files of travel time matrices.
+const matrixMagic = "FOPSTTM1"
+
+// Matrix is a travel model with travel times between all dataset locations precomputed by another model.
+// Times are stored densely as minutes in uint16, longer times are saturated to math.MaxUint16.
+// Recently used matrices up to MatrixCacheBytes are shared by solvers of the process and matrices are
+// persisted to CacheDir if it is set, they are keyed by hash of model key, projection and location coordinates.
+type Matrix struct {
+	// Model computes travel times of the matrix and of locations which are not in the dataset.
+	Model generic.TravelModel
+	// Key identifies model and its parameters, e.g. travel mode and speed.
+	Key string
+	// CacheDir is a folder of cache files, nothing is persisted if it is empty.
+	CacheDir string
+
+	data *matrixData
+}
+
+type matrixData struct {
+	// index maps location coordinates to row of the matrix, locations with same coordinates share a row.
+	index map[[2]float64]int
+	size  int
+	times []uint16
+}
+
+// MatrixCacheBytes limits memory of matrices kept for reuse by other solvers, least recently used
+// matrices are dropped first. Matrices of running solvers are kept by them until they finish.
+var MatrixCacheBytes = 256 << 20
+
+// matrices is a least recently used cache of matrices, the front of the list is the most recent.
+// Matrices which are being computed or read are in loading, so that every matrix is prepared once.
+var matrices = struct {
+	sync.Mutex
+	byHash  map[string]*list.Element
+	recent  *list.List
+	bytes   int
+	loading map[string]*matrixLoad
+}{byHash: make(map[string]*list.Element), recent: list.New(), loading: make(map[string]*matrixLoad)}
+
+// matrixLoad is a matrix being prepared, done is closed when data or err is set.
+type matrixLoad struct {
+	done chan struct{}
+	data *matrixData
+	err  error
+}
+
+type cachedMatrix struct {
+	hash string
+	data *matrixData
+}
+
+// cachedMatrixData returns matrix of hash and marks it as recently used, matrices should be locked.
+func cachedMatrixData(hash string) (*matrixData, bool) {
+	element, ok := matrices.byHash[hash]
+	if !ok {
+		return nil, false
+	}
+	matrices.recent.MoveToFront(element)
+	return element.Value.(cachedMatrix).data, true
+}
+
+// cacheMatrixData adds matrix and drops least recently used matrices over MatrixCacheBytes,
+// matrices should be locked.
+func cacheMatrixData(hash string, data *matrixData) {
+	if data.bytes() > MatrixCacheBytes {
+		return
+	}
+	matrices.byHash[hash] = matrices.recent.PushFront(cachedMatrix{hash: hash, data: data})
+	matrices.bytes += data.bytes()
+	for matrices.bytes > MatrixCacheBytes {
+		oldest := matrices.recent.Back()
+		matrices.recent.Remove(oldest)
+		delete(matrices.byHash, oldest.Value.(cachedMatrix).hash)
+		matrices.bytes -= oldest.Value.(cachedMatrix).data.bytes()
+	}
+}
+
+func (data *matrixData) bytes() int {
+	return len(data.times) * 2
+}
+
+// Prepare computes travel times between locations or loads them from cache. Solvers which prepare
+// the same matrix at the same time wait for the first of them.
+func (m *Matrix) Prepare(solver *generic.Solver) error {
+	if model, ok := m.Model.(generic.PreparedTravelModel); ok {
+		if err := model.Prepare(solver); err != nil {
+			return err
+		}
+	}
+
+	locations := solver.Points.GetAllPoints()
+	index := make(map[[2]float64]int)
+	rows := make([]generic.Point, 0, len(locations))
+	for _, location := range locations {
+		x, y := location.Coordinates()
+		if _, ok := index[[2]float64{x, y}]; !ok {
+			index[[2]float64{x, y}] = len(rows)
+			rows = append(rows, location)
+		}
+	}
+	hash := m.hash(solver.Projection, rows)
+
+	matrices.Lock()
+	if data, ok := cachedMatrixData(hash); ok {
+		matrices.Unlock()
+		m.data = data
+		return nil
+	}
+	if load, ok := matrices.loading[hash]; ok {
+		matrices.Unlock()
+		<-load.done
+		if load.err != nil {
+			return load.err
+		}
+		m.data = load.data
+		return nil
+	}
+	load := &matrixLoad{done: make(chan struct{})}
+	matrices.loading[hash] = load
+	matrices.Unlock()
+
+	// matrix is computed without lock, solvers of other matrices are not blocked
+	load.data, load.err = m.load(hash, index, rows)
+
+	matrices.Lock()
+	delete(matrices.loading, hash)
+	if load.err == nil {
+		cacheMatrixData(hash, load.data)
+	}
+	matrices.Unlock()
+	close(load.done)
+
+	if load.err != nil {
+		return load.err
+	}
+	m.data = load.data
+	return nil
+}
+
+// load reads matrix from cache file or computes it and writes the file.
+func (m *Matrix) load(hash string, index map[[2]float64]int, rows []generic.Point) (*matrixData, error) {
+	data := &matrixData{index: index, size: len(rows)}
+	path := ""
+	if m.CacheDir != "" {
+		path = filepath.Join(m.CacheDir, "travel-"+hash+".bin")
+		// invalid cache file is replaced with computed matrix
+		if times, err := readMatrix(path, data.size); err == nil {
+			data.times = times
+			return data, nil
+		}
+	}
+
+	data.times = computeMatrix(m.Model, rows)
+	if path != "" {
+		if err := writeMatrix(path, data.size, data.times); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// TravelTime returns travel time in minutes.
+func (m *Matrix) TravelTime(from generic.Point, to generic.Point) int {
+	if m.data == nil {
+		return m.Model.TravelTime(from, to)
+	}
+	fromX, fromY := from.Coordinates()
+	toX, toY := to.Coordinates()
+	i, ok := m.data.index[[2]float64{fromX, fromY}]
+	if !ok {
+		return m.Model.TravelTime(from, to)
+	}
+	j, ok := m.data.index[[2]float64{toX, toY}]
+	if !ok {
+		return m.Model.TravelTime(from, to)
+	}
+	return int(m.data.times[i*m.data.size+j])
+}
+
+// Size returns number of rows of prepared matrix and its memory size in bytes, both are zero before Prepare.
+func (m *Matrix) Size() (rows int, bytes int) {
+	if m.data == nil {
+		return 0, 0
+	}
+	return m.data.size, m.data.bytes()
+}
+
+func (m *Matrix) hash(projection geo.Projection, rows []generic.Point) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %#v", m.Key, projection)
+	buffer := make([]byte, 8)
+	for _, location := range rows {
+		x, y := location.Coordinates()
+		binary.LittleEndian.PutUint64(buffer, math.Float64bits(x))
+		h.Write(buffer)
+		binary.LittleEndian.PutUint64(buffer, math.Float64bits(y))
+		h.Write(buffer)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// computeMatrix computes rows of the matrix by GOMAXPROCS workers.
+func computeMatrix(model generic.TravelModel, rows []generic.Point) []uint16 {
+	size := len(rows)
+	times := make([]uint16, size*size)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				for j := range rows {
+					if i == j {
+						continue
+					}
+					t := model.TravelTime(rows[i], rows[j])
+					if t > math.MaxUint16 {
+						t = math.MaxUint16
+					}
+					times[i*size+j] = uint16(t)
+				}
+			}
+		}()
+	}
+	for i := range rows {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return times
+}
+
+// readMatrix reads cache file: magic, uint32 size and size*size uint16 times in little endian.
+func readMatrix(path string, size int) ([]uint16, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	magic := make([]byte, len(matrixMagic))
+	var fileSize uint32
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != matrixMagic {
+		return nil, fmt.Errorf("travel: %s is not a travel time matrix", path)
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &fileSize); err != nil || int(fileSize) != size {
+		return nil, fmt.Errorf("travel: %s has wrong size", path)
+	}
+	times := make([]uint16, size*size)
+	if err := binary.Read(reader, binary.LittleEndian, times); err != nil {
+		return nil, fmt.Errorf("travel: %s: %v", path, err)
+	}
+	return times, nil
+}
+
+// writeMatrix writes cache file through temporary file, so that concurrent processes never read partial matrix.
+func writeMatrix(path string, size int, times []uint16) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("travel: %v", err)
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("travel: %v", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	writer.WriteString(matrixMagic)
+	binary.Write(writer, binary.LittleEndian, uint32(size))
+	binary.Write(writer, binary.LittleEndian, times)
+	err = writer.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("travel: %v", err)
+	}
+	return nil
+}
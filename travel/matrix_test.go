@@ -0,0 +1,198 @@
+package travel
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/points"
+)
+
+// samplePoints are locations on a square grid with step of 100 meters.
+type samplePoints []generic.Point
+
+func newSamplePoints(size int) samplePoints {
+	side := 1
+	for side*side < size {
+		side++
+	}
+	locations := make(samplePoints, size)
+	for i := range locations {
+		locations[i] = &points.BaseLocation{X: float64(i%side) * 100, Y: float64(i/side) * 100}
+	}
+	return locations
+}
+
+func (p samplePoints) Init(solver *generic.Solver) (generic.Points, error) { return p, nil }
+func (p samplePoints) GetAllPoints() []generic.Point                       { return p }
+func (p samplePoints) GetCurrentPoints() map[int]generic.Point             { return nil }
+func (p samplePoints) GetPointsInArea(startID int, endID int) map[int]generic.Point {
+	return nil
+}
+
+func TestMatrix(t *testing.T) {
+	locations := newSamplePoints(30)
+	model := Speed{MetersPerMinute: 50}
+	m := &Matrix{Model: model, Key: "test matrix"}
+	if err := m.Prepare(&generic.Solver{Points: locations}); err != nil {
+		t.Fatal(err)
+	}
+	for _, from := range locations {
+		for _, to := range locations {
+			if got, want := m.TravelTime(from, to), model.TravelTime(from, to); got != want {
+				t.Fatalf("TravelTime = %d, want %d of model", got, want)
+			}
+		}
+	}
+	outside := &points.BaseLocation{X: 12345, Y: 678}
+	if got, want := m.TravelTime(locations[0], outside), model.TravelTime(locations[0], outside); got != want {
+		t.Errorf("TravelTime to location outside of dataset = %d, want %d", got, want)
+	}
+}
+
+func TestMatrixCacheEviction(t *testing.T) {
+	defer func(limit int) { MatrixCacheBytes = limit }(MatrixCacheBytes)
+	// one matrix of 20 locations takes 800 bytes
+	MatrixCacheBytes = 2000
+	solver := &generic.Solver{Points: newSamplePoints(20)}
+	for i := 0; i < 5; i++ {
+		m := &Matrix{Model: Speed{MetersPerMinute: 50}, Key: fmt.Sprintf("eviction %d", i)}
+		if err := m.Prepare(solver); err != nil {
+			t.Fatal(err)
+		}
+		if rows, _ := m.Size(); rows != 20 {
+			t.Fatalf("matrix %d has %d rows, want 20", i, rows)
+		}
+	}
+	matrices.Lock()
+	defer matrices.Unlock()
+	if matrices.bytes > MatrixCacheBytes || matrices.recent.Len() != 2 || len(matrices.byHash) != 2 {
+		t.Errorf("cache keeps %d matrices of %d bytes, want 2 matrices up to %d bytes",
+			matrices.recent.Len(), matrices.bytes, MatrixCacheBytes)
+	}
+}
+
+// countingModel counts travel time queries, queries wait until release is closed if it is set.
+type countingModel struct {
+	Speed
+	queries *int64
+	release chan struct{}
+}
+
+func (c countingModel) TravelTime(from generic.Point, to generic.Point) int {
+	atomic.AddInt64(c.queries, 1)
+	if c.release != nil {
+		<-c.release
+	}
+	return c.Speed.TravelTime(from, to)
+}
+
+func TestMatrixPreparedOnce(t *testing.T) {
+	var queries int64
+	model := countingModel{Speed: Speed{MetersPerMinute: 50}, queries: &queries}
+	solver := &generic.Solver{Points: newSamplePoints(20)}
+	// key is unique, so matrix is not cached by previous runs
+	key := fmt.Sprintf("prepared once %d", time.Now().UnixNano())
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := &Matrix{Model: model, Key: key}
+			errs[i] = m.Prepare(solver)
+			if rows, _ := m.Size(); errs[i] == nil && rows != 20 {
+				errs[i] = fmt.Errorf("matrix has %d rows, want 20", rows)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if queries != 20*19 {
+		t.Errorf("model is queried %d times, want %d", queries, 20*19)
+	}
+}
+
+func TestMatrixPrepareConcurrently(t *testing.T) {
+	var queries int64
+	slow := countingModel{Speed: Speed{MetersPerMinute: 50}, queries: &queries, release: make(chan struct{})}
+	solver := &generic.Solver{Points: newSamplePoints(5)}
+	key := fmt.Sprintf("slow matrix %d", time.Now().UnixNano())
+	slowDone := make(chan error)
+	go func() {
+		slowDone <- (&Matrix{Model: slow, Key: key}).Prepare(solver)
+	}()
+	for atomic.LoadInt64(&queries) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// other matrix is prepared while the slow one is computed
+	done := make(chan error)
+	go func() {
+		done <- (&Matrix{Model: Speed{MetersPerMinute: 50}, Key: "fast matrix"}).Prepare(solver)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Error("matrix waits for computation of other matrix")
+	}
+	close(slow.release)
+	if err := <-slowDone; err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkMatrix(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		locations := newSamplePoints(size)
+		model := Speed{MetersPerMinute: 50}
+		solver := &generic.Solver{Points: locations}
+
+		b.Run(fmt.Sprintf("prepare-%d", size), func(b *testing.B) {
+			defer func(limit int) { MatrixCacheBytes = limit }(MatrixCacheBytes)
+			// matrices are not cached, so every iteration computes the matrix
+			MatrixCacheBytes = 0
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m := &Matrix{Model: model, Key: "benchmark"}
+				if err := m.Prepare(solver); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		m := &Matrix{Model: model, Key: "benchmark"}
+		if err := m.Prepare(solver); err != nil {
+			b.Fatal(err)
+		}
+		random := rand.New(rand.NewSource(1))
+		pairs := make([][2]generic.Point, 1024)
+		for i := range pairs {
+			pairs[i] = [2]generic.Point{locations[random.Intn(size)], locations[random.Intn(size)]}
+		}
+		for _, travel := range []struct {
+			name  string
+			model generic.TravelModel
+		}{{"model", model}, {"matrix", m}} {
+			b.Run(fmt.Sprintf("query-%s-%d", travel.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					pair := pairs[i%len(pairs)]
+					travel.model.TravelTime(pair[0], pair[1])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package travel
+
+import (
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/roads"
+)
+
+// Network is a travel mode of walking along streets of RoadNetworkPath.
+const Network = "network"
+
+// StreetWalk is a travel model with constant speed along shortest way in street network.
+type StreetWalk struct {
+	// Path is a street network file.
+	Path string
+	// Network is projected to coordinates of dataset locations by Prepare.
+	Network *roads.Network
+	// MetersPerMinute is a travel speed.
+	MetersPerMinute float64
+}
+
+// Prepare projects street network to coordinates of dataset locations.
+func (s *StreetWalk) Prepare(solver *generic.Solver) error {
+	network, err := roads.LoadNetwork(s.Path, solver.Projection)
+	if err != nil {
+		return err
+	}
+	s.Network = network
+	return nil
+}
+
+// TravelTime returns travel time in minutes.
+func (s *StreetWalk) TravelTime(from generic.Point, to generic.Point) int {
+	fromX, fromY := from.Coordinates()
+	toX, toY := to.Coordinates()
+	return int(s.Network.Distance(fromX, fromY, toX, toY) / s.MetersPerMinute)
+}
+
+func init() {
+	register(Network, func(config misc.Config) (generic.TravelModel, error) {
+		if config.RoadNetworkPath == "" {
+			return nil, misc.ConfigError{Field: "RoadNetworkPath", Message: "is required by network travel mode"}
+		}
+		// graph is read once per process, projection of dataset is known only in Prepare
+		if _, err := roads.LoadGraph(config.RoadNetworkPath); err != nil {
+			return nil, err
+		}
+		return &StreetWalk{Path: config.RoadNetworkPath, MetersPerMinute: config.WalkingSpeed}, nil
+	})
+}
@@ -0,0 +1,69 @@
+// Package travel provides travel models which compute travel time between locations.
+package travel
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/points"
+)
+
+// Travel modes with constant speed along straight line.
+const (
+	Walking = "walking"
+	Cycling = "cycling"
+	Driving = "driving"
+)
+
+// Speed is a travel model with constant speed along straight line between locations.
+type Speed struct {
+	// MetersPerMinute is a travel speed.
+	MetersPerMinute float64
+}
+
+// TravelTime returns travel time in minutes.
+func (s Speed) TravelTime(from generic.Point, to generic.Point) int {
+	return int(points.EuclidianDistance(from, to) / s.MetersPerMinute)
+}
+
+func init() {
+	register(Walking, func(config misc.Config) (generic.TravelModel, error) {
+		return Speed{MetersPerMinute: config.WalkingSpeed}, nil
+	})
+	register(Cycling, func(config misc.Config) (generic.TravelModel, error) {
+		return Speed{MetersPerMinute: config.CyclingSpeed}, nil
+	})
+	register(Driving, func(config misc.Config) (generic.TravelModel, error) {
+		return Speed{MetersPerMinute: config.DrivingSpeed}, nil
+	})
+}
+
+// register makes travel model available by name, it is wrapped in Matrix if TravelMatrix is enabled.
+// Time-dependent models are not wrapped, matrix has one travel time for a pair of locations.
+func register(name string, constructor generic.TravelModelConstructor) {
+	generic.RegisterTravelModel(name, func(config misc.Config) (generic.TravelModel, error) {
+		model, err := constructor(config)
+		if err != nil || !config.TravelMatrix {
+			return model, err
+		}
+		if _, ok := model.(generic.TimeDependentTravelModel); ok {
+			return model, nil
+		}
+		return &Matrix{Model: model, Key: matrixKey(name, config), CacheDir: config.TravelCacheDir}, nil
+	})
+}
+
+// matrixKey describes parameters of travel model which change travel times.
+func matrixKey(name string, config misc.Config) string {
+	key := fmt.Sprintf("%s %v %v %v", name, config.WalkingSpeed, config.CyclingSpeed, config.DrivingSpeed)
+	if name == Network {
+		// cached matrix is invalidated when network file changes
+		key += " " + config.RoadNetworkPath
+		if info, err := os.Stat(config.RoadNetworkPath); err == nil {
+			key += fmt.Sprintf(" %d %d", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return key
+}
@@ -0,0 +1,87 @@
+package travel
+
+import (
+	"sync"
+
+	"github.com/mukhinaks/fops/generic"
+	"github.com/mukhinaks/fops/misc"
+	"github.com/mukhinaks/fops/transit"
+)
+
+// Transit is a travel mode of walking and public transport rides by GTFS timetable of TransitPath.
+const Transit = "transit"
+
+// Timetable is a time-dependent travel model with earliest arrival by walking and transit.
+type Timetable struct {
+	Feed   *transit.Feed
+	Router *transit.Router
+	// MetersPerMinute is a walking speed.
+	MetersPerMinute float64
+	// MaxWalk is a maximum walking distance to, from and between stops in meters.
+	MaxWalk float64
+	// DepartureTime in HHMM format is used by TravelTime.
+	DepartureTime int
+}
+
+// Prepare projects stops to coordinates of dataset locations.
+func (t *Timetable) Prepare(solver *generic.Solver) error {
+	t.Router = transit.NewRouter(t.Feed, solver.Projection, t.MetersPerMinute, t.MaxWalk)
+	return nil
+}
+
+// TravelTime returns travel time in minutes for departure at DepartureTime.
+func (t *Timetable) TravelTime(from generic.Point, to generic.Point) int {
+	return t.TravelTimeAt(from, to, t.DepartureTime)
+}
+
+// TravelTimeAt returns travel time in minutes for departure at time of the day in HHMM format.
+// Walking time is returned until the model is prepared.
+func (t *Timetable) TravelTimeAt(from generic.Point, to generic.Point, departure int) int {
+	if t.Router == nil {
+		return Speed{MetersPerMinute: t.MetersPerMinute}.TravelTime(from, to)
+	}
+	fromX, fromY := from.Coordinates()
+	toX, toY := to.Coordinates()
+	minutes := departure/100*60 + departure%100
+	return t.Router.EarliestArrival(fromX, fromY, toX, toY, minutes) - minutes
+}
+
+var feeds = struct {
+	sync.Mutex
+	byDay map[[2]string]*transit.Feed
+}{byDay: make(map[[2]string]*transit.Feed)}
+
+// loadFeed loads GTFS feed once per process.
+func loadFeed(path string, dayOfWeek string) (*transit.Feed, error) {
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	key := [2]string{path, dayOfWeek}
+	if feed, ok := feeds.byDay[key]; ok {
+		return feed, nil
+	}
+	feed, err := transit.LoadGTFS(path, dayOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	feeds.byDay[key] = feed
+	return feed, nil
+}
+
+func init() {
+	register(Transit, func(config misc.Config) (generic.TravelModel, error) {
+		if config.TransitPath == "" {
+			return nil, misc.ConfigError{Field: "TransitPath", Message: "is required by transit travel mode"}
+		}
+		feed, err := loadFeed(config.TransitPath, config.TransitDayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+		return &Timetable{
+			Feed:            feed,
+			MetersPerMinute: config.WalkingSpeed,
+			MaxWalk:         config.TransitMaxWalk,
+			DepartureTime:   config.TransitDepartureTime,
+		}, nil
+	})
+}